@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	config "service-gateway/internal/configs"
+	"service-gateway/internal/fwauth"
+	"service-gateway/internal/store/mariadb"
+)
+
+const commandUsage = `usage:
+  gateway                         게이트웨이 기동
+  gateway migrate up              미적용 마이그레이션 반영
+  gateway migrate down [-steps N] 최근 N개(기본 1) 되돌림
+  gateway migrate status          버전별 적용 여부 출력
+  gateway seed [-biz A,B]         gateway.yaml routes → SID_* 카탈로그 적재
+  gateway fwauth issue -biz SMP [-ttl 5m] [-sub ID]
+                                  fw_auth primary 키로 FwAuthorization 발급`
+
+// runCommand: 서버 기동 대신 관리 명령을 수행하고 프로세스 종료 코드를 반환
+func runCommand(args []string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var err error
+	switch args[0] {
+	case "migrate":
+		err = runMigrate(ctx, args[1:])
+	case "seed":
+		err = runSeed(ctx, args[1:])
+	case "fwauth":
+		err = runFwAuth(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(commandUsage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", args[0], commandUsage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s failed: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing subcommand (up|down|status)")
+	}
+	sub := args[0]
+	fs := flag.NewFlagSet("migrate "+sub, flag.ContinueOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	db, err := mariadb.Open(dbConfigFromApp(slog.Default()))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := mariadb.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch sub {
+	case "up":
+		ran, err := m.Up(ctx)
+		for _, mig := range ran {
+			fmt.Printf("applied  %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(ran) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		ran, err := m.Down(ctx, *steps)
+		for _, mig := range ran {
+			fmt.Printf("reverted %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	case "status":
+		st, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range st {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", sub)
+	}
+}
+
+func runFwAuth(args []string) error {
+	if len(args) == 0 || args[0] != "issue" {
+		return fmt.Errorf("missing subcommand (issue)")
+	}
+	fs := flag.NewFlagSet("fwauth issue", flag.ContinueOnError)
+	biz := fs.String("biz", "", "BIZ_SRVC_CD the token is bound to")
+	ttl := fs.Duration("ttl", 5*time.Minute, "token lifetime")
+	sub := fs.String("sub", "", "optional subject")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *biz == "" {
+		return fmt.Errorf("-biz is required")
+	}
+
+	codec, err := fwAuthFromConfig()
+	if err != nil {
+		return err
+	}
+	tok, err := codec.Issue(fwauth.Claims{
+		BizSrvcCd: *biz,
+		Subject:   *sub,
+		ExpiresAt: time.Now().Add(*ttl).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(tok)
+	return nil
+}
+
+func runSeed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	biz := fs.String("biz", "", "comma separated BIZ_SRVC_CD to grant every seeded route")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	app := config.AppConfig
+	seed := mariadb.Seed{
+		GroupCode: app.Application.GroupCode,
+		GroupName: app.Application.Name,
+		LogSettings: []string{
+			app.Application.Log.Inbound.Request,
+			app.Application.Log.Inbound.Response,
+			app.Application.Log.Outbound.Request,
+			app.Application.Log.Outbound.Response,
+		},
+	}
+	for _, code := range strings.Split(*biz, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			seed.BizSrvcCds = append(seed.BizSrvcCds, code)
+		}
+	}
+	for _, r := range app.Routes {
+		path := r.Match.PathPattern
+		if path == "" {
+			path = r.Match.PathPrefix
+		}
+		scheme := r.Backend.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		seed.Routes = append(seed.Routes, mariadb.SeedRoute{
+			Name:      r.Name,
+			Path:      path,
+			TargetURI: scheme + "://" + r.Backend.Host,
+		})
+	}
+
+	db, err := mariadb.Open(dbConfigFromApp(slog.Default()))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	res, err := mariadb.ApplySeed(ctx, db, seed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("seeded group %s: %d inserted, %d updated\n", seed.GroupCode, res.Inserted, res.Updated)
+	return nil
+}
@@ -2,16 +2,31 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"service-gateway/internal/admin"
+	"service-gateway/internal/apikey"
+	"service-gateway/internal/audit"
+	"service-gateway/internal/fwauth"
 	"service-gateway/internal/gateway"
+	"service-gateway/internal/grpcgw"
 	"service-gateway/internal/httpx"
+	"service-gateway/internal/idempotency"
+	"service-gateway/internal/ipfilter"
 	"service-gateway/internal/kafkax"
+	"service-gateway/internal/logx"
+	"service-gateway/internal/maintenance"
+	"service-gateway/internal/masking"
 	"service-gateway/internal/middleware"
+	"service-gateway/internal/model"
 	"service-gateway/internal/observability"
 	"service-gateway/internal/router"
 	httpadapter "service-gateway/internal/router/adapter/http"
@@ -19,11 +34,16 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // distroless 이미지에 zoneinfo 가 없어 점검 시간대 타임존용으로 내장
 
 	config "service-gateway/internal/configs"
 	"service-gateway/internal/handlers"
+	"service-gateway/internal/session"
+	"service-gateway/internal/signing"
 	"service-gateway/internal/store"
 	"service-gateway/internal/store/mariadb"
+	"service-gateway/internal/tlsx"
+	pb "service-gateway/proto"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -32,6 +52,9 @@ import (
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func initTracer(ctx context.Context, cfg config.Config) (func(context.Context) error, error) {
@@ -95,14 +118,63 @@ func main() {
 
 	config.LoadConfig(confPath)
 
+	// 구조화 로그 (레벨은 관리 API PUT /admin/v1/log-level 로 런타임 변경)
+	lc := config.AppConfig.Logging
+	logger, logLevel, err := logx.New(logx.Config{Level: lc.Level, Format: lc.Format, AddSource: lc.AddSource}, os.Stdout)
+	if err != nil {
+		log.Fatalf("logging config: %v", err)
+	}
+	slog.SetDefault(logger) // 주입받지 않는 패키지 / 표준 log 출력도 동일 핸들러로
+
+	// 관리 명령(migrate/seed)은 서버 기동 없이 수행 후 종료
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
+	// 에러 응답 형식/언어/문구 번들
+	ec := config.AppConfig.Errors
+	if err := httpx.ConfigureErrors(httpx.ErrorConfig{Format: ec.Format, Lang: ec.Lang, BundleDir: ec.Messages, TimeZone: ec.TimeZone}); err != nil {
+		logger.Warn("error messages config, using built-in messages", "err", err)
+	}
+
+	// 민감정보 마스킹 (감사 로그 / 디버그 덤프)
+	mc := config.AppConfig.Masking
+	if err := masking.Configure(masking.Config{
+		Enabled:     mc.Enabled == nil || *mc.Enabled,
+		Replacement: mc.Replacement,
+		Headers:     mc.Headers,
+		FwFields:    mc.FwFields,
+		JSONPaths:   mc.JSONPaths,
+		Detectors:   mc.Detectors,
+	}); err != nil {
+		fatal("masking config", err)
+	}
+
+	// 접근 로그 (비활성 시 통과)
+	access := func(h http.Handler) http.Handler { return h }
+	if alc := config.AppConfig.AccessLog; alc.Enabled == nil || *alc.Enabled {
+		al, err := observability.NewAccessLog(observability.AccessLogConfig{
+			Format:     alc.Format,
+			Template:   alc.Template,
+			Output:     alc.Output,
+			SampleRate: alc.SampleRate,
+			Slow:       ms(alc.SlowMs),
+		})
+		if err != nil {
+			fatal("access log config", err)
+		}
+		defer al.Close()
+		access = al.Middleware
+	}
+
 	// 모니터링 연결
 	tp, err := initTracer(context.Background(), config.AppConfig)
 	if err != nil {
-		log.Fatalf("fail to initalize tracer : %v", err)
+		fatal("tracer init failed", err)
 	}
 	defer func() {
 		if err := tp(context.Background()); err != nil {
-			log.Printf("failed to shut down tracer provider: %v", err)
+			logger.Warn("tracer provider shutdown failed", "err", err)
 		}
 	}()
 
@@ -113,6 +185,10 @@ func main() {
 		for _, m := range r.Match.Methods {
 			methods[strings.ToUpper(m)] = struct{}{}
 		}
+		ipf, err := ipfilter.Parse(r.Options.IPAllow, r.Options.IPDeny)
+		if err != nil {
+			fatal("routes["+r.Name+"].options ip_allow/ip_deny", err)
+		}
 		routes = append(routes, router.Route{
 			Name: r.Name,
 			Match: router.Match{
@@ -126,30 +202,58 @@ func main() {
 				Method:      r.Backend.Method,
 				PathRewrite: r.Backend.PathRewrite,
 			},
+			Options: router.RouteOptions{
+				RequireSession:    r.Options.RequireSession,
+				GenerateIfMissing: r.Options.GenerateIfMissing,
+				Scopes:            r.Options.Scopes,
+				Claims:            r.Options.Claims,
+				IPFilter:          ipf,
+			},
 		})
 	}
 	// 기존
 	table := router.NewTable(routes)
 
 	// 2.5) DB 리포지토리 생성 (환경변수 기반)
-	repo, err := buildRepoFromConfig()
+	repo, err := buildRepoFromConfig(logger)
 	must(err)
 	defer repo.Close()
 
 	// 어댑터 / 핸들러 조합
+	// 왜: 라우트 / API 그룹별 업스트림 TLS(사설 CA, mTLS) + 커넥션 풀 공유
+	transport, err := upstreamTransport(logger)
+	if err != nil {
+		fatal("upstream tls config", err)
+	}
+	defer transport.CloseIdleConnections()
 	client := httpadapter.NewClient(
+		transport,
 		ms(config.AppConfig.Server.ReadTOms),
 		ms(config.AppConfig.Server.WriteTOms),
-		ms(config.AppConfig.Server.IdleTOms),
 	)
 
-	rproxy := &httpadapter.ReverseProxy{Client: client}
+	rproxy := &httpadapter.ReverseProxy{Client: client, Logger: logger.With("component", "proxy")}
+	// 왜: 제휴 백엔드 서명 요구 (routes[].backend.signing, 전송 직전 서명)
+	if rproxy.Signers, err = signersFromConfig(); err != nil {
+		fatal("routes signing config", err)
+	}
+	// 왜: 경로 변수 / 세션 ID 를 업스트림 JSON 본문으로 옮겨야 하는 라우트 (routes[].backend.body)
+	rproxy.Bodies = map[string]httpadapter.BodyMap{}
+	for _, r := range config.AppConfig.Routes {
+		bm, err := httpadapter.ParseBodyMap(r.Backend.Body)
+		if err != nil {
+			fatal("routes["+r.Name+"].backend.body", err)
+		}
+		if bm != nil {
+			rproxy.Bodies[r.Name] = bm
+		}
+	}
 
 	// Kafka Publisher 생성
 	kc := config.AppConfig.Kafka
 
 	if config.AppConfig.Application.Log.Topic == "" {
-		log.Fatal("application.log.topic is empty") // 미설정 조기 발견
+		fatal("application.log.topic is empty", nil) // 미설정 조기 발견
 	}
 
 	pub, err := kafkax.NewPublisher(kafkax.Config{
@@ -162,6 +266,11 @@ func main() {
 		Timeout:      time.Duration(kc.TimeoutMs) * time.Millisecond,
 		BatchBytes:   kc.BatchBytes,
 		BatchTimeout: time.Duration(kc.BatchTimeoutMs) * time.Millisecond,
+		BatchSize:    kc.BatchSize,
+		MaxInFlight:  kc.MaxInFlight,
+		DrainTimeout: time.Duration(kc.DrainTimeoutMs) * time.Millisecond,
+		Partitioner:  kc.Partitioner,
+		Logger:       logger,
 		SASL: struct {
 			Enabled   bool
 			Mechanism string
@@ -172,24 +281,102 @@ func main() {
 			Enabled            bool
 			InsecureSkipVerify bool
 		}{kc.TLS.Enabled, kc.TLS.InsecureSkipVerify},
+		Spool: kafkax.SpoolConfig{
+			Enabled:       kc.Spool.Enabled,
+			Dir:           kc.Spool.Dir,
+			SegmentBytes:  kc.Spool.SegmentBytes,
+			MaxBytes:      kc.Spool.MaxBytes,
+			Fsync:         kc.Spool.Fsync,
+			FsyncInterval: time.Duration(kc.Spool.FsyncIntervalMs) * time.Millisecond,
+		},
 	})
 	if err != nil {
-		log.Fatalf("kafka init failed: %v", err)
+		fatal("kafka init failed", err)
 	}
 	defer pub.Close()
 
+	// 로그 토픽 라우팅 (RasTyp / API 그룹 / 업무코드 → 토픽, 파티션 키)
+	var logRoutes []kafkax.Route
+	for _, rc := range kc.Routing.Rules {
+		logRoutes = append(logRoutes, kafkax.Route{
+			Match: kafkax.RouteMatch{RasTyp: rc.Match.RasTyp, ApiGroupCd: rc.Match.ApiGroupCd, BizSrvcCd: rc.Match.BizSrvcCd},
+			Topic: rc.Topic,
+			Key:   rc.Key,
+		})
+	}
+	logRouter, err := kafkax.NewRouter(logRoutes, kc.Routing.DefaultKey)
+	if err != nil {
+		fatal("kafka routing config", err)
+	}
+
 	mux := http.NewServeMux()
 
 	// health
 	mux.Handle("/sid/gateway/hello", observability.Healthz())
 
 	// === 신규: /gateway 등록 === 핸들러 생성에 주입 (타임아웃은 기존 설정 사용) kafka 추가
-	dyn := handlers.NewDynamicGateway(repo, 5*time.Second, pub)
+	// 감사 로그 Sink (application.log.sinks, 비어 있으면 kafka 단독)
+	var sinkCfgs []audit.SinkConfig
+	for _, sc := range config.AppConfig.Application.Log.Sinks {
+		sinkCfgs = append(sinkCfgs, audit.SinkConfig{
+			Type:          sc.Type,
+			Path:          sc.Path,
+			MaxSizeMB:     sc.MaxSizeMB,
+			MaxBackups:    sc.MaxBackups,
+			URL:           sc.URL,
+			Headers:       sc.Headers,
+			BatchSize:     sc.BatchSize,
+			FlushInterval: ms(sc.FlushIntervalMs),
+			Timeout:       ms(sc.TimeoutMs),
+		})
+	}
+	sink, err := audit.Build(sinkCfgs, pub)
+	if err != nil {
+		fatal("audit sink init failed", err)
+	}
+	defer sink.Close()
+
+	dyn := handlers.NewDynamicGateway(repo, 5*time.Second, sink)
+	dyn.Client.Transport = transport
+	dyn.Audit.Router = logRouter
+	dyn.Logger = logger.With("component", "gateway")
+	// API 별 마스킹 규칙(SID_API_EST_MNG MASK / MASK.<API_CD>) 캐시
+	dyn.Audit.Masks = masking.NewRegistry(repo, 30*time.Second)
+	// 점검 시간대(SID_API_MNT_WIN) 캐시: 관리 API 변경 시 즉시 무효화
+	dyn.Maintenance = maintenance.NewChecker(repo, 30*time.Second)
+	// X-Fw-Header FwAuthorization 검증 (DB 정책 체크 전 401)
+	if config.AppConfig.FwAuth.Enabled {
+		codec, err := fwAuthFromConfig()
+		if err != nil {
+			fatal("fw_auth config", err)
+		}
+		dyn.FwAuth = codec
+		dyn.FwAuthRequired = config.AppConfig.FwAuth.Required
+	}
+	// mTLS: 클라이언트 인증서 CN/SAN 에 묶인 BIZ_SRVC_CD 로 권한 체크
+	if tc := config.AppConfig.Server.TLS; tc.Enabled && len(tc.ClientIdentities) > 0 {
+		dyn.ClientCerts = tlsx.NewIdentityMap(tc.ClientIdentities)
+	}
+	// 업무서비스별 IP 허용/차단 (설정 + SID_BIZ_SRVC_IP_ACL, 관리 API 변경 시 캐시 무효화)
+	if fc := config.AppConfig.IPFilter; fc.Enabled {
+		static := map[string]*ipfilter.List{}
+		for cd, l := range fc.BizSrvc {
+			if static[cd], err = ipfilter.Parse(l.Allow, l.Deny); err != nil {
+				fatal("ip_filter.biz_srvc["+cd+"]", err)
+			}
+		}
+		dyn.IPFilter = ipfilter.NewRegistry(repo, static, ms(fc.CacheTTLMs))
+	}
+	// 업무서비스 API 키: 키에 묶인 BIZ_SRVC_CD 로 권한 체크 (관리 API 변경 시 캐시 무효화)
+	if kc := config.AppConfig.APIKeys; kc.Enabled {
+		dyn.APIKeys = apikey.NewResolver(repo, ms(kc.CacheTTLMs))
+		dyn.APIKeyRequired = kc.Required
+	}
 
 	// /gateway 및 하위 경로 모두 처리 (기존 동작 유지)
 	mux.HandleFunc("/gateway/", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost && r.Method != http.MethodGet {
-			httpx.WriteJSON(w, http.StatusMethodNotAllowed, httpx.NewError("method not allowed", nil))
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeMethodNotAllow, nil))
 			return
 		}
 		dyn.Post(w, r)
@@ -197,7 +384,7 @@ func main() {
 	// /gateway 단일 경로도 동일하게 처리
 	mux.HandleFunc("/gateway", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost && r.Method != http.MethodGet {
-			httpx.WriteJSON(w, http.StatusMethodNotAllowed, httpx.NewError("method not allowed", nil))
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeMethodNotAllow, nil))
 			return
 		}
 		dyn.Post(w, r)
@@ -208,7 +395,12 @@ func main() {
 		// 라우트 매칭 (PathPattern/Prefix + PathVariable)
 		rt, params := table.MatchRoute(r)
 		if rt == nil {
-			httpx.WriteJSON(w, http.StatusNotFound, httpx.NewError("route not found", nil))
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeApiNotFound, nil))
+			return
+		}
+		logx.SetRoute(r.Context(), rt.Name)
+		if ge := middleware.AuthorizeRoute(r.Context(), rt.Options); ge != nil {
+			httpx.WriteError(w, r, ge)
 			return
 		}
 
@@ -232,7 +424,7 @@ func main() {
 		// 원본 바디를 그대로 전달하는 업스트림 요청 생성
 		reqUp, err := http.NewRequestWithContext(ctx, upMethod, upstreamURL, r.Body)
 		if err != nil {
-			httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("build upstream request failed", err))
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeInternal, err))
 			return
 		}
 		// 안전한 헤더 전달 (Hop-by-Hop 제거)
@@ -246,7 +438,12 @@ func main() {
 	yamlFallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		rt, params := table.MatchRoute(r)
 		if rt == nil {
-			httpx.WriteJSON(w, http.StatusNotFound, httpx.NewError("route not found", nil))
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeApiNotFound, nil))
+			return
+		}
+		logx.SetRoute(r.Context(), rt.Name)
+		if ge := middleware.AuthorizeRoute(r.Context(), rt.Options); ge != nil {
+			httpx.WriteError(w, r, ge)
 			return
 		}
 		ctx := r.Context()
@@ -261,7 +458,7 @@ func main() {
 		}
 		reqUp, err := http.NewRequestWithContext(ctx, upMethod, upstreamURL, r.Body)
 		if err != nil {
-			httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("build upstream request failed", err))
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeInternal, err))
 			return
 		}
 		copyProxyHeaders(reqUp.Header, r.Header)
@@ -310,10 +507,76 @@ func main() {
 			maxBody = n
 		}
 	}
+	// 왜: 라우트 옵션(require_session / generate_if_missing) 기반 세션 확인·발급
+	// 멱등 가드 안쪽 → 재응답은 세션 서비스를 거치지 않음 (첫 응답의 X-Fw-Session-Id 가 그대로 재응답)
+	sess, closeSession, err := sessionFromConfig(table, client, logger)
+	if err != nil {
+		fatal("session config", err)
+	}
+	defer closeSession()
+	if sess != nil {
+		handler = sess.Middleware(handler)
+	}
+	// 왜: X-Fw-Header IdempotencyKey 존재 시 이중거래 차단 (본문 크기 제한 안쪽에서 본문 해시)
+	if ic := config.AppConfig.Idempotency; ic.Enabled {
+		var st idempotency.Store
+		switch ic.Store {
+		case "", "memory":
+			st = idempotency.NewMemoryStore()
+		default:
+			fatal("idempotency config", fmt.Errorf("idempotency.store: unknown %q (memory)", ic.Store))
+		}
+		handler = idempotency.New(st, idempotency.Config{
+			Methods: ic.Methods,
+			TTL:     ms(ic.TTLMs),
+			LockTTL: ms(ic.LockMs),
+			Wait:    ms(ic.WaitMs),
+			MaxBody: ic.MaxBodyBytes,
+			Identity: func(r *http.Request) string {
+				// 검증된 값만 범위로 사용 (X-Fw-Header / 바디의 BizSrvcCd 는 클라이언트 선언값)
+				id := dyn.CallerIdentity(r)
+				if sub, ok := middleware.ClaimsFrom(r.Context())["sub"].(string); ok && sub != "" {
+					id = strings.TrimPrefix(id+",sub:"+sub, ",")
+				}
+				if id == "" {
+					id = "ip:" + middleware.ClientIPFrom(r.Context()).String()
+				}
+				return id
+			},
+			Logger: logger,
+		}).Middleware(handler)
+	}
 	handler = middleware.BodyLimit(handler, maxBody)
 
-	// 왜: 원 클라이언트 컨텍스트(X-Forwarded-*) 보강(추적 ID와 목적이 다름)
-	handler = middleware.ProxyHeaders(handler)
+	// 왜: Bearer JWT 검증 + 클레임 헤더 전달 (FwHeaderTrace 안쪽 → 401 응답에도 TCID, 라우트별 scope/클레임은 매칭 후 AuthorizeRoute)
+	var jwt *middleware.JWTAuth // gRPC 리스너와 공유
+	if jc := config.AppConfig.JWT; jc.Enabled {
+		keys, err := middleware.NewJWKS(jc.JWKSFile, jc.JWKSURL, ms(jc.RefreshMs), nil, logger)
+		if err != nil {
+			fatal("jwt config", err)
+		}
+		defer keys.Close()
+		jwt, err = middleware.NewJWTAuth(middleware.JWTConfig{
+			Keys:          keys,
+			Issuer:        jc.Issuer,
+			Audience:      jc.Audience,
+			Leeway:        ms(jc.LeewayMs),
+			Required:      jc.Required,
+			ForwardClaims: jc.ForwardClaims,
+			Logger:        logger,
+		})
+		if err != nil {
+			fatal("jwt config", err)
+		}
+		handler = jwt.Middleware(handler)
+	}
+
+	// 왜: 브라우저 직접 호출 → preflight(OPTIONS) 는 JWT / 라우트 매칭(404·405) 전에 게이트웨이가 응답, 업스트림 미호출
+	if cors, err := corsFromConfig(table); err != nil {
+		fatal("cors config", err)
+	} else if cors != nil {
+		handler = cors.Middleware(handler)
+	}
 
 	// 왜: 상관관계 ID는 X-Fw-Header의 TCID로 통일. X-Request-Id는 생성/전파하지 않음.
 	bizCode := os.Getenv("FW_BIZ_CODE")
@@ -322,12 +585,27 @@ func main() {
 	}
 	handler = middleware.FwHeaderTrace(bizCode, handler)
 
-	// ...필요 시 JWT/RateLimit/CircuitBreaker 추가...
-	// handler = middleware.JWTAuth(handler)
-	// rl := middleware.NewRateLimiterFromEnv(); handler = rl.Middleware(handler)
+	// 왜: 전체 유입 한도 (middleware.ratelimit), gRPC 리스너와 같은 버킷
+	var rl *middleware.RateLimiter
+	if rc := config.AppConfig.Middleware.RateLimit; rc.Enabled {
+		rl = middleware.NewRateLimiter(rc.Rate, rc.Burst)
+		handler = rl.Middleware(handler)
+	}
+
+	// ...필요 시 CircuitBreaker 추가...
 	// cb := middleware.NewCircuitBreaker(5, 10*time.Second, 5*time.Second); handler = cb.Middleware(handler)
 
-	handler = observability.Logging(handler)
+	handler = access(handler)
+
+	// 왜: 원 클라이언트 컨텍스트(X-Forwarded-* / Forwarded) 보강(추적 ID와 목적이 다름), 신뢰 프록시만 전달 헤더 인정
+	// logx 바로 안쪽 → JWT 401 / CORS 403 / 유입 한도 429 로 끝나는 요청도 접근 로그 client_ip 가 실제 IP (LB 주소 아님)
+	trusted, err := ipfilter.ParsePrefixes(config.AppConfig.Server.TrustedProxies)
+	if err != nil {
+		fatal("server.trusted_proxies", err)
+	}
+	handler = middleware.ProxyHeaders(handler, trusted)
+	// 왜: 요청 단위 로그 속성(tcid/route) 보관 → 이후 모든 *Context 로그에 자동 포함
+	handler = logx.Middleware(handler)
 
 	srv := &http.Server{
 		Addr:         config.AppConfig.Server.Addr,
@@ -337,13 +615,108 @@ func main() {
 		IdleTimeout:  ms(config.AppConfig.Server.IdleTOms),
 	}
 
+	// 왜: TLS 종료(SNI 인증서 선택, 파일 변경 시 재적재) + 선택적 mTLS
+	if tc := config.AppConfig.Server.TLS; tc.Enabled {
+		certs := make([]tlsx.CertFiles, 0, len(tc.Certs))
+		for _, c := range tc.Certs {
+			certs = append(certs, tlsx.CertFiles{CertFile: c.CertFile, KeyFile: c.KeyFile, ServerNames: c.ServerNames})
+		}
+		ts, err := tlsx.NewServer(tlsx.ServerConfig{
+			Certs:        certs,
+			MinVersion:   tc.MinVersion,
+			CipherSuites: tc.CipherSuites,
+			ClientAuth:   tc.ClientAuth,
+			ClientCAFile: tc.ClientCAFile,
+			Reload:       ms(tc.ReloadMs),
+			Logger:       logger,
+		})
+		if err != nil {
+			fatal("server.tls config", err)
+		}
+		defer ts.Close()
+		srv.TLSConfig = ts.TLSConfig()
+	}
+
 	go func() {
-		log.Printf("gateway listening on %s", config.AppConfig.Server.Addr)
-		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("[Sevice-Gateway] server error: %v", err)
+		logger.Info("gateway listening", "addr", config.AppConfig.Server.Addr, "tls", srv.TLSConfig != nil)
+		var err error
+		if srv.TLSConfig != nil {
+			err = srv.ListenAndServeTLS("", "") // 인증서는 TLSConfig.GetCertificate
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal("gateway server error", err)
 		}
 	}()
 
+	// gRPC 리스너: HelloService / health / SessionService 프록시 (인증·요청 한도·감사 로그는 HTTP 와 공유)
+	var grpcSrv *grpcgw.Server
+	if gc := config.AppConfig.GRPCServer; gc.Enabled {
+		var closeGRPC func()
+		grpcSrv, closeGRPC, err = grpcServerFromConfig(grpcgw.Config{
+			JWT:            jwt,
+			JWTRequired:    config.AppConfig.JWT.Required,
+			Callers:        dyn,
+			Catalog:        dyn,
+			IPFilter:       dyn.IPFilter,
+			FwAuth:         dyn.FwAuth,
+			FwAuthRequired: dyn.FwAuthRequired,
+			RateLimit:      rl,
+			Audit:          dyn.Audit,
+			Logger:         logger.With("component", "grpc"),
+		}, srv.TLSConfig)
+		if err != nil {
+			fatal("grpc_server config", err)
+		}
+		defer closeGRPC()
+		lis, err := net.Listen("tcp", gc.Addr)
+		if err != nil {
+			fatal("grpc listen failed", err)
+		}
+		go func() {
+			logger.Info("grpc listening", "addr", gc.Addr, "tls", gc.TLS)
+			if err := grpcSrv.Serve(lis); err != nil {
+				fatal("grpc server error", err)
+			}
+		}()
+	}
+
+	// 관리 API: 별도 리스너 (/admin/v1)
+	var adminSrv *http.Server
+	if ac := config.AppConfig.Admin; ac.Enabled {
+		adminRepo, err := mariadb.NewAdmin(dbConfigFromApp(logger))
+		must(err)
+		defer adminRepo.Close()
+
+		var tokens []admin.Token
+		for _, t := range ac.Tokens {
+			tokens = append(tokens, admin.Token{Name: t.Name, Token: t.Token})
+		}
+		adm := admin.New(adminRepo, tokens, dyn.Maintenance, dyn.Audit.Masks)
+		if dyn.APIKeys != nil {
+			adm.AddInvalidator(dyn.APIKeys)
+		}
+		if dyn.IPFilter != nil {
+			adm.AddInvalidator(dyn.IPFilter)
+		}
+		adm.SetLogLevel(logLevel)
+
+		adminSrv = &http.Server{
+			Addr:         ac.Addr,
+			Handler:      logx.Middleware(access(middleware.FwHeaderTrace(bizCode, adm.Handler()))),
+			ReadTimeout:  ms(config.AppConfig.Server.ReadTOms),
+			WriteTimeout: ms(config.AppConfig.Server.WriteTOms),
+			IdleTimeout:  ms(config.AppConfig.Server.IdleTOms),
+		}
+		go func() {
+			logger.Info("admin api listening", "addr", ac.Addr)
+			if err := adminSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				fatal("admin server error", err)
+			}
+		}()
+	}
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	<-stop
@@ -351,7 +724,13 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	_ = srv.Shutdown(ctx)
-	log.Println("gateway stopped")
+	if grpcSrv != nil {
+		grpcSrv.GracefulStop(ctx)
+	}
+	if adminSrv != nil {
+		_ = adminSrv.Shutdown(ctx)
+	}
+	logger.Info("gateway stopped")
 
 }
 
@@ -366,38 +745,270 @@ func envOr(key, def string) string {
 
 func must(err error) {
 	if err != nil {
-		log.Fatal(err)
+		fatal("startup failed", err)
+	}
+}
+
+// fatal: 기동 실패 (구조화 로그 후 종료)
+func fatal(msg string, err error) {
+	if err != nil {
+		slog.Error(msg, "err", err)
+	} else {
+		slog.Error(msg)
 	}
+	os.Exit(1)
 }
 
 // 기존 buildRepoFromEnv 대신, config를 1순위로 사용하고, 없으면 ENV로 폴백
-func buildRepoFromConfig() (store.Repository, error) {
+func buildRepoFromConfig(logger *slog.Logger) (store.Repository, error) {
 
 	// 1) config.AppConfig.yaml 우선
 	enabled := config.AppConfig.DB.Enabled
 
 	if !enabled {
-		log.Println("DB disabled, using mock repository")
+		logger.Warn("DB disabled, using mock repository")
 		//return &MockRepository{}, nil
 	}
 
 	driver := config.AppConfig.DB.Driver
-	host := config.AppConfig.DB.Host
-	port := config.AppConfig.DB.Port
-	user := config.AppConfig.DB.User
-	password := config.AppConfig.DB.Password
-	name := config.AppConfig.DB.Name
 
 	switch driver {
 	case "mysql":
-		return mariadb.New(mariadb.Config{
-			Enabled: enabled, User: user, Password: password, Host: host, Port: port, DBName: name,
-		})
+		return mariadb.New(dbConfigFromApp(logger))
 	default:
 		return nil, fmt.Errorf("unsupported DB_DRIVER: %s", driver)
 	}
 }
 
+// gateway.yaml db 블록 → mariadb.Config (서버/관리 명령 공용)
+func dbConfigFromApp(logger *slog.Logger) mariadb.Config {
+	db := config.AppConfig.DB
+	return mariadb.Config{
+		Enabled: db.Enabled, User: db.User, Password: db.Password, Host: db.Host, Port: db.Port, DBName: db.Name,
+		Logger: logger,
+	}
+}
+
+// clientTLS: 업스트림 TLS 블록 (routes[].backend.tls, session.grpc.tls, grpc_server.session.tls 공용)
+func clientTLS(c *config.UpstreamTLS) (*tls.Config, error) {
+	return tlsx.ClientConfig{
+		CAFile:             c.CAFile,
+		CertFile:           c.CertFile,
+		KeyFile:            c.KeyFile,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         c.MinVersion,
+	}.Build()
+}
+
+// grpcServerFromConfig: grpc_server 블록 → 리스너 (SessionService 백엔드 연결은 closeFn 으로 정리)
+func grpcServerFromConfig(base grpcgw.Config, serverTLS *tls.Config) (srv *grpcgw.Server, closeFn func(), err error) {
+	gc := config.AppConfig.GRPCServer
+	closeFn = func() {}
+	if gc.TLS {
+		if serverTLS == nil {
+			return nil, nil, errors.New("grpc_server.tls: server.tls.enabled required")
+		}
+		base.TLS = serverTLS
+	}
+	base.Timeout = ms(gc.TimeoutMs)
+	if t := gc.Session.Target; t != "" {
+		creds := insecure.NewCredentials()
+		if gc.Session.TLS != nil {
+			tc, err := clientTLS(gc.Session.TLS)
+			if err != nil {
+				return nil, nil, fmt.Errorf("grpc_server.session.tls: %w", err)
+			}
+			creds = credentials.NewTLS(tc)
+		}
+		cc, err := grpc.NewClient(t, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, nil, fmt.Errorf("grpc_server.session.target: %w", err)
+		}
+		base.Session, base.SessionTarget = pb.NewSessionServiceClient(cc), t
+		closeFn = func() { _ = cc.Close() }
+	}
+	return grpcgw.New(base), closeFn, nil
+}
+
+// upstreamTransport: routes[].backend.tls / upstream_tls.groups → 공유 Transport 프로파일
+func upstreamTransport(logger *slog.Logger) (*httpadapter.Transport, error) {
+	tr := httpadapter.NewTransport(ms(config.AppConfig.Server.IdleTOms))
+	register := func(what, profile string, c *config.UpstreamTLS) error {
+		tc, err := clientTLS(c)
+		if err != nil {
+			return fmt.Errorf("%s: %w", what, err)
+		}
+		if c.InsecureSkipVerify {
+			logger.Warn("upstream tls verification disabled (dev only)", "target", what)
+		}
+		tr.SetTLS(profile, tc)
+		return nil
+	}
+	for _, r := range config.AppConfig.Routes {
+		if r.Backend.TLS != nil {
+			if err := register("routes["+r.Name+"].backend.tls", httpadapter.RouteProfile(r.Name), r.Backend.TLS); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for cd, c := range config.AppConfig.UpstreamTLS.Groups {
+		if err := register("upstream_tls.groups["+cd+"]", httpadapter.GroupProfile(cd), &c); err != nil {
+			return nil, err
+		}
+	}
+	return tr, nil
+}
+
+// signersFromConfig: routes[].backend.signing → 라우트 이름별 Signer
+func signersFromConfig() (map[string]signing.Signer, error) {
+	env := func(name, fallback string) string {
+		if name != "" {
+			return os.Getenv(name)
+		}
+		return fallback
+	}
+	out := map[string]signing.Signer{}
+	for _, r := range config.AppConfig.Routes {
+		sc := r.Backend.Signing
+		if sc == nil {
+			continue
+		}
+		var sg signing.Signer
+		var err error
+		switch strings.ToLower(sc.Type) {
+		case "hmac":
+			sg, err = signing.NewHMAC(sc.KeyID, []byte(env(sc.SecretEnv, sc.Secret)), sc.Algorithm, signing.HMACHeaders{
+				Signature: sc.Headers["signature"],
+				KeyID:     sc.Headers["key_id"],
+				Date:      sc.Headers["date"],
+				Digest:    sc.Headers["digest"],
+			})
+		case "sigv4":
+			sg, err = signing.NewSigV4(env(sc.AccessKeyEnv, sc.AccessKey), env(sc.SecretKeyEnv, ""),
+				env(sc.SessionTokenEnv, ""), sc.Region, sc.Service)
+		default:
+			err = fmt.Errorf("unknown type %q (hmac|sigv4)", sc.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("routes[%s].backend.signing: %w", r.Name, err)
+		}
+		out[r.Name] = sg
+	}
+	return out, nil
+}
+
+// sessionFromConfig: session.enabled 가 아니면 nil (세션 옵션이 있는 라우트는 경고만), closeFn 은 종료 시 연결 정리
+func sessionFromConfig(table *router.Table, client *http.Client, logger *slog.Logger) (sess *middleware.Session, closeFn func(), err error) {
+	closeFn = func() {}
+	sc := config.AppConfig.Session
+	if !sc.Enabled {
+		for _, r := range config.AppConfig.Routes {
+			if r.Options.RequireSession || r.Options.GenerateIfMissing {
+				logger.Warn("session disabled, route session options ignored", "route", r.Name)
+			}
+		}
+		return nil, closeFn, nil
+	}
+	var st session.Store
+	switch strings.ToLower(sc.Backend) {
+	case "", "http":
+		st, err = session.NewHTTPStore(client, sc.HTTP.BaseURL, sc.HTTP.GetPath, sc.HTTP.CreatePath, ms(sc.TimeoutMs))
+	case "grpc":
+		gc := sc.GRPC
+		var tc *tls.Config
+		if gc.TLS != nil {
+			if tc, err = clientTLS(gc.TLS); err != nil {
+				return nil, nil, fmt.Errorf("session.grpc.tls: %w", err)
+			}
+		}
+		var gs *session.GRPCStore
+		gs, err = session.NewGRPCStore(session.GRPCConfig{
+			Target:   gc.Target,
+			PoolSize: gc.PoolSize,
+			Timeout:  ms(sc.TimeoutMs),
+			Retries:  gc.Retries,
+			Backoff:  ms(gc.BackoffMs),
+			TLS:      tc,
+		})
+		if err == nil {
+			st, closeFn = gs, func() { _ = gs.Close() }
+		}
+	default:
+		err = fmt.Errorf("session.backend: unknown %q (http|grpc)", sc.Backend)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return middleware.NewSession(middleware.SessionConfig{
+		Store:  st,
+		TTL:    ms(sc.TTLMs),
+		Logger: logger.With("component", "session"),
+	}, func(r *http.Request) *router.RouteOptions {
+		if rt, _ := table.MatchRoute(r); rt != nil {
+			return &rt.Options
+		}
+		return nil
+	}), closeFn, nil
+}
+
+// corsFromConfig: 전역 cors(enabled) + routes[].options.cors, 둘 다 없으면 nil
+func corsFromConfig(table *router.Table) (*middleware.CORS, error) {
+	policy := func(c config.CORS) *middleware.CORSPolicy {
+		return &middleware.CORSPolicy{
+			AllowedOrigins:   c.AllowedOrigins,
+			AllowedMethods:   c.AllowedMethods,
+			AllowedHeaders:   c.AllowedHeaders,
+			ExposedHeaders:   c.ExposedHeaders,
+			AllowCredentials: c.AllowCredentials,
+			MaxAge:           ms(c.MaxAgeMs),
+		}
+	}
+	var global *middleware.CORSPolicy
+	if gc := config.AppConfig.CORS; gc.Enabled {
+		global = policy(gc.CORS)
+	}
+	routes := map[string]*middleware.CORSPolicy{}
+	for _, r := range config.AppConfig.Routes {
+		if r.Options.CORS != nil {
+			routes[r.Name] = policy(*r.Options.CORS)
+		}
+	}
+	if global == nil && len(routes) == 0 {
+		return nil, nil
+	}
+	return middleware.NewCORS(global, routes, func(r *http.Request) string {
+		if rt, _ := table.MatchRoute(r); rt != nil {
+			return rt.Name
+		}
+		return ""
+	})
+}
+
+// fwAuthFromConfig: fw_auth 블록 → 발급/검증 Codec (gateway fwauth issue 와 공용)
+func fwAuthFromConfig() (*fwauth.Codec, error) {
+	fc := config.AppConfig.FwAuth
+	keys := make([]fwauth.Key, 0, len(fc.Keys))
+	for _, k := range fc.Keys {
+		raw := k.Secret
+		if k.SecretEnv != "" {
+			raw = os.Getenv(k.SecretEnv)
+		}
+		secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(raw))
+		if err != nil || len(secret) == 0 {
+			return nil, fmt.Errorf("fw_auth.keys[%s]: secret must be base64", k.ID)
+		}
+		key := fwauth.Key{ID: k.ID, Secret: secret}
+		if k.NotAfter != "" {
+			if key.NotAfter, err = time.Parse(time.RFC3339, k.NotAfter); err != nil {
+				return nil, fmt.Errorf("fw_auth.keys[%s].not_after: %w", k.ID, err)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return fwauth.New(fc.Scheme, keys, fc.Primary, ms(fc.ClockSkewMs))
+}
+
 // 파일 하단에 유틸 추가
 func copyProxyHeaders(dst, src http.Header) {
 	// Hop-by-Hop 헤더 제거
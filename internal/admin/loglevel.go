@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"service-gateway/internal/logx"
+)
+
+// 런타임 로그 레벨 (DB 변경이 아니므로 감사 이력 대신 로그로 남김)
+
+type logLevelBody struct {
+	Level string `json:"level"`
+}
+
+// SetLogLevel: main 에서 만든 LevelVar 연결 (미연결 시 /admin/v1/log-level 은 404)
+func (s *Server) SetLogLevel(lv *slog.LevelVar) {
+	s.logLevel = lv
+}
+
+func (s *Server) getLogLevel(w http.ResponseWriter, r *http.Request) {
+	if s.logLevel == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.respond(w, r, http.StatusOK, logLevelBody{Level: s.logLevel.Level().String()}, nil)
+}
+
+func (s *Server) putLogLevel(w http.ResponseWriter, r *http.Request) {
+	if s.logLevel == nil {
+		http.NotFound(w, r)
+		return
+	}
+	var in logLevelBody
+	if !decode(w, r, &in) {
+		return
+	}
+	lvl, err := logx.ParseLevel(in.Level)
+	if err != nil {
+		s.respond(w, r, 0, nil, invalid("level", "%v", err))
+		return
+	}
+	prev := s.logLevel.Level()
+	s.logLevel.Set(lvl)
+	slog.InfoContext(r.Context(), "log level changed", "from", prev.String(), "to", lvl.String(), "actor", actorName(r.Context()))
+	s.respond(w, r, http.StatusOK, logLevelBody{Level: lvl.String()}, nil)
+}
@@ -0,0 +1,452 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log/slog"
+	"net"
+	"net/http"
+	"service-gateway/internal/apikey"
+	"service-gateway/internal/header"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+관리 API (/admin/v1)
+
+WHY:
+- 운영자가 SID_* 테이블을 SQL 로 직접 수정하던 작업(API 오픈, 그룹 거래통제, BIZ_SRVC_CD 권한 부여)을
+  검증/감사 이력이 남는 REST API 로 대체.
+- 게이트웨이 트래픽 리스너와 분리된 별도 리스너에서만 노출 (외부 노출 방지).
+
+인증:
+- Authorization: Bearer <token>  (admin.tokens 에 등록된 토큰, 이름이 감사 이력의 ACTOR)
+
+변경 후:
+- 등록된 Invalidator 전부 호출 → 프로세스 내 캐시가 다음 요청에서 DB 를 다시 읽도록
+*/
+
+// Invalidator: 카탈로그 변경 시 비워야 하는 프로세스 내 캐시
+type Invalidator interface {
+	Invalidate()
+}
+
+// InvalidatorFunc: 함수 → Invalidator 어댑터
+type InvalidatorFunc func()
+
+func (f InvalidatorFunc) Invalidate() { f() }
+
+type Token struct {
+	Name  string
+	Token string
+}
+
+type Server struct {
+	repo         store.AdminRepository
+	tokens       []Token
+	invalidators []Invalidator
+	logLevel     *slog.LevelVar
+}
+
+func New(repo store.AdminRepository, tokens []Token, invalidators ...Invalidator) *Server {
+	return &Server{repo: repo, tokens: tokens, invalidators: invalidators}
+}
+
+// AddInvalidator: 기동 이후 생성되는 캐시 등록용
+func (s *Server) AddInvalidator(inv Invalidator) {
+	s.invalidators = append(s.invalidators, inv)
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /admin/v1/groups", s.listGroups)
+	mux.HandleFunc("POST /admin/v1/groups", s.createGroup)
+	mux.HandleFunc("GET /admin/v1/groups/{groupCd}", s.getGroup)
+	mux.HandleFunc("PUT /admin/v1/groups/{groupCd}", s.updateGroup)
+	mux.HandleFunc("DELETE /admin/v1/groups/{groupCd}", s.deleteGroup)
+
+	mux.HandleFunc("GET /admin/v1/apis", s.listApis)
+	mux.HandleFunc("POST /admin/v1/apis", s.createApi)
+	mux.HandleFunc("GET /admin/v1/apis/{groupCd}/{apiCd}", s.getApi)
+	mux.HandleFunc("PUT /admin/v1/apis/{groupCd}/{apiCd}", s.updateApi)
+	mux.HandleFunc("DELETE /admin/v1/apis/{groupCd}/{apiCd}", s.deleteApi)
+
+	mux.HandleFunc("GET /admin/v1/permissions", s.listPermissions)
+	mux.HandleFunc("POST /admin/v1/permissions", s.grantPermission)
+	mux.HandleFunc("DELETE /admin/v1/permissions/{bizSrvcCd}/{groupCd}/{apiCd}", s.revokePermission)
+
+	mux.HandleFunc("GET /admin/v1/log-settings", s.listSettings)
+	mux.HandleFunc("PUT /admin/v1/log-settings", s.putSetting)
+	mux.HandleFunc("DELETE /admin/v1/log-settings/{groupCd}/{key}/{value}", s.deleteSetting)
+
+	mux.HandleFunc("GET /admin/v1/maintenance-windows", s.listMaintenanceWindows)
+	mux.HandleFunc("POST /admin/v1/maintenance-windows", s.createMaintenanceWindow)
+	mux.HandleFunc("DELETE /admin/v1/maintenance-windows/{winId}", s.deleteMaintenanceWindow)
+
+	mux.HandleFunc("GET /admin/v1/api-keys", s.listApiKeys)
+	mux.HandleFunc("POST /admin/v1/api-keys", s.createApiKey)
+	mux.HandleFunc("DELETE /admin/v1/api-keys/{keyId}", s.revokeApiKey)
+
+	mux.HandleFunc("GET /admin/v1/ip-acls", s.listIPAcls)
+	mux.HandleFunc("POST /admin/v1/ip-acls", s.createIPAcl)
+	mux.HandleFunc("DELETE /admin/v1/ip-acls/{aclId}", s.deleteIPAcl)
+
+	mux.HandleFunc("GET /admin/v1/audit", s.listAudit)
+
+	// 런타임 로그 레벨 (debug | info | warn | error)
+	mux.HandleFunc("GET /admin/v1/log-level", s.getLogLevel)
+	mux.HandleFunc("PUT /admin/v1/log-level", s.putLogLevel)
+
+	// 런타임 지표 (expvar: kafkax 발행/드롭/스풀 카운트 등)
+	mux.Handle("GET /admin/v1/metrics", expvar.Handler())
+
+	return s.auth(mux)
+}
+
+// auth: Bearer 토큰 검증 (상수 시간 비교)
+func (s *Server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw := r.Header.Get("Authorization")
+		tok, ok := strings.CutPrefix(raw, "Bearer ")
+		if !ok || tok == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeUnauthorized, nil))
+			return
+		}
+		name := ""
+		for _, t := range s.tokens {
+			if t.Token != "" && subtle.ConstantTimeCompare([]byte(t.Token), []byte(tok)) == 1 {
+				name = t.Name
+			}
+		}
+		if name == "" {
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeUnauthorized, nil))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withActorName(r.Context(), name)))
+	})
+}
+
+// ==== groups ====
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListGroups(r.Context())
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.GetGroup(r.Context(), r.PathValue("groupCd"))
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) createGroup(w http.ResponseWriter, r *http.Request) {
+	var g model.ApiGroup
+	if !decode(w, r, &g) {
+		return
+	}
+	if err := validateGroup(&g); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	err := s.repo.CreateGroup(r.Context(), g, actorOf(r))
+	s.mutated(w, r, http.StatusCreated, g, err)
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	var g model.ApiGroup
+	if !decode(w, r, &g) {
+		return
+	}
+	g.ApiGroupCode = r.PathValue("groupCd")
+	if err := validateGroup(&g); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	err := s.repo.UpdateGroup(r.Context(), g, actorOf(r))
+	s.mutated(w, r, http.StatusOK, g, err)
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	err := s.repo.DeleteGroup(r.Context(), r.PathValue("groupCd"), actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== apis ====
+
+func (s *Server) listApis(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListApis(r.Context(), r.URL.Query().Get("apiGroupCd"))
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) getApi(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.GetApi(r.Context(), r.PathValue("groupCd"), r.PathValue("apiCd"))
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) createApi(w http.ResponseWriter, r *http.Request) {
+	var a model.ApiDetail
+	if !decode(w, r, &a) {
+		return
+	}
+	if err := validateApi(&a); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	if _, err := s.repo.GetGroup(r.Context(), a.ApiGroupCode); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			err = invalid("apiGroupCd", "group %s does not exist", a.ApiGroupCode)
+		}
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	err := s.repo.CreateApi(r.Context(), a, actorOf(r))
+	s.mutated(w, r, http.StatusCreated, a, err)
+}
+
+func (s *Server) updateApi(w http.ResponseWriter, r *http.Request) {
+	var a model.ApiDetail
+	if !decode(w, r, &a) {
+		return
+	}
+	a.ApiGroupCode, a.ApiCode = r.PathValue("groupCd"), r.PathValue("apiCd")
+	if err := validateApi(&a); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	err := s.repo.UpdateApi(r.Context(), a, actorOf(r))
+	s.mutated(w, r, http.StatusOK, a, err)
+}
+
+func (s *Server) deleteApi(w http.ResponseWriter, r *http.Request) {
+	err := s.repo.DeleteApi(r.Context(), r.PathValue("groupCd"), r.PathValue("apiCd"), actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== permissions ====
+
+func (s *Server) listPermissions(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListPermissions(r.Context(), r.URL.Query().Get("bizSrvcCd"))
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) grantPermission(w http.ResponseWriter, r *http.Request) {
+	var p model.BizSrvcPermission
+	if !decode(w, r, &p) {
+		return
+	}
+	if err := validatePermission(&p); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	err := s.repo.GrantPermission(r.Context(), p, actorOf(r))
+	p.UseYn = "Y"
+	s.mutated(w, r, http.StatusOK, p, err)
+}
+
+func (s *Server) revokePermission(w http.ResponseWriter, r *http.Request) {
+	err := s.repo.RevokePermission(r.Context(), r.PathValue("bizSrvcCd"), r.PathValue("groupCd"), r.PathValue("apiCd"), actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== log settings (SID_API_EST_MNG) ====
+
+func (s *Server) listSettings(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListSettings(r.Context(), r.URL.Query().Get("apiGroupCd"))
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) putSetting(w http.ResponseWriter, r *http.Request) {
+	var st model.ApiSetting
+	if !decode(w, r, &st) {
+		return
+	}
+	if err := validateSetting(&st); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	err := s.repo.PutSetting(r.Context(), st, actorOf(r))
+	s.mutated(w, r, http.StatusOK, st, err)
+}
+
+func (s *Server) deleteSetting(w http.ResponseWriter, r *http.Request) {
+	err := s.repo.DeleteSetting(r.Context(), r.PathValue("groupCd"), r.PathValue("key"), r.PathValue("value"), actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== maintenance windows (SID_API_MNT_WIN) ====
+
+func (s *Server) listMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListMaintenanceWindows(r.Context())
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) createMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	var mw model.MaintenanceWindow
+	if !decode(w, r, &mw) {
+		return
+	}
+	if err := validateMaintenanceWindow(&mw); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	id, err := s.repo.CreateMaintenanceWindow(r.Context(), mw, actorOf(r))
+	mw.ID = id
+	s.mutated(w, r, http.StatusCreated, mw, err)
+}
+
+func (s *Server) deleteMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("winId"), 10, 64)
+	if err != nil {
+		s.respond(w, r, 0, nil, invalid("winId", "must be a number"))
+		return
+	}
+	err = s.repo.DeleteMaintenanceWindow(r.Context(), id, actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== api keys (SID_BIZ_SRVC_API_KEY) ====
+
+func (s *Server) listApiKeys(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListApiKeys(r.Context(), r.URL.Query().Get("bizSrvcCd"))
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+// createdApiKey: 원문 키는 발급 응답에서만 노출
+type createdApiKey struct {
+	model.ApiKey
+	Plain string `json:"apiKey"`
+}
+
+func (s *Server) createApiKey(w http.ResponseWriter, r *http.Request) {
+	var k model.ApiKey
+	if !decode(w, r, &k) {
+		return
+	}
+	if err := validateApiKey(&k); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	plain, keyID, hash, err := apikey.Generate()
+	if err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	k.KeyID, k.Hash, k.UseYn, k.CreatedAt = keyID, hash, "Y", time.Now()
+	err = s.repo.CreateApiKey(r.Context(), k, actorOf(r))
+	s.mutated(w, r, http.StatusCreated, createdApiKey{ApiKey: k, Plain: plain}, err)
+}
+
+func (s *Server) revokeApiKey(w http.ResponseWriter, r *http.Request) {
+	err := s.repo.RevokeApiKey(r.Context(), r.PathValue("keyId"), actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== ip acls (SID_BIZ_SRVC_IP_ACL) ====
+
+func (s *Server) listIPAcls(w http.ResponseWriter, r *http.Request) {
+	out, err := s.repo.ListIPAcls(r.Context())
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+func (s *Server) createIPAcl(w http.ResponseWriter, r *http.Request) {
+	var a model.IPAcl
+	if !decode(w, r, &a) {
+		return
+	}
+	if err := validateIPAcl(&a); err != nil {
+		s.respond(w, r, 0, nil, err)
+		return
+	}
+	id, err := s.repo.CreateIPAcl(r.Context(), a, actorOf(r))
+	a.ID = id
+	s.mutated(w, r, http.StatusCreated, a, err)
+}
+
+func (s *Server) deleteIPAcl(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("aclId"), 10, 64)
+	if err != nil {
+		s.respond(w, r, 0, nil, invalid("aclId", "must be a number"))
+		return
+	}
+	err = s.repo.DeleteIPAcl(r.Context(), id, actorOf(r))
+	s.mutated(w, r, http.StatusOK, nil, err)
+}
+
+// ==== audit ====
+
+func (s *Server) listAudit(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	out, err := s.repo.ListAudit(r.Context(), limit)
+	s.respond(w, r, http.StatusOK, out, err)
+}
+
+// ==== 공통 ====
+
+// mutated: 변경 성공 시 캐시 무효화 후 응답
+func (s *Server) mutated(w http.ResponseWriter, r *http.Request, status int, data any, err error) {
+	if err == nil {
+		for _, inv := range s.invalidators {
+			inv.Invalidate()
+		}
+	}
+	s.respond(w, r, status, data, err)
+}
+
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, status int, data any, err error) {
+	var ve *validationError
+	switch {
+	case err == nil:
+		httpx.WriteJSON(w, status, httpx.Response{Success: true, Data: data})
+	case errors.As(err, &ve):
+		httpx.WriteError(w, r, httpx.Err(model.ErrCodeValidation, nil).WithMessage(ve.Error()))
+	case errors.Is(err, store.ErrNotFound):
+		httpx.WriteError(w, r, httpx.Err(model.ErrCodeNotFound, nil))
+	case errors.Is(err, store.ErrConflict):
+		httpx.WriteError(w, r, httpx.Err(model.ErrCodeConflict, nil))
+	default:
+		httpx.WriteError(w, r, httpx.Err(model.ErrCodeInternal, err))
+	}
+}
+
+// decode: 1MiB 제한 + 알 수 없는 필드 거부
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		httpx.WriteError(w, r, httpx.Err(model.ErrCodeBadRequest, err))
+		return false
+	}
+	return true
+}
+
+func actorOf(r *http.Request) store.Actor {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return store.Actor{
+		Name:     actorName(r.Context()),
+		ClientIP: ip,
+		Tcid:     header.Parse(r.Header.Get("X-Fw-Header"))["TCID"],
+	}
+}
+
+type actorKey struct{}
+
+func withActorName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, actorKey{}, name)
+}
+
+func actorName(ctx context.Context) string {
+	v, _ := ctx.Value(actorKey{}).(string)
+	return v
+}
@@ -0,0 +1,226 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/maintenance"
+	"service-gateway/internal/masking"
+	"service-gateway/internal/model"
+	"strings"
+	"time"
+)
+
+var (
+	reGroupCd = regexp.MustCompile(`^\d{3}$`)
+	reApiCd   = regexp.MustCompile(`^\d{5}$`)
+	reBizCd   = regexp.MustCompile(`^[A-Za-z0-9_-]{1,20}$`)
+	reEstKey  = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,50}$`)
+
+	// 제어시각 포맷 yyyyMMddHHmmssSSS (checkControlCodes 비교 기준과 동일)
+	reClotTim = regexp.MustCompile(`^\d{17}$`)
+)
+
+type validationError struct {
+	field string
+	msg   string
+}
+
+func (e *validationError) Error() string { return e.field + ": " + e.msg }
+
+func invalid(field, format string, args ...any) error {
+	return &validationError{field: field, msg: fmt.Sprintf(format, args...)}
+}
+
+// 빈 값 기본 채움 + 형식 검증
+func normalizeYn(v string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(v)) {
+	case "", "Y":
+		return "Y", nil
+	case "N":
+		return "N", nil
+	}
+	return "", invalid("usgYn", "must be Y or N")
+}
+
+func validateClot(ctlCd *string, sta, end string) error {
+	if *ctlCd == "" {
+		*ctlCd = "00"
+	}
+	if _, ok := model.ErrorCodeMap[*ctlCd]; !ok {
+		return invalid("clotCtlCd", "unknown control code %q", *ctlCd)
+	}
+	for name, v := range map[string]string{"clotUablStaTim": sta, "clotUablEndTim": end} {
+		if v == "" {
+			continue
+		}
+		if !reClotTim.MatchString(v) {
+			return invalid(name, "must be 17 digits (yyyyMMddHHmmssSSS)")
+		}
+		if _, err := time.Parse("20060102150405", v[:14]); err != nil {
+			return invalid(name, "not a valid date time")
+		}
+	}
+	if *ctlCd == "08" {
+		if sta == "" || end == "" {
+			return invalid("clotUablStaTim", "start and end time are required for control code 08")
+		}
+		if sta > end {
+			return invalid("clotUablEndTim", "end time is before start time")
+		}
+	}
+	return nil
+}
+
+func validateGroup(g *model.ApiGroup) error {
+	if !reGroupCd.MatchString(g.ApiGroupCode) {
+		return invalid("apiGroupCd", "must be 3 digits")
+	}
+	if len(g.ApiGroupName) > 100 {
+		return invalid("apiGroupNm", "too long (max 100)")
+	}
+	yn, err := normalizeYn(g.UseYn)
+	if err != nil {
+		return err
+	}
+	g.UseYn = yn
+	return validateClot(&g.ClotCtlCode, g.ClotUablStaTim, g.ClotUablEndTim)
+}
+
+func validateApi(a *model.ApiDetail) error {
+	if !reGroupCd.MatchString(a.ApiGroupCode) {
+		return invalid("apiGroupCd", "must be 3 digits")
+	}
+	if !reApiCd.MatchString(a.ApiCode) {
+		return invalid("apiCd", "must be 5 digits")
+	}
+	if !strings.HasPrefix(a.ApiPath, "/") || len(a.ApiPath) > 200 || strings.ContainsAny(a.ApiPath, "?# ") {
+		return invalid("apiPath", "must start with '/' without query (max 200)")
+	}
+	if a.ApiTypeCode == "" {
+		a.ApiTypeCode = "00"
+	}
+	if len(a.ApiName) > 100 {
+		return invalid("apiNm", "too long (max 100)")
+	}
+	if a.TargetURI != "" {
+		u, err := url.Parse(a.TargetURI)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid("targetUri", "must be an absolute http(s) URL")
+		}
+	}
+	yn, err := normalizeYn(a.UseYn)
+	if err != nil {
+		return err
+	}
+	a.UseYn = yn
+	return validateClot(&a.ClotCtlCode, a.ClotUablStaTim, a.ClotUablEndTim)
+}
+
+func validatePermission(p *model.BizSrvcPermission) error {
+	if !reBizCd.MatchString(p.BizServiceCode) {
+		return invalid("bizSrvcCd", "must be 1-20 characters [A-Za-z0-9_-]")
+	}
+	if !reGroupCd.MatchString(p.ApiGroupCode) {
+		return invalid("apiGroupCd", "must be 3 digits")
+	}
+	if !reApiCd.MatchString(p.ApiCode) {
+		return invalid("apiCd", "must be 5 digits")
+	}
+	return nil
+}
+
+func validateApiKey(k *model.ApiKey) error {
+	if !reBizCd.MatchString(k.BizServiceCode) {
+		return invalid("bizSrvcCd", "must be 1-20 characters [A-Za-z0-9_-]")
+	}
+	if len(k.Name) > 100 {
+		return invalid("keyNm", "too long (max 100)")
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(time.Now()) {
+		return invalid("expDtm", "must be in the future")
+	}
+	return nil
+}
+
+func validateIPAcl(a *model.IPAcl) error {
+	if !reBizCd.MatchString(a.BizServiceCode) {
+		return invalid("bizSrvcCd", "must be 1-20 characters [A-Za-z0-9_-]")
+	}
+	p, err := ipfilter.ParsePrefix(a.CIDR)
+	if err != nil {
+		return invalid("cidr", "%s", err.Error())
+	}
+	a.CIDR = p.String()
+	switch strings.ToUpper(a.Type) {
+	case "", "A":
+		a.Type = "A"
+	case "D":
+		a.Type = "D"
+	default:
+		return invalid("aclTyp", "must be A (allow) or D (deny)")
+	}
+	if len(a.Remark) > 200 {
+		return invalid("rmk", "too long (max 200)")
+	}
+	yn, err := normalizeYn(a.UseYn)
+	if err != nil {
+		return err
+	}
+	a.UseYn = yn
+	return nil
+}
+
+func validateSetting(s *model.ApiSetting) error {
+	if !reGroupCd.MatchString(s.ApiGroupCode) {
+		return invalid("apiGroupCd", "must be 3 digits")
+	}
+	if !reEstKey.MatchString(s.Key) {
+		return invalid("apiEstKey", "must be 1-50 characters [A-Za-z0-9_.-]")
+	}
+	if s.Value == "" || len(s.Value) > 200 {
+		return invalid("value", "required (max 200)")
+	}
+	if masking.IsSettingKey(s.Key) {
+		if err := masking.ValidateRule(s.Value); err != nil {
+			return invalid("value", "%v", err)
+		}
+	}
+	yn, err := normalizeYn(s.UseYn)
+	if err != nil {
+		return err
+	}
+	s.UseYn = yn
+	return nil
+}
+
+func validateMaintenanceWindow(mw *model.MaintenanceWindow) error {
+	if !reGroupCd.MatchString(mw.ApiGroupCode) {
+		return invalid("apiGroupCd", "must be 3 digits")
+	}
+	if mw.ApiCode != "" && !reApiCd.MatchString(mw.ApiCode) {
+		return invalid("apiCd", "must be 5 digits or empty for the whole group")
+	}
+	if mw.ControlCode == "" {
+		mw.ControlCode = "08"
+	}
+	if mw.TimeZone == "" {
+		mw.TimeZone = "Asia/Seoul"
+	}
+	if len(mw.Message) > 500 {
+		return invalid("msg", "too long (max 500)")
+	}
+	if mw.RetryAfterSec < 0 {
+		return invalid("retryAfterSec", "must not be negative")
+	}
+	yn, err := normalizeYn(mw.UseYn)
+	if err != nil {
+		return err
+	}
+	mw.UseYn = yn
+	if err := maintenance.Validate(*mw); err != nil {
+		return &validationError{field: "window", msg: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+업무서비스 API 키 (SID_BIZ_SRVC_API_KEY)
+
+WHY:
+- ExistUseAPIList 권한 체크가 클라이언트가 X-Fw-Header / 바디에 넣은 BizSrvcCd 를 그대로 믿어
+  어떤 클라이언트든 다른 업무서비스를 사칭할 수 있었음.
+- 키를 업무서비스(BIZ_SRVC_CD)에 묶어 발급하고, 게이트웨이는 키로 업무서비스를 판별.
+
+형식: gwk_<keyId 12자>_<secret 32자>  (X-Api-Key 헤더)
+- DB 에는 SHA-256(전체 키) 만 저장 (고엔트로피 랜덤 값이므로 느린 해시 불필요), 원문은 발급 시 1회 노출
+- 조회는 keyId 로, 비교는 상수 시간
+- 폐기(USG_YN = N) / 만료(EXP_DTM) 키 거부
+- ttl 동안 keyId 별 캐시 (없는 키도 캐시 → 무작위 키 대입 시 DB 부하 방지), 관리 API 변경 시 Invalidate()
+*/
+
+const (
+	prefix    = "gwk_"
+	idLen     = 12
+	secretLen = 32
+	alphabet  = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	maxEntries = 10000
+)
+
+var (
+	ErrMalformed = errors.New("apikey: malformed key")
+	ErrUnknown   = errors.New("apikey: unknown key")
+	ErrRevoked   = errors.New("apikey: key revoked")
+	ErrExpired   = errors.New("apikey: key expired")
+)
+
+// Generate: 새 키 (원문, keyId, 저장용 해시)
+func Generate() (plain, keyID, hash string, err error) {
+	b, err := randomString(idLen + secretLen)
+	if err != nil {
+		return "", "", "", err
+	}
+	keyID = string(b[:idLen])
+	plain = prefix + keyID + "_" + string(b[idLen:])
+	return plain, keyID, Hash(plain), nil
+}
+
+// randomString: alphabet 균등 분포 n 자 (256 % 36 != 0 → 모듈로 편향 없도록 범위 밖 바이트는 버림)
+func randomString(n int) ([]byte, error) {
+	const limit = 256 - 256%len(alphabet)
+	out := make([]byte, 0, n)
+	buf := make([]byte, n+n/4)
+	for len(out) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for _, c := range buf {
+			if int(c) >= limit {
+				continue
+			}
+			out = append(out, alphabet[int(c)%len(alphabet)])
+			if len(out) == n {
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// Hash: 저장/비교용 SHA-256 hex
+func Hash(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
+
+// Parse: 원문 → keyId
+func Parse(plain string) (string, error) {
+	rest, ok := strings.CutPrefix(plain, prefix)
+	if !ok {
+		return "", ErrMalformed
+	}
+	id, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(id) != idLen || len(secret) != secretLen {
+		return "", ErrMalformed
+	}
+	return id, nil
+}
+
+// Source: 키 조회 (store.Repository 가 구현)
+type Source interface {
+	FindApiKey(ctx context.Context, keyID string) (model.ApiKey, error)
+}
+
+type entry struct {
+	key      model.ApiKey
+	found    bool
+	loadedAt time.Time
+}
+
+type Resolver struct {
+	src Source
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.RWMutex
+	entries map[string]entry
+}
+
+func NewResolver(src Source, ttl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Resolver{src: src, ttl: ttl, now: time.Now, entries: map[string]entry{}}
+}
+
+// Authenticate: 키 검증 후 묶인 키 정보 (BizServiceCode) 반환
+func (r *Resolver) Authenticate(ctx context.Context, plain string) (model.ApiKey, error) {
+	keyID, err := Parse(plain)
+	if err != nil {
+		return model.ApiKey{}, err
+	}
+	e, err := r.lookup(ctx, keyID)
+	if err != nil {
+		return model.ApiKey{}, err
+	}
+	if !e.found || subtle.ConstantTimeCompare([]byte(Hash(plain)), []byte(e.key.Hash)) != 1 {
+		return model.ApiKey{}, ErrUnknown
+	}
+	if e.key.UseYn != "Y" {
+		return model.ApiKey{}, ErrRevoked
+	}
+	if e.key.ExpiresAt != nil && !r.now().Before(*e.key.ExpiresAt) {
+		return model.ApiKey{}, ErrExpired
+	}
+	return e.key, nil
+}
+
+// Invalidate: 다음 Authenticate 에서 DB 재조회 (admin.Invalidator)
+func (r *Resolver) Invalidate() {
+	r.mu.Lock()
+	r.entries = map[string]entry{}
+	r.mu.Unlock()
+}
+
+// lookup: 캐시 → DB (조회 실패 시 이전 캐시 유지)
+func (r *Resolver) lookup(ctx context.Context, keyID string) (entry, error) {
+	r.mu.RLock()
+	e, ok := r.entries[keyID]
+	r.mu.RUnlock()
+	if ok && r.now().Sub(e.loadedAt) < r.ttl {
+		return e, nil
+	}
+
+	k, err := r.src.FindApiKey(ctx, keyID)
+	switch {
+	case err == nil:
+		e = entry{key: k, found: true, loadedAt: r.now()}
+	case errors.Is(err, store.ErrNotFound):
+		e = entry{loadedAt: r.now()}
+	case ok:
+		return e, nil // DB 장애: 이전 값으로 계속
+	default:
+		return entry{}, err
+	}
+
+	r.mu.Lock()
+	if len(r.entries) >= maxEntries { // 무작위 키 대입으로 캐시가 무한히 커지지 않도록
+		r.entries = map[string]entry{}
+	}
+	r.entries[keyID] = e
+	r.mu.Unlock()
+	return e, nil
+}
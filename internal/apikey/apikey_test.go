@@ -0,0 +1,234 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var keyNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeSource: keyId → 키, 조회 수 집계 / 지정 에러
+type fakeSource struct {
+	mu    sync.Mutex
+	keys  map[string]model.ApiKey
+	calls map[string]int
+	err   error
+}
+
+func (f *fakeSource) FindApiKey(_ context.Context, keyID string) (model.ApiKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[keyID]++
+	if f.err != nil {
+		return model.ApiKey{}, f.err
+	}
+	k, ok := f.keys[keyID]
+	if !ok {
+		return model.ApiKey{}, store.ErrNotFound
+	}
+	return k, nil
+}
+
+// issued: 새 키 발급 + 소스에 등록
+func issued(t *testing.T, src *fakeSource, mutate func(*model.ApiKey)) string {
+	t.Helper()
+	plain, id, hash, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := model.ApiKey{KeyID: id, BizServiceCode: "SMP", Hash: hash, UseYn: "Y"}
+	if mutate != nil {
+		mutate(&k)
+	}
+	src.keys[id] = k
+	return plain
+}
+
+func newResolver(src *fakeSource) (*Resolver, *time.Time) {
+	now := keyNow
+	r := NewResolver(src, time.Minute)
+	r.now = func() time.Time { return now }
+	return r, &now
+}
+
+func newSource() *fakeSource {
+	return &fakeSource{keys: map[string]model.ApiKey{}, calls: map[string]int{}}
+}
+
+func TestGenerateParse(t *testing.T) {
+	plain, id, hash, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain) != len(prefix)+idLen+1+secretLen || hash != Hash(plain) {
+		t.Errorf("plain %q, hash %q", plain, hash)
+	}
+	if got, err := Parse(plain); err != nil || got != id {
+		t.Errorf("Parse = %q %v, want %q", got, err, id)
+	}
+
+	for _, bad := range []string{
+		"",
+		"gwk_",
+		"sk_" + strings.Repeat("a", idLen) + "_" + strings.Repeat("b", secretLen),
+		"gwk_" + strings.Repeat("a", idLen) + strings.Repeat("b", secretLen),
+		"gwk_" + strings.Repeat("a", idLen-1) + "_" + strings.Repeat("b", secretLen),
+		"gwk_" + strings.Repeat("a", idLen) + "_" + strings.Repeat("b", secretLen+1),
+		"gwk_" + strings.Repeat("a", idLen) + "_",
+	} {
+		if _, err := Parse(bad); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Parse(%q) = %v, want ErrMalformed", bad, err)
+		}
+	}
+}
+
+func TestRandomString(t *testing.T) {
+	seen := map[byte]bool{}
+	for _, n := range []int{1, 7, 44, 500} {
+		b, err := randomString(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != n {
+			t.Fatalf("len = %d, want %d", len(b), n)
+		}
+		for _, c := range b {
+			if !strings.ContainsRune(alphabet, rune(c)) {
+				t.Fatalf("byte %q outside alphabet", c)
+			}
+			seen[c] = true
+		}
+	}
+	if len(seen) < len(alphabet)/2 {
+		t.Errorf("only %d distinct characters in 552 draws", len(seen))
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	src := newSource()
+	r, _ := newResolver(src)
+	future, past := keyNow.Add(time.Hour), keyNow
+
+	valid := issued(t, src, nil)
+	revoked := issued(t, src, func(k *model.ApiKey) { k.UseYn = "N" })
+	expired := issued(t, src, func(k *model.ApiKey) { k.ExpiresAt = &past })
+	notYet := issued(t, src, func(k *model.ApiKey) { k.ExpiresAt = &future })
+
+	if k, err := r.Authenticate(ctx, valid); err != nil || k.BizServiceCode != "SMP" {
+		t.Errorf("valid: %v %v", k, err)
+	}
+	if _, err := r.Authenticate(ctx, notYet); err != nil {
+		t.Errorf("expires later: %v", err)
+	}
+	// 같은 keyId, 다른 secret → 해시 불일치
+	id, _ := Parse(valid)
+	if _, err := r.Authenticate(ctx, prefix+id+"_"+strings.Repeat("x", secretLen)); !errors.Is(err, ErrUnknown) {
+		t.Errorf("hash mismatch: %v", err)
+	}
+	if _, err := r.Authenticate(ctx, revoked); !errors.Is(err, ErrRevoked) {
+		t.Errorf("revoked: %v", err)
+	}
+	if _, err := r.Authenticate(ctx, expired); !errors.Is(err, ErrExpired) {
+		t.Errorf("expired at exp: %v", err)
+	}
+	if _, err := r.Authenticate(ctx, "gwk_bad"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("malformed: %v", err)
+	}
+	if n := src.calls["gwk_bad"]; n != 0 {
+		t.Errorf("malformed key reached the source %d times", n)
+	}
+}
+
+func TestNegativeCache(t *testing.T) {
+	ctx := context.Background()
+	src := newSource()
+	r, now := newResolver(src)
+	id := strings.Repeat("u", idLen)
+	unknown := prefix + id + "_" + strings.Repeat("s", secretLen)
+
+	for range 3 {
+		if _, err := r.Authenticate(ctx, unknown); !errors.Is(err, ErrUnknown) {
+			t.Fatalf("unknown: %v", err)
+		}
+	}
+	if n := src.calls[id]; n != 1 {
+		t.Errorf("source calls within ttl = %d, want 1", n)
+	}
+
+	// ttl 이후 재조회 → 그 사이 발급된 키도 인식
+	*now = now.Add(2 * time.Minute)
+	src.keys[id] = model.ApiKey{KeyID: id, BizServiceCode: "SMP", Hash: Hash(unknown), UseYn: "Y"}
+	if _, err := r.Authenticate(ctx, unknown); err != nil {
+		t.Errorf("after ttl: %v", err)
+	}
+	if n := src.calls[id]; n != 2 {
+		t.Errorf("source calls after ttl = %d, want 2", n)
+	}
+}
+
+func TestCacheBounded(t *testing.T) {
+	src := newSource()
+	r, _ := newResolver(src)
+	for i := range maxEntries {
+		r.entries[fmt.Sprintf("%012d", i)] = entry{loadedAt: keyNow}
+	}
+	valid := issued(t, src, nil)
+	if _, err := r.Authenticate(context.Background(), valid); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.entries) != 1 {
+		t.Errorf("entries = %d after reaching maxEntries, want 1", len(r.entries))
+	}
+}
+
+func TestSourceErrorKeepsPrevious(t *testing.T) {
+	ctx := context.Background()
+	src := newSource()
+	r, now := newResolver(src)
+	valid := issued(t, src, nil)
+	if _, err := r.Authenticate(ctx, valid); err != nil {
+		t.Fatal(err)
+	}
+
+	src.err = errors.New("db down")
+	*now = now.Add(2 * time.Minute)
+	if k, err := r.Authenticate(ctx, valid); err != nil || k.BizServiceCode != "SMP" {
+		t.Errorf("cached key during outage: %v %v", k, err)
+	}
+
+	// 캐시에 없던 키는 에러 그대로 (ErrUnknown 으로 바꾸지 않음 → 호출측이 5xx 로 구분)
+	other := issued(t, src, nil)
+	if _, err := r.Authenticate(ctx, other); err == nil || errors.Is(err, ErrUnknown) {
+		t.Errorf("uncached key during outage: %v", err)
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	src := newSource()
+	r, _ := newResolver(src)
+	valid := issued(t, src, nil)
+	id, _ := Parse(valid)
+	if _, err := r.Authenticate(ctx, valid); err != nil {
+		t.Fatal(err)
+	}
+
+	k := src.keys[id]
+	k.UseYn = "N"
+	src.keys[id] = k
+	if _, err := r.Authenticate(ctx, valid); err != nil {
+		t.Errorf("cached before invalidate: %v", err)
+	}
+	r.Invalidate()
+	if _, err := r.Authenticate(ctx, valid); !errors.Is(err, ErrRevoked) {
+		t.Errorf("after invalidate: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"service-gateway/internal/kafkax"
+	"service-gateway/internal/masking"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+감사 로그 이벤트 (README "json format")
+
+WHY:
+- DynamicGateway.Post / returnlog 에서 gwLog 를 5군데 손으로 조립하면서 X-Fw-Header 를 매번 다시 파싱,
+  ApiCd "00001" 고정, HostName/Guid/InterId 누락, cmmBizSrvcSrno 누락 등 스키마와 어긋나 있었음.
+- 요청 1건당 Trail 하나를 만들고 단계(11/21/22/12)별 메서드만 호출 → 공통 필드는 한 번만 채움.
+
+단계(rasTyp): 11 인바운드 요청, 21 업스트림 송신, 22 업스트림 수신, 12 인바운드 응답
+tcIdSrno    : 요청 단계(11/21)는 수신한 TCIDSRNO, 응답 단계(22/12)는 +1 (응답 X-Fw-Header 와 동일)
+*/
+
+// Phase: rasTyp 값
+type Phase string
+
+const (
+	InboundRequest   Phase = "11"
+	InboundResponse  Phase = "12"
+	OutboundRequest  Phase = "21"
+	OutboundResponse Phase = "22"
+)
+
+// Event: README 로그 스키마 + status / latencyMs (응답 단계만)
+type Event struct {
+	TimeStamp      string          `json:"timeStamp"` // yyyyMMddHHmmssSSS
+	TcId           string          `json:"tcId"`
+	TcIdSrno       int             `json:"tcIdSrno"`
+	BizSrvcCd      string          `json:"bizSrvcCd"`
+	BizSrvcIp      string          `json:"bizSrvcIp"`
+	RasTyp         Phase           `json:"rasTyp"`
+	NmlYn          string          `json:"nmlYn"`
+	ApiPath        string          `json:"apiPath"`
+	CmmSrvcCd      string          `json:"cmmSrvcCd"`
+	CmmBizSrvcCd   string          `json:"cmmBizSrvcCd"`
+	CmmBizSrvcSrno int             `json:"cmmBizSrvcSrno"`
+	ApiGroupCd     string          `json:"apiGroupCd"`
+	ApiCd          string          `json:"apiCd"`
+	Guid           string          `json:"guid"`
+	InterId        string          `json:"interId"`
+	MessageCd      string          `json:"messageCd"`
+	HostName       string          `json:"hostName"`
+	Status         int             `json:"status,omitempty"`
+	LatencyMs      int64           `json:"latencyMs,omitempty"`
+	Data           json.RawMessage `json:"data,omitempty"`
+}
+
+// Emitter: Sink + 토픽 라우팅 + 단계별 적재 여부 (게이트웨이 단위로 1개)
+type Emitter struct {
+	Sink      Sink
+	Router    *kafkax.Router                          // nil 이면 기본 토픽 + tcId 키
+	Masks     *masking.Registry                       // API 별 마스킹 (nil 이면 전역 규칙)
+	Enabled   func(ctx context.Context, p Phase) bool // nil 이면 전 단계 적재
+	HostName  string
+	GroupCode string // API 확정 전 기본 apiGroupCd (application.group_code)
+	MaxBody   int    // data 최대 바이트 (기본 4KiB)
+}
+
+// NewEmitter: HostName 은 os.Hostname (실패 시 빈 값)
+func NewEmitter(sink Sink, enabled func(ctx context.Context, p Phase) bool) *Emitter {
+	host, _ := os.Hostname()
+	return &Emitter{Sink: sink, Enabled: enabled, HostName: host, MaxBody: 4 * 1024}
+}
+
+// Trail: 요청 1건의 감사 컨텍스트 (단일 goroutine 에서 사용)
+type Trail struct {
+	e        *Emitter
+	ctx      context.Context
+	base     Event
+	upstream string // 21/22 단계 apiPath (업스트림 URL)
+	start    time.Time
+	sent     time.Time
+}
+
+// Begin: 서버 기준 필드가 적용된 X-Fw-Header(map) 로 공통 필드 구성
+func (e *Emitter) Begin(r *http.Request, fw map[string]string) *Trail {
+	return &Trail{
+		e:     e,
+		ctx:   r.Context(),
+		start: time.Now(),
+		base: Event{
+			TcId:           fw["TCID"],
+			TcIdSrno:       atoi(fw["TCIDSRNO"]),
+			BizSrvcCd:      fw["BizSrvcCd"],
+			BizSrvcIp:      fw["BizSrvcIp"],
+			ApiPath:        r.URL.String(),
+			CmmSrvcCd:      fw["CmmSrvcCd"],
+			CmmBizSrvcCd:   fw["CmmBizSrvcCd"],
+			CmmBizSrvcSrno: atoi(fw["CmmBizSrvcSrno"]),
+			ApiGroupCd:     e.GroupCode,
+			Guid:           fw["Guid"],
+			InterId:        fw["InterId"],
+			HostName:       e.HostName,
+		},
+	}
+}
+
+// Resolve: 카탈로그(SID_API_DTL_MNG) 조회 결과로 그룹/API 코드 확정
+func (t *Trail) Resolve(apiGroupCd, apiCd string) {
+	if apiGroupCd != "" {
+		t.base.ApiGroupCd = apiGroupCd
+	}
+	t.base.ApiCd = apiCd
+}
+
+// Mask: 현재 API(Resolve 이후) 기준 마스킹 규칙 → 디버그 로그에도 동일 규칙 적용
+func (t *Trail) Mask() *masking.Masker {
+	return t.e.Masks.For(t.ctx, t.base.ApiGroupCd, t.base.ApiCd)
+}
+
+// Request: 11 인바운드 요청
+func (t *Trail) Request(body []byte) {
+	t.emit(InboundRequest, t.base.TcIdSrno, "", 0, 0, body)
+}
+
+// Send: 21 업스트림 송신 (업스트림 지연 측정 시작)
+func (t *Trail) Send(url string, body []byte) {
+	t.sent, t.upstream = time.Now(), url
+	t.emit(OutboundRequest, t.base.TcIdSrno, "", 0, 0, body)
+}
+
+// Receive: 22 업스트림 수신 (latencyMs = 업스트림 구간)
+func (t *Trail) Receive(status int, body []byte) {
+	t.emit(OutboundResponse, t.base.TcIdSrno+1, "", status, time.Since(t.sent), body)
+}
+
+// Respond: 12 인바운드 응답 (latencyMs = 게이트웨이 전체 구간, messageCd 가 있으면 nmlYn=N)
+func (t *Trail) Respond(status int, messageCd string, body []byte) {
+	t.emit(InboundResponse, t.base.TcIdSrno+1, messageCd, status, time.Since(t.start), body)
+}
+
+func (t *Trail) emit(p Phase, srno int, messageCd string, status int, latency time.Duration, body []byte) {
+	if t == nil || t.e.Sink == nil {
+		return
+	}
+	if t.e.Enabled != nil && !t.e.Enabled(t.ctx, p) {
+		return
+	}
+	ev := t.base
+	if p == OutboundRequest || p == OutboundResponse {
+		ev.ApiPath = t.upstream
+	}
+	ev.TimeStamp = strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1)
+	ev.RasTyp = p
+	ev.TcIdSrno = srno
+	ev.MessageCd = messageCd
+	ev.NmlYn = "Y"
+	if messageCd != "" || status >= 400 {
+		ev.NmlYn = "N"
+	}
+	ev.Status = status
+	if status != 0 {
+		ev.LatencyMs = latency.Milliseconds()
+	}
+	ev.Data = t.e.data(t.Mask().Body(body)) // 자르기 전에 마스킹 (잘린 JSON 은 경로 규칙 적용 불가)
+
+	buf, _ := json.Marshal(ev)
+	t.e.Sink.Publish(t.e.Router.Message(kafkax.Fields{
+		RasTyp:     string(ev.RasTyp),
+		ApiGroupCd: ev.ApiGroupCd,
+		ApiCd:      ev.ApiCd,
+		BizSrvcCd:  ev.BizSrvcCd,
+		Tcid:       ev.TcId,
+		Guid:       ev.Guid,
+	}, buf))
+}
+
+// data: JSON 바디는 객체 그대로, 그 외(비 JSON / 잘린 바디)는 문자열로 기록
+func (e *Emitter) data(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	limit := e.MaxBody
+	if limit <= 0 {
+		limit = 4 * 1024
+	}
+	if len(body) <= limit && json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	if len(body) > limit {
+		body = body[:limit]
+	}
+	s, _ := json.Marshal(string(body))
+	return s
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
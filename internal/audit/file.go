@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"service-gateway/internal/kafkax"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+로컬 파일 Sink (JSON Lines + 크기 기준 회전)
+
+- path 가 max_size_mb 를 넘으면 path.<yyyyMMdd-HHmmss.SSS> 로 이름 변경 후 새 파일
+- 회전 파일은 최신 max_backups 개만 유지
+- 쓰기 실패는 로그만 남김 (감사 로그 장애로 거래가 막히지 않도록)
+*/
+
+type fileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+}
+
+func NewFileSink(path string, maxSizeMB, maxBackups int) (Sink, error) {
+	if path == "" {
+		return nil, errors.New("file sink path empty")
+	}
+	if maxSizeMB <= 0 {
+		maxSizeMB = 100
+	}
+	if maxBackups <= 0 {
+		maxBackups = 7
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, err
+	}
+	s := &fileSink{path: path, maxSize: int64(maxSizeMB) << 20, maxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f, s.size = f, info.Size()
+	return nil
+}
+
+func (s *fileSink) Publish(m kafkax.Message) {
+	line := make([]byte, 0, len(m.Value)+1)
+	line = append(append(line, m.Value...), '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return
+	}
+	if s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			slog.Error("rotate failed", "component", "audit-file", "path", s.path, "err", err)
+			if s.f == nil {
+				return
+			}
+		}
+	}
+	n, err := s.f.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		slog.Error("write failed", "component", "audit-file", "path", s.path, "err", err)
+	}
+}
+
+// rotate: 현재 파일 이름 변경 → 새 파일 → 오래된 백업 정리 (mu 보유 상태)
+func (s *fileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		slog.Warn("close failed", "component", "audit-file", "path", s.path, "err", err)
+	}
+	s.f = nil
+	backup := s.path + "." + time.Now().Format("20060102-150405.000")
+	if err := os.Rename(s.path, backup); err != nil {
+		_ = s.open() // 이름 변경 실패 시 기존 파일에 이어쓰기
+		return err
+	}
+	if err := s.open(); err != nil {
+		return err
+	}
+	s.prune()
+	return nil
+}
+
+func (s *fileSink) prune() {
+	backups, err := filepath.Glob(s.path + ".*")
+	if err != nil || len(backups) <= s.maxBackups {
+		return
+	}
+	sort.Strings(backups) // 타임스탬프 접미사 → 사전순 = 시간순
+	for _, old := range backups[:len(backups)-s.maxBackups] {
+		if err := os.Remove(old); err != nil {
+			slog.Warn("remove backup failed", "component", "audit-file", "path", old, "err", err)
+		}
+	}
+}
+
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"service-gateway/internal/kafkax"
+	"sync"
+	"time"
+)
+
+/*
+감사 로그 출력 대상 (Sink)
+
+WHY:
+- gwLog 이벤트가 Kafka(또는 Noop)로만 나가서 로컬/테스트 환경에서 확인할 방법이 없었음.
+- application.log.sinks 설정으로 stdout / file / webhook / kafka 를 골라 여러 곳에 동시 출력(fan-out).
+- kafkax.Publisher 도 같은 메서드 집합이라 그대로 Sink 로 사용.
+*/
+
+// Sink: 감사 로그 출력 대상 (Publish 는 요청 경로에서 호출되므로 블로킹 금지)
+type Sink interface {
+	Publish(m kafkax.Message)
+	Close() error
+}
+
+// SinkConfig: application.log.sinks[] 항목
+type SinkConfig struct {
+	Type string // kafka | stdout | file | webhook
+
+	// file
+	Path       string
+	MaxSizeMB  int // 기본 100
+	MaxBackups int // 기본 7
+
+	// webhook
+	URL           string
+	Headers       map[string]string
+	BatchSize     int           // 기본 100
+	FlushInterval time.Duration // 기본 1s
+	Timeout       time.Duration // 기본 5s
+}
+
+// Build: 설정 → Sink (여러 개면 FanOut). 비어 있으면 kafka 단독 (기존 동작)
+func Build(cfgs []SinkConfig, kafka kafkax.Publisher) (Sink, error) {
+	if len(cfgs) == 0 {
+		return kafka, nil
+	}
+	var sinks []Sink
+	for i, c := range cfgs {
+		s, err := build(c, kafka)
+		if err != nil {
+			for _, opened := range sinks {
+				_ = opened.Close()
+			}
+			return nil, fmt.Errorf("application.log.sinks[%d]: %w", i, err)
+		}
+		sinks = append(sinks, s)
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return FanOut(sinks...), nil
+}
+
+func build(c SinkConfig, kafka kafkax.Publisher) (Sink, error) {
+	switch c.Type {
+	case "kafka":
+		return kafka, nil
+	case "stdout":
+		return Stdout(), nil
+	case "file":
+		return NewFileSink(c.Path, c.MaxSizeMB, c.MaxBackups)
+	case "webhook":
+		return NewWebhookSink(c)
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", c.Type)
+	}
+}
+
+// ==== fan-out ====
+
+type fanOut []Sink
+
+// FanOut: 모든 Sink 로 동일 메시지 전달
+func FanOut(sinks ...Sink) Sink {
+	return fanOut(sinks)
+}
+
+func (f fanOut) Publish(m kafkax.Message) {
+	for _, s := range f {
+		s.Publish(m)
+	}
+}
+
+// Close: kafka 는 main 에서 별도로 닫으므로 여기서 중복 Close 되어도 무해해야 함
+func (f fanOut) Close() error {
+	var errs []error
+	for _, s := range f {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ==== stdout / io.Writer (JSON Lines) ====
+
+type writerSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink: 메시지 값(JSON) 을 한 줄씩 기록
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+// Stdout: 로컬 개발/컨테이너 로그 수집용
+func Stdout() Sink {
+	return NewWriterSink(os.Stdout)
+}
+
+func (s *writerSink) Publish(m kafkax.Message) {
+	line := make([]byte, 0, len(m.Value)+1)
+	line = append(append(line, m.Value...), '\n')
+	s.mu.Lock()
+	_, _ = s.w.Write(line)
+	s.mu.Unlock()
+}
+
+func (s *writerSink) Close() error { return nil }
@@ -0,0 +1,176 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"service-gateway/internal/kafkax"
+	"sync"
+	"time"
+)
+
+/*
+HTTP 웹훅 Sink
+
+- 메시지를 모아 batch_size 또는 flush_interval 마다 JSON 배열([이벤트, ...])로 POST
+- 5xx/네트워크 오류는 최대 3회 재시도 후 배치 드롭 (로그 남김), 4xx 는 재시도 없이 드롭
+- 버퍼(1000) 가득 차면 드롭 → 웹훅 지연이 게이트웨이 응답 지연으로 번지지 않도록
+*/
+
+type webhookSink struct {
+	cfg    SinkConfig
+	client *http.Client
+	ch     chan []byte
+
+	mu      sync.RWMutex
+	closing bool
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func NewWebhookSink(cfg SinkConfig) (Sink, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook sink url empty")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	s := &webhookSink{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		ch:      make(chan []byte, 1000),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.loop()
+	return s, nil
+}
+
+func (s *webhookSink) Publish(m kafkax.Message) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closing {
+		return
+	}
+	select {
+	case s.ch <- m.Value:
+	default:
+		slog.Warn("buffer full, drop event", "component", "audit-webhook")
+	}
+}
+
+func (s *webhookSink) loop() {
+	defer close(s.stopped)
+	t := time.NewTicker(s.cfg.FlushInterval)
+	defer t.Stop()
+
+	var batch [][]byte
+	flush := func() {
+		if len(batch) > 0 {
+			s.post(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case v := <-s.ch:
+			batch = append(batch, v)
+			if len(batch) >= s.cfg.BatchSize {
+				flush()
+			}
+		case <-t.C:
+			flush()
+		case <-s.done:
+			for {
+				select {
+				case v := <-s.ch:
+					batch = append(batch, v)
+					if len(batch) >= s.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *webhookSink) post(batch [][]byte) {
+	body := append([]byte{'['}, bytes.Join(batch, []byte{','})...)
+	body = append(body, ']')
+
+	backoff := 200 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := s.send(body)
+		if err == nil {
+			return
+		}
+		var perm permanentError
+		if errors.As(err, &perm) || attempt == 3 {
+			slog.Error("drop events", "component", "audit-webhook", "events", len(batch), "attempts", attempt, "err", err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+type permanentError struct{ error }
+
+func (s *webhookSink) send(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return permanentError{fmt.Errorf("webhook status %d", resp.StatusCode)}
+	default:
+		return fmt.Errorf("webhook status %d", resp.StatusCode)
+	}
+}
+
+// Close: 신규 이벤트 차단 후 잔여 배치 전송 (최대 timeout*3 대기)
+func (s *webhookSink) Close() error {
+	s.mu.Lock()
+	if s.closing {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closing = true
+	s.mu.Unlock()
+	close(s.done)
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-time.After(3 * s.cfg.Timeout):
+		return errors.New("webhook sink drain timeout")
+	}
+}
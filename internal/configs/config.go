@@ -13,7 +13,8 @@ type Config struct {
 		Name      string `yaml:"name"`
 		GroupCode string `yaml:"group_code"`
 		Log       struct {
-			Topic   string `yaml:"topic"`
+			Topic   string    `yaml:"topic"`
+			Sinks   []LogSink `yaml:"sinks"` // 비어 있으면 kafka 단독
 			Inbound struct {
 				Request  string `yaml:"request"`
 				Response string `yaml:"response"`
@@ -26,10 +27,13 @@ type Config struct {
 	}
 
 	Server struct {
-		Addr      string `yaml:"addr"`
-		ReadTOms  int    `yaml:"read_timeout_ms"`
-		WriteTOms int    `yaml:"write_timeout_ms"`
-		IdleTOms  int    `yaml:"idle_timeout_ms"`
+		Addr      string    `yaml:"addr"`
+		ReadTOms  int       `yaml:"read_timeout_ms"`
+		WriteTOms int       `yaml:"write_timeout_ms"`
+		IdleTOms  int       `yaml:"idle_timeout_ms"`
+		TLS       ServerTLS `yaml:"tls"`
+		// 전달 헤더(Forwarded / X-Forwarded-*)를 신뢰할 직전 프록시 CIDR (비우면 RemoteAddr 만 사용)
+		TrustedProxies []string `yaml:"trusted_proxies"`
 	} `yaml:"server"`
 
 	Kafka KafkaConfig `yaml:"kafka"`
@@ -45,8 +49,131 @@ type Config struct {
 		Name     string `yaml:"name"`
 	} `yaml:"db"`
 
+	// 관리 API(/admin/v1) 전용 리스너
+	Admin struct {
+		Enabled bool   `yaml:"enabled"`
+		Addr    string `yaml:"addr"`
+		Tokens  []struct {
+			Name  string `yaml:"name"`
+			Token string `yaml:"token"`
+		} `yaml:"tokens"`
+	} `yaml:"admin"`
+
+	// 에러 응답 기본 형식/언어/타임존 + 문구 번들 (요청의 Accept / Accept-Language / x-timezone 이 우선)
+	Errors struct {
+		Format   string `yaml:"format"`
+		Lang     string `yaml:"lang"`
+		Messages string `yaml:"messages"`
+		TimeZone string `yaml:"timezone"`
+	} `yaml:"errors"`
+
+	// 구조화 로그 (application.log 는 감사 로그)
+	Logging struct {
+		Level     string `yaml:"level"`  // debug | info | warn | error
+		Format    string `yaml:"format"` // json | text
+		AddSource bool   `yaml:"add_source"`
+	} `yaml:"logging"`
+
+	// Bearer JWT 검증 (jwks_file 또는 jwks_url 중 하나)
+	JWT struct {
+		Enabled       bool              `yaml:"enabled"`
+		Required      bool              `yaml:"required"` // false 면 토큰이 있을 때만 검증
+		Issuer        string            `yaml:"issuer"`
+		Audience      []string          `yaml:"audience"`
+		JWKSFile      string            `yaml:"jwks_file"`
+		JWKSURL       string            `yaml:"jwks_url"`
+		RefreshMs     int               `yaml:"refresh_ms"`
+		LeewayMs      int               `yaml:"leeway_ms"`
+		ForwardClaims map[string]string `yaml:"forward_claims"` // 클레임 → 업스트림 헤더
+	} `yaml:"jwt"`
+
+	// X-Fw-Header FwAuthorization 검증
+	FwAuth struct {
+		Enabled     bool        `yaml:"enabled"`
+		Required    bool        `yaml:"required"` // false 면 값이 있을 때만 검증
+		Scheme      string      `yaml:"scheme"`   // hmac | aes-gcm
+		Primary     string      `yaml:"primary"`  // 발급용 키 id (기본 마지막 키)
+		ClockSkewMs int         `yaml:"clock_skew_ms"`
+		Keys        []FwAuthKey `yaml:"keys"`
+	} `yaml:"fw_auth"`
+
+	// 업무서비스 API 키 (X-Api-Key → SID_BIZ_SRVC_API_KEY)
+	APIKeys struct {
+		Enabled    bool `yaml:"enabled"`
+		Required   bool `yaml:"required"`     // false 면 키가 있을 때만 검증
+		CacheTTLMs int  `yaml:"cache_ttl_ms"` // 키 조회 캐시 (기본 30s)
+	} `yaml:"api_keys"`
+
+	// 업무서비스별 IP 허용/차단 (설정 + SID_BIZ_SRVC_IP_ACL), 라우트별은 routes[].options.ip_allow / ip_deny
+	IPFilter struct {
+		Enabled    bool              `yaml:"enabled"`
+		CacheTTLMs int               `yaml:"cache_ttl_ms"`
+		BizSrvc    map[string]IPList `yaml:"biz_srvc"`
+	} `yaml:"ip_filter"`
+
+	// 세션 서비스 (routes[].options.require_session / generate_if_missing)
+	Session struct {
+		Enabled   bool   `yaml:"enabled"`
+		Backend   string `yaml:"backend"`    // http (기본) | grpc
+		TTLMs     int    `yaml:"ttl_ms"`     // 신규 세션 유지 시간 (기본 30m)
+		TimeoutMs int    `yaml:"timeout_ms"` // 세션 서비스 호출 1건 상한
+		HTTP      struct {
+			BaseURL    string `yaml:"base_url"`
+			GetPath    string `yaml:"get_path"`
+			CreatePath string `yaml:"create_path"`
+		} `yaml:"http"`
+		GRPC struct {
+			Target    string       `yaml:"target"`     // host:port (SessionService)
+			PoolSize  int          `yaml:"pool_size"`  // ClientConn 수 (기본 1)
+			Retries   int          `yaml:"retries"`    // Unavailable / DeadlineExceeded 재시도 (기본 2, -1 이면 안 함)
+			BackoffMs int          `yaml:"backoff_ms"` // 첫 재시도 대기 (이후 2배)
+			TLS       *UpstreamTLS `yaml:"tls"`        // 없으면 평문
+		} `yaml:"grpc"`
+	} `yaml:"session"`
+
+	// 브라우저 직접 호출 CORS (전역), 라우트별은 routes[].options.cors 로 대체
+	CORS struct {
+		Enabled bool `yaml:"enabled"`
+		CORS    `yaml:",inline"`
+	} `yaml:"cors"`
+
+	// 멱등키 이중거래 체크 (X-Fw-Header IdempotencyKey / Idempotency-Key)
+	Idempotency struct {
+		Enabled      bool     `yaml:"enabled"`
+		Store        string   `yaml:"store"`   // memory (기본)
+		Methods      []string `yaml:"methods"` // 기본 POST, PUT
+		TTLMs        int      `yaml:"ttl_ms"`  // 완료 응답 보관 (기본 24h)
+		LockMs       int      `yaml:"lock_ms"` // 처리 중 선점 유지 (기본 60s)
+		WaitMs       int      `yaml:"wait_ms"` // 처리 중 중복 요청 대기 (0 이면 즉시 409)
+		MaxBodyBytes int64    `yaml:"max_body_bytes"`
+	} `yaml:"idempotency"`
+
+	// 접근 로그 (운영 로그와 별도 스트림)
+	AccessLog struct {
+		Enabled    *bool   `yaml:"enabled"`     // 미지정 시 true
+		Format     string  `yaml:"format"`      // common | combined | json | template
+		Template   string  `yaml:"template"`    // format=template 일 때 text/template
+		Output     string  `yaml:"output"`      // stdout | stderr | 파일 경로
+		SampleRate float64 `yaml:"sample_rate"` // 2xx 기록 비율 (0 또는 미지정 시 1)
+		SlowMs     int     `yaml:"slow_ms"`     // 이 시간 이상이면 샘플링과 무관하게 기록
+	} `yaml:"access_log"`
+
+	Masking struct {
+		Enabled     *bool    `yaml:"enabled"` // 미지정 시 true
+		Replacement string   `yaml:"replacement"`
+		Headers     []string `yaml:"headers"`
+		FwFields    []string `yaml:"fw_fields"` // X-Fw-Header 필드 (기본 FwAuthorization)
+		JSONPaths   []string `yaml:"json_paths"`
+		Detectors   []string `yaml:"detectors"` // card | rrn | phone | email (비어 있으면 전체)
+	} `yaml:"masking"`
+
 	Hosts map[string]string `yaml:"hosts"`
 
+	// /gateway 업스트림 TLS: API 그룹(API_GRP_CD) 별
+	UpstreamTLS struct {
+		Groups map[string]UpstreamTLS `yaml:"groups"`
+	} `yaml:"upstream_tls"`
+
 	Routes []struct {
 		Name  string `yaml:"name"`
 		Match struct {
@@ -55,17 +182,46 @@ type Config struct {
 			Methods     []string `yaml:"methods"`
 		} `yaml:"match"`
 		Backend struct {
-			Scheme      string `yaml:"scheme"`
-			Host        string `yaml:"host"`
-			Method      string `yaml:"method"`
-			PathRewrite string `yaml:"path_rewrite"`
+			Scheme      string            `yaml:"scheme"`
+			Host        string            `yaml:"host"`
+			Method      string            `yaml:"method"`
+			PathRewrite string            `yaml:"path_rewrite"`
+			TLS         *UpstreamTLS      `yaml:"tls"` // https 백엔드 사설 CA / 클라이언트 인증서
+			Signing     *Signing          `yaml:"signing"`
+			Body        map[string]string `yaml:"body"` // JSON 키 → param:<경로변수> | header:<헤더> | const:<값>
 		} `yaml:"backend"`
 		Options struct {
-			RequireSession    bool `yaml:"require_session"`
-			GenerateIfMissing bool `yaml:"generate_if_missing"`
+			RequireSession    bool              `yaml:"require_session"`
+			GenerateIfMissing bool              `yaml:"generate_if_missing"`
+			Scopes            []string          `yaml:"scopes"`   // JWT scope 모두 필요
+			Claims            map[string]string `yaml:"claims"`   // JWT 클레임 값 일치
+			IPAllow           []string          `yaml:"ip_allow"` // IP / CIDR
+			IPDeny            []string          `yaml:"ip_deny"`
+			CORS              *CORS             `yaml:"cors"` // 있으면 전역 cors 대신 적용
 		} `yaml:"options"`
 	} `yaml:"routes"`
 
+	// gRPC 리스너 (HelloService / health / SessionService 프록시), 인증·요청 한도·감사 로그는 HTTP 와 공유
+	GRPCServer struct {
+		Enabled   bool   `yaml:"enabled"`
+		Addr      string `yaml:"addr"`
+		TLS       bool   `yaml:"tls"`        // true 면 server.tls 인증서 / mTLS 사용 (server.tls.enabled 필요)
+		TimeoutMs int    `yaml:"timeout_ms"` // 세션 백엔드 호출 상한
+		Session   struct {
+			Target string       `yaml:"target"` // 비우면 SessionService 미제공
+			TLS    *UpstreamTLS `yaml:"tls"`
+		} `yaml:"session"`
+	} `yaml:"grpc_server"`
+
+	// 요청 한도 (HTTP / gRPC 공유 토큰 버킷)
+	Middleware struct {
+		RateLimit struct {
+			Enabled bool    `yaml:"enabled"`
+			Rate    float64 `yaml:"rate"` // 초당 요청 수
+			Burst   int     `yaml:"burst"`
+		} `yaml:"ratelimit"`
+	} `yaml:"middleware"`
+
 	Tracing struct {
 		Enabled bool `yaml:"enabled"`
 		OTLP    struct {
@@ -75,6 +231,90 @@ type Config struct {
 	} `yaml:"tracing"`
 }
 
+// FwAuthKey: secret(base64) 또는 secret_env(환경변수 이름, base64 값) 중 하나
+type FwAuthKey struct {
+	ID        string `yaml:"id"`
+	Secret    string `yaml:"secret"`
+	SecretEnv string `yaml:"secret_env"`
+	NotAfter  string `yaml:"not_after"` // RFC3339, 이후 이 키로 서명된 토큰 거부
+}
+
+// LogSink: 감사 로그 출력 대상 (type: kafka|stdout|file|webhook)
+type LogSink struct {
+	Type            string            `yaml:"type"`
+	Path            string            `yaml:"path"`
+	MaxSizeMB       int               `yaml:"max_size_mb"`
+	MaxBackups      int               `yaml:"max_backups"`
+	URL             string            `yaml:"url"`
+	Headers         map[string]string `yaml:"headers"`
+	BatchSize       int               `yaml:"batch_size"`
+	FlushIntervalMs int               `yaml:"flush_interval_ms"`
+	TimeoutMs       int               `yaml:"timeout_ms"`
+}
+
+// ServerTLS: 리스너 TLS 종료 / mTLS
+type ServerTLS struct {
+	Enabled          bool              `yaml:"enabled"`
+	Certs            []ServerTLSCert   `yaml:"certs"`
+	MinVersion       string            `yaml:"min_version"`   // "1.2"(기본) | "1.3"
+	CipherSuites     []string          `yaml:"cipher_suites"` // 비우면 Go 기본
+	ReloadMs         int               `yaml:"reload_ms"`     // 인증서 파일 변경 확인 주기 (기본 10s)
+	ClientAuth       string            `yaml:"client_auth"`   // none | request | require
+	ClientCAFile     string            `yaml:"client_ca_file"`
+	ClientIdentities map[string]string `yaml:"client_identities"` // 인증서 CN/SAN → BIZ_SRVC_CD
+}
+
+// Signing: 아웃바운드 요청 서명 (type: hmac | sigv4), 비밀값은 *_env(환경변수 이름) 우선
+type Signing struct {
+	Type string `yaml:"type"`
+
+	// hmac
+	KeyID     string            `yaml:"key_id"`
+	Secret    string            `yaml:"secret"`
+	SecretEnv string            `yaml:"secret_env"`
+	Algorithm string            `yaml:"algorithm"` // sha256 | sha512
+	Headers   map[string]string `yaml:"headers"`   // signature | key_id | date | digest → 헤더 이름
+
+	// sigv4
+	AccessKey       string `yaml:"access_key"`
+	AccessKeyEnv    string `yaml:"access_key_env"`
+	SecretKeyEnv    string `yaml:"secret_key_env"`
+	SessionTokenEnv string `yaml:"session_token_env"`
+	Region          string `yaml:"region"`
+	Service         string `yaml:"service"`
+}
+
+// CORS: 허용 Origin("*", "https://app.example.com", "https://*.example.com") / 메서드 / 헤더
+type CORS struct {
+	AllowedOrigins   []string `yaml:"allowed_origins"`
+	AllowedMethods   []string `yaml:"allowed_methods"` // 비우면 GET, HEAD, POST
+	AllowedHeaders   []string `yaml:"allowed_headers"` // "*" 면 요청 헤더 그대로 허용
+	ExposedHeaders   []string `yaml:"exposed_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	MaxAgeMs         int      `yaml:"max_age_ms"` // preflight 캐시 (Access-Control-Max-Age, 초 단위로 내림)
+}
+
+// IPList: IP / CIDR 허용·차단 목록
+type IPList struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// UpstreamTLS: 업스트림 호출 TLS
+type UpstreamTLS struct {
+	CAFile             string `yaml:"ca_file"`   // 비우면 시스템 루트 CA
+	CertFile           string `yaml:"cert_file"` // mTLS 클라이언트 인증서
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"` // 개발 환경 전용
+	MinVersion         string `yaml:"min_version"`
+}
+type ServerTLSCert struct {
+	CertFile    string   `yaml:"cert_file"`
+	KeyFile     string   `yaml:"key_file"`
+	ServerNames []string `yaml:"server_names"` // SNI 이름 (비우면 인증서 SAN)
+}
+
 type KafkaSASL struct {
 	Enabled   bool   `yaml:"enabled"`
 	Mechanism string `yaml:"mechanism"` // "PLAIN"|"SCRAM-SHA-256"|"SCRAM-SHA-512"
@@ -85,17 +325,44 @@ type KafkaTLS struct {
 	Enabled            bool `yaml:"enabled"`
 	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
 }
+type KafkaSpool struct {
+	Enabled         bool   `yaml:"enabled"`
+	Dir             string `yaml:"dir"`
+	SegmentBytes    int64  `yaml:"segment_bytes"`
+	MaxBytes        int64  `yaml:"max_bytes"`
+	Fsync           string `yaml:"fsync"` // "always"|"interval"|"none"
+	FsyncIntervalMs int    `yaml:"fsync_interval_ms"`
+}
+type KafkaRoute struct {
+	Match struct {
+		RasTyp     []string `yaml:"ras_typ"`
+		ApiGroupCd []string `yaml:"api_group_cd"`
+		BizSrvcCd  []string `yaml:"biz_srvc_cd"`
+	} `yaml:"match"`
+	Topic string `yaml:"topic"`
+	Key   string `yaml:"key"` // "tcId"|"bizSrvcCd"|"apiGroupCd"|"apiCd"|"guid"|"none"
+}
+type KafkaRouting struct {
+	DefaultKey string       `yaml:"default_key"`
+	Rules      []KafkaRoute `yaml:"rules"`
+}
 type KafkaConfig struct {
-	Enabled        bool      `yaml:"enabled"`
-	Brokers        []string  `yaml:"brokers"`
-	ClientID       string    `yaml:"client_id"`
-	Acks           string    `yaml:"acks"`
-	Compression    string    `yaml:"compression"`
-	TimeoutMs      int       `yaml:"timeout_ms"`
-	BatchBytes     int64     `yaml:"batch_bytes"`
-	BatchTimeoutMs int       `yaml:"batch_timeout_ms"`
-	SASL           KafkaSASL `yaml:"sasl"`
-	TLS            KafkaTLS  `yaml:"tls"`
+	Enabled        bool         `yaml:"enabled"`
+	Brokers        []string     `yaml:"brokers"`
+	ClientID       string       `yaml:"client_id"`
+	Acks           string       `yaml:"acks"`
+	Compression    string       `yaml:"compression"`
+	TimeoutMs      int          `yaml:"timeout_ms"`
+	BatchBytes     int64        `yaml:"batch_bytes"`
+	BatchTimeoutMs int          `yaml:"batch_timeout_ms"`
+	BatchSize      int          `yaml:"batch_size"`
+	MaxInFlight    int          `yaml:"max_in_flight"`
+	DrainTimeoutMs int          `yaml:"drain_timeout_ms"`
+	Partitioner    string       `yaml:"partitioner"` // "hash"|"crc32"|"least_bytes"
+	Routing        KafkaRouting `yaml:"routing"`
+	SASL           KafkaSASL    `yaml:"sasl"`
+	TLS            KafkaTLS     `yaml:"tls"`
+	Spool          KafkaSpool   `yaml:"spool"`
 }
 
 var (
@@ -109,14 +376,12 @@ func LoadConfig(path string) {
 
 		file, err := os.ReadFile(path)
 		if err != nil {
-			log.Fatalf("\u274c Failed to read config file: %v", err)
+			log.Fatalf("config: read %s: %v", path, err)
 		}
 
 		if err := yaml.Unmarshal(file, &AppConfig); err != nil {
-			log.Fatalf("\u274c Failed to parse config YAML: %v", err)
+			log.Fatalf("config: parse %s: %v", path, err)
 		}
 
-		//log.Printf("\u2705 Config loaded: GRPC=%d, HTTP=%d, Redis=%s", AppConfig.Application.GrpcPort, AppConfig.Application.HttpPort, AppConfig.Redis.Host)
-
 	})
 }
@@ -0,0 +1,224 @@
+package fwauth
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/*
+X-Fw-Header FwAuthorization 검증
+
+WHY:
+- 메모상 "존재 시 인증 (ENC 내의 코드)" 로 정의된 FwAuthorization 을 아무도 검사하지 않아
+  임의 값이 그대로 업무 서비스로 전달됐음.
+- 업무서비스(BizSrvcCd)에 묶인 단기 토큰으로 발급하고, 게이트웨이가 DB 정책 체크 전에 검증.
+
+형식 (base64url, 패딩 없음 → X-Fw-Header 구분자 ';' '=' 와 충돌 없음):
+- hmac    : <kid>.<payload>.<HMAC-SHA256(key, "<kid>.<payload>")>
+- aes-gcm : <kid>.<nonce(12) || AES-GCM(key, payload, aad=kid)>     (payload 비노출)
+- payload : {"bizSrvcCd":"SMP","sub":"...","iat":1700000000,"exp":1700000300}
+
+키 교체:
+- keys 에 여러 키를 두고 kid 로 선택 → 새 키 추가 후 primary 전환, 이전 키는 not_after 이후 거부
+- 발급(Issue)은 primary 키, 검증은 만료되지 않은 모든 키
+*/
+
+var (
+	ErrMalformed   = errors.New("fwauth: malformed token")
+	ErrUnknownKey  = errors.New("fwauth: unknown or retired key")
+	ErrSignature   = errors.New("fwauth: invalid signature")
+	ErrExpired     = errors.New("fwauth: token expired")
+	ErrNotYetValid = errors.New("fwauth: token not yet valid")
+	ErrBizMismatch = errors.New("fwauth: token bound to another BizSrvcCd")
+)
+
+// Claims: 토큰 내용
+type Claims struct {
+	BizSrvcCd string `json:"bizSrvcCd"`
+	Subject   string `json:"sub,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Verifier: FwAuthorization 검증기 (외부 인증 서버 연동 등으로 교체 가능)
+type Verifier interface {
+	// Verify: 서명/복호화, 만료, BizSrvcCd 일치 확인
+	Verify(ctx context.Context, token, bizSrvcCd string) (*Claims, error)
+}
+
+// Key: 키 1개 (Secret 은 HMAC 32바이트 이상, AES-GCM 16/24/32바이트)
+type Key struct {
+	ID       string
+	Secret   []byte
+	NotAfter time.Time // 이후 이 kid 토큰 거부 (zero 면 무기한)
+}
+
+const (
+	SchemeHMAC   = "hmac"
+	SchemeAESGCM = "aes-gcm"
+)
+
+// Codec: 키 집합 기반 발급/검증 (Verifier 구현)
+type Codec struct {
+	scheme  string
+	keys    map[string]Key
+	aeads   map[string]cipher.AEAD
+	primary string
+	skew    time.Duration
+	now     func() time.Time
+}
+
+// New: primary 가 비어 있으면 마지막 키를 발급용으로 사용
+func New(scheme string, keys []Key, primary string, skew time.Duration) (*Codec, error) {
+	c := &Codec{
+		scheme: strings.ToLower(scheme),
+		keys:   map[string]Key{},
+		aeads:  map[string]cipher.AEAD{},
+		skew:   skew,
+		now:    time.Now,
+	}
+	if c.scheme == "" {
+		c.scheme = SchemeHMAC
+	}
+	if c.scheme != SchemeHMAC && c.scheme != SchemeAESGCM {
+		return nil, fmt.Errorf("fw_auth.scheme: unknown %q (hmac|aes-gcm)", scheme)
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("fw_auth.keys: at least one key required")
+	}
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, ".") {
+			return nil, fmt.Errorf("fw_auth.keys: invalid id %q", k.ID)
+		}
+		if _, dup := c.keys[k.ID]; dup {
+			return nil, fmt.Errorf("fw_auth.keys: duplicate id %q", k.ID)
+		}
+		switch c.scheme {
+		case SchemeHMAC:
+			if len(k.Secret) < 32 {
+				return nil, fmt.Errorf("fw_auth.keys[%s]: hmac secret must be at least 32 bytes", k.ID)
+			}
+		case SchemeAESGCM:
+			block, err := aes.NewCipher(k.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("fw_auth.keys[%s]: %w", k.ID, err)
+			}
+			aead, err := cipher.NewGCM(block)
+			if err != nil {
+				return nil, fmt.Errorf("fw_auth.keys[%s]: %w", k.ID, err)
+			}
+			c.aeads[k.ID] = aead
+		}
+		c.keys[k.ID] = k
+	}
+	c.primary = primary
+	if c.primary == "" {
+		c.primary = keys[len(keys)-1].ID
+	}
+	if _, ok := c.keys[c.primary]; !ok {
+		return nil, fmt.Errorf("fw_auth.primary: unknown key %q", primary)
+	}
+	return c, nil
+}
+
+// Issue: primary 키로 발급 (IssuedAt 미지정 시 현재 시각)
+func (c *Codec) Issue(cl Claims) (string, error) {
+	if cl.IssuedAt == 0 {
+		cl.IssuedAt = c.now().Unix()
+	}
+	payload, err := json.Marshal(cl)
+	if err != nil {
+		return "", err
+	}
+	kid := c.primary
+	switch c.scheme {
+	case SchemeAESGCM:
+		aead := c.aeads[kid]
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		sealed := aead.Seal(nonce, nonce, payload, []byte(kid))
+		return kid + "." + b64(sealed), nil
+	default:
+		signing := kid + "." + b64(payload)
+		return signing + "." + b64(c.mac(kid, signing)), nil
+	}
+}
+
+func (c *Codec) Verify(_ context.Context, token, bizSrvcCd string) (*Claims, error) {
+	kid, rest, ok := strings.Cut(token, ".")
+	if !ok || kid == "" {
+		return nil, ErrMalformed
+	}
+	now := c.now()
+	k, ok := c.keys[kid]
+	if !ok || (!k.NotAfter.IsZero() && now.After(k.NotAfter)) {
+		return nil, ErrUnknownKey
+	}
+
+	var payload []byte
+	switch c.scheme {
+	case SchemeAESGCM:
+		sealed, err := unb64(rest)
+		aead := c.aeads[kid]
+		if err != nil || len(sealed) < aead.NonceSize()+aead.Overhead() {
+			return nil, ErrMalformed
+		}
+		ns := aead.NonceSize()
+		payload, err = aead.Open(nil, sealed[:ns], sealed[ns:], []byte(kid))
+		if err != nil {
+			return nil, ErrSignature
+		}
+	default:
+		p, sig, ok := strings.Cut(rest, ".")
+		if !ok {
+			return nil, ErrMalformed
+		}
+		mac, err := unb64(sig)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		if !hmac.Equal(mac, c.mac(kid, kid+"."+p)) {
+			return nil, ErrSignature
+		}
+		if payload, err = unb64(p); err != nil {
+			return nil, ErrMalformed
+		}
+	}
+
+	var cl Claims
+	if err := json.Unmarshal(payload, &cl); err != nil || cl.ExpiresAt == 0 {
+		return nil, ErrMalformed
+	}
+	if now.After(time.Unix(cl.ExpiresAt, 0).Add(c.skew)) {
+		return nil, ErrExpired
+	}
+	if cl.IssuedAt != 0 && time.Unix(cl.IssuedAt, 0).After(now.Add(c.skew)) {
+		return nil, ErrNotYetValid
+	}
+	if cl.BizSrvcCd != bizSrvcCd {
+		return nil, ErrBizMismatch
+	}
+	return &cl, nil
+}
+
+func (c *Codec) mac(kid, signing string) []byte {
+	h := hmac.New(sha256.New, c.keys[kid].Secret)
+	h.Write([]byte(signing))
+	return h.Sum(nil)
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func unb64(s string) ([]byte, error) { return base64.RawURLEncoding.DecodeString(s) }
@@ -0,0 +1,187 @@
+package fwauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var fwNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func secret(b byte, n int) []byte { return bytes.Repeat([]byte{b}, n) }
+
+func newCodec(t *testing.T, scheme string, keys []Key, primary string) *Codec {
+	t.Helper()
+	c, err := New(scheme, keys, primary, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return fwNow }
+	return c
+}
+
+func claims() Claims {
+	return Claims{BizSrvcCd: "SMP", Subject: "partner-1", ExpiresAt: fwNow.Add(5 * time.Minute).Unix()}
+}
+
+func issue(t *testing.T, c *Codec, cl Claims) string {
+	t.Helper()
+	tok, err := c.Issue(cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, scheme := range []string{SchemeHMAC, SchemeAESGCM} {
+		c := newCodec(t, scheme, []Key{{ID: "k1", Secret: secret(1, 32)}}, "")
+		tok := issue(t, c, claims())
+		if strings.ContainsAny(tok, ";= ") {
+			t.Errorf("%s: token %q collides with X-Fw-Header separators", scheme, tok)
+		}
+		cl, err := c.Verify(context.Background(), tok, "SMP")
+		if err != nil {
+			t.Fatalf("%s: %v", scheme, err)
+		}
+		if cl.BizSrvcCd != "SMP" || cl.Subject != "partner-1" || cl.IssuedAt != fwNow.Unix() {
+			t.Errorf("%s: claims = %+v", scheme, cl)
+		}
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		scheme  string
+		keys    []Key
+		primary string
+	}{
+		{"unknown scheme", "rsa", []Key{{ID: "k1", Secret: secret(1, 32)}}, ""},
+		{"no keys", SchemeHMAC, nil, ""},
+		{"short hmac secret", SchemeHMAC, []Key{{ID: "k1", Secret: secret(1, 31)}}, ""},
+		{"bad aes key size", SchemeAESGCM, []Key{{ID: "k1", Secret: secret(1, 20)}}, ""},
+		{"dot in kid", SchemeHMAC, []Key{{ID: "k.1", Secret: secret(1, 32)}}, ""},
+		{"duplicate kid", SchemeHMAC, []Key{{ID: "k1", Secret: secret(1, 32)}, {ID: "k1", Secret: secret(2, 32)}}, ""},
+		{"unknown primary", SchemeHMAC, []Key{{ID: "k1", Secret: secret(1, 32)}}, "k2"},
+	} {
+		if _, err := New(c.scheme, c.keys, c.primary, 0); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestTampered(t *testing.T) {
+	ctx := context.Background()
+	h := newCodec(t, SchemeHMAC, []Key{{ID: "k1", Secret: secret(1, 32)}}, "")
+	tok := issue(t, h, claims())
+	kid, rest, _ := strings.Cut(tok, ".")
+	payload, sig, _ := strings.Cut(rest, ".")
+
+	// payload 변조 (BizSrvcCd 바꿔치기) → 서명 불일치
+	forged := claims()
+	forged.BizSrvcCd = "ABC"
+	fp, _ := json.Marshal(forged)
+	if _, err := h.Verify(ctx, kid+"."+b64(fp)+"."+sig, "ABC"); !errors.Is(err, ErrSignature) {
+		t.Errorf("tampered payload: %v", err)
+	}
+	// 다른 키로 만든 서명
+	other := newCodec(t, SchemeHMAC, []Key{{ID: "k1", Secret: secret(2, 32)}}, "")
+	if _, err := h.Verify(ctx, issue(t, other, claims()), "SMP"); !errors.Is(err, ErrSignature) {
+		t.Errorf("bad signature: %v", err)
+	}
+	for _, bad := range []string{"", "k1", ".x.y", "k1." + payload, "k1." + payload + ".!!"} {
+		if _, err := h.Verify(ctx, bad, "SMP"); !errors.Is(err, ErrMalformed) {
+			t.Errorf("%q: err = %v, want ErrMalformed", bad, err)
+		}
+	}
+
+	g := newCodec(t, SchemeAESGCM, []Key{{ID: "k1", Secret: secret(1, 32)}}, "")
+	tok = issue(t, g, claims())
+	sealed, _ := unb64(strings.TrimPrefix(tok, "k1."))
+	sealed[len(sealed)-1] ^= 1
+	if _, err := g.Verify(ctx, "k1."+b64(sealed), "SMP"); !errors.Is(err, ErrSignature) {
+		t.Errorf("aes-gcm tampered: %v", err)
+	}
+	if _, err := g.Verify(ctx, "k1."+b64([]byte("short")), "SMP"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("aes-gcm short: %v", err)
+	}
+}
+
+// aes-gcm 은 kid 를 AAD 로 봉인 → 같은 비밀값이라도 다른 kid 로 제시하면 실패
+func TestAADBinding(t *testing.T) {
+	c := newCodec(t, SchemeAESGCM, []Key{{ID: "k1", Secret: secret(1, 32)}, {ID: "k2", Secret: secret(1, 32)}}, "k1")
+	tok := issue(t, c, claims())
+	if _, err := c.Verify(context.Background(), "k2"+strings.TrimPrefix(tok, "k1"), "SMP"); !errors.Is(err, ErrSignature) {
+		t.Errorf("kid swap: %v", err)
+	}
+}
+
+func TestTimeClaims(t *testing.T) {
+	c := newCodec(t, SchemeHMAC, []Key{{ID: "k1", Secret: secret(1, 32)}}, "")
+	for _, tc := range []struct {
+		name string
+		exp  time.Duration
+		iat  time.Duration
+		want error
+	}{
+		{"valid", time.Minute, 0, nil},
+		{"exp within skew", -20 * time.Second, -time.Minute, nil},
+		{"exp past skew", -40 * time.Second, -time.Minute, ErrExpired},
+		{"iat within skew", time.Minute, 20 * time.Second, nil},
+		{"iat past skew", time.Minute, 40 * time.Second, ErrNotYetValid},
+	} {
+		cl := claims()
+		cl.ExpiresAt = fwNow.Add(tc.exp).Unix()
+		cl.IssuedAt = fwNow.Add(tc.iat).Unix()
+		if _, err := c.Verify(context.Background(), issue(t, c, cl), "SMP"); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+
+	cl := claims()
+	cl.ExpiresAt = 0
+	if _, err := c.Verify(context.Background(), issue(t, c, cl), "SMP"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("exp missing: %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	old := Key{ID: "k1", Secret: secret(1, 32), NotAfter: fwNow.Add(time.Hour)}
+	c := newCodec(t, SchemeHMAC, []Key{old, {ID: "k2", Secret: secret(2, 32)}}, "")
+	if c.primary != "k2" {
+		t.Fatalf("primary = %s, want last key", c.primary)
+	}
+
+	oldIssuer := newCodec(t, SchemeHMAC, []Key{old}, "")
+	oldTok := issue(t, oldIssuer, claims())
+	if _, err := c.Verify(ctx, oldTok, "SMP"); err != nil {
+		t.Errorf("old key before not_after: %v", err)
+	}
+	c.now = func() time.Time { return fwNow.Add(2 * time.Hour) }
+	if _, err := c.Verify(ctx, oldTok, "SMP"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("retired key: %v", err)
+	}
+
+	unknown := newCodec(t, SchemeHMAC, []Key{{ID: "k9", Secret: secret(1, 32)}}, "")
+	if _, err := c.Verify(ctx, issue(t, unknown, claims()), "SMP"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("unknown kid: %v", err)
+	}
+}
+
+func TestBizMismatch(t *testing.T) {
+	for _, scheme := range []string{SchemeHMAC, SchemeAESGCM} {
+		c := newCodec(t, scheme, []Key{{ID: "k1", Secret: secret(1, 32)}}, "")
+		tok := issue(t, c, claims())
+		for _, biz := range []string{"ABC", ""} {
+			if _, err := c.Verify(context.Background(), tok, biz); !errors.Is(err, ErrBizMismatch) {
+				t.Errorf("%s / %q: %v", scheme, biz, err)
+			}
+		}
+	}
+}
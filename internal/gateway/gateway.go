@@ -9,6 +9,11 @@ import (
 	"regexp"
 	"strings"
 
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/logx"
+	"service-gateway/internal/middleware"
+	"service-gateway/internal/model"
 	"service-gateway/internal/router"
 	httpadapter "service-gateway/internal/router/adapter/http"
 )
@@ -40,6 +45,9 @@ func toPattern(path string) string {
 type routeOptions struct {
 	validateJSON func([]byte) error // 본문 JSON 검증기(있으면 400 처리)
 	asyncAck     bool               // true면 202 반환 후 백그라운드에서 프록시
+	scopes       []string           // JWT 필요 scope
+	claims       map[string]string  // JWT 필요 클레임 값
+	ipFilter     *ipfilter.List     // 클라이언트 IP 허용/차단
 }
 
 type Gateway struct {
@@ -69,7 +77,27 @@ func WithAsyncAck() RouteOption {
 	return func(o *routeOptions) { o.asyncAck = true }
 }
 
+// JWT scope 요구 (middleware.JWTAuth 이후, 부족 시 403)
+func WithScopes(scopes ...string) RouteOption {
+	return func(o *routeOptions) { o.scopes = append(o.scopes, scopes...) }
+}
+
+// JWT 클레임 값 요구 (예: {"tenant": "kr"})
+func WithClaims(claims map[string]string) RouteOption {
+	return func(o *routeOptions) { o.claims = claims }
+}
+
+// 클라이언트 IP 허용/차단 (ipfilter.Parse, 위반 시 403)
+func WithIPFilter(l *ipfilter.List) RouteOption {
+	return func(o *routeOptions) { o.ipFilter = l }
+}
+
 func (g *Gateway) add(method, path string, up Upstream, opts ...RouteOption) {
+	ro := routeOptions{}
+	for _, opt := range opts {
+		opt(&ro)
+	}
+
 	methods := map[string]struct{}{strings.ToUpper(method): {}}
 	route := router.Route{
 		Name: method + " " + path,
@@ -83,13 +111,9 @@ func (g *Gateway) add(method, path string, up Upstream, opts ...RouteOption) {
 			Method:      up.Method,      // 비워두면 원본 메서드
 			PathRewrite: up.PathRewrite, // 비우면 원본 경로
 		},
+		Options: router.RouteOptions{Scopes: ro.scopes, Claims: ro.claims, IPFilter: ro.ipFilter},
 	}
 	g.routes = append(g.routes, route)
-
-	ro := routeOptions{}
-	for _, opt := range opts {
-		opt(&ro)
-	}
 	g.opts[route.Name] = ro
 }
 
@@ -118,6 +142,11 @@ func (g *Gateway) Handler(fallback http.Handler) http.Handler {
 			fallback.ServeHTTP(w, r)
 			return
 		}
+		logx.SetRoute(r.Context(), rt.Name)
+		if ge := middleware.AuthorizeRoute(r.Context(), rt.Options); ge != nil {
+			httpx.WriteError(w, r, ge)
+			return
+		}
 
 		// 업스트림 메서드
 		upMethod := r.Method
@@ -133,12 +162,12 @@ func (g *Gateway) Handler(fallback http.Handler) http.Handler {
 		if opt, ok := g.opts[rt.Name]; ok && opt.validateJSON != nil {
 			raw, err := ioReadAllAndClose(r.Body)
 			if err != nil {
-				http.Error(w, "invalid request body", http.StatusBadRequest)
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeBadRequest, err))
 				return
 			}
 			if len(raw) > 0 {
 				if err := opt.validateJSON(raw); err != nil {
-					http.Error(w, "validation failed: "+err.Error(), http.StatusBadRequest)
+					httpx.WriteError(w, r, httpx.Err(model.ErrCodeValidation, nil).WithMessage(err.Error()))
 					return
 				}
 			}
@@ -160,7 +189,7 @@ func (g *Gateway) Handler(fallback http.Handler) http.Handler {
 		}
 		reqUp, err := http.NewRequestWithContext(r.Context(), upMethod, upstreamURL, reqBody)
 		if err != nil {
-			http.Error(w, "build upstream request failed", http.StatusInternalServerError)
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeInternal, err))
 			return
 		}
 		copyProxyHeaders(reqUp.Header, r.Header)
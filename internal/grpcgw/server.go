@@ -0,0 +1,340 @@
+package grpcgw
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"service-gateway/internal/audit"
+	"service-gateway/internal/fwauth"
+	"service-gateway/internal/header"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/logx"
+	"service-gateway/internal/middleware"
+	"service-gateway/internal/model"
+	"strings"
+	"time"
+
+	pb "service-gateway/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+/*
+gRPC 리스너 (HelloService / grpc.health.v1.Health / SessionService 프록시)
+
+WHY:
+- proto 에 HelloService / SessionService 가 있고 grpc 도 의존성이지만 게이트웨이는 HTTP 만 수신했음.
+- gRPC 경로도 HTTP 와 같은 인증 / 요청 한도 / 감사 로그를 거쳐야 우회 경로가 되지 않음
+  → HTTP 쪽 인스턴스(JWTAuth, RateLimiter, DynamicGateway 인증, IP 필터, 감사 Emitter)를 그대로 공유.
+
+파이프라인 (unary 인터셉터):
+1. Health          : 인증 / 한도 / 감사 제외 (로드밸런서 헬스체크)
+2. 요청 한도        : middleware.ratelimit 버킷 공유, 초과 시 ResourceExhausted [18]
+3. HelloService    : 통신 확인용, 인증 제외
+4. 인증            : authorization: Bearer (jwt), x-api-key / 클라이언트 인증서 (AuthenticateCaller),
+                     업무서비스 IP 허용/차단, x-fw-header FwAuthorization
+5. API 권한         : /gateway 와 같은 카탈로그 검사 (AuthorizeAPI) - REQ_URL 은 gRPC full method
+                     ("/servie.gateway.SessionService/GetSession"), 미등록 14 / 사용 권한 15 / 그룹 16 / 점검 시간대
+6. 감사 로그        : SessionService 만 11 → 21 → 22 → 12 (apiPath = /패키지.서비스/메서드)
+
+게이트웨이 에러: status 메시지 "[코드] 문구" + 트레일러 x-fw-error-code (HTTP 에러 코드와 동일 카탈로그)
+메타데이터: x-fw-header (HTTP X-Fw-Header 와 동일 형식), 응답 헤더 x-fw-header 는 TCIDSRNO +1
+*/
+
+// Authenticator: 클라이언트 인증서 / API 키 → BizSrvcCd (handlers.DynamicGateway)
+type Authenticator interface {
+	AuthenticateCaller(r *http.Request, claimed string, bizCode *string) *httpx.Error
+}
+
+// Catalog: API 카탈로그 / 사용 권한 / 점검 시간대 (handlers.DynamicGateway)
+type Catalog interface {
+	AuthorizeAPI(ctx context.Context, bizCode, apiPath string) (model.RequestData, *httpx.Error)
+}
+
+type Config struct {
+	TLS            *tls.Config // nil 이면 평문 (server.tls 와 같은 인증서 / mTLS)
+	JWT            *middleware.JWTAuth
+	JWTRequired    bool
+	Callers        Authenticator      // nil 이면 인증서 / API 키 미사용
+	Catalog        Catalog            // nil 이면 카탈로그 검사 생략 (테스트용, 운영은 main 에서 항상 설정)
+	IPFilter       *ipfilter.Registry // 업무서비스별 IP 허용/차단
+	FwAuth         fwauth.Verifier
+	FwAuthRequired bool
+	RateLimit      *middleware.RateLimiter // HTTP 와 같은 인스턴스
+	Audit          *audit.Emitter          // HTTP /gateway 와 같은 Emitter (nil 이면 미적재)
+
+	Session       pb.SessionServiceClient // nil 이면 SessionService 미제공
+	SessionTarget string                  // 감사 로그 21/22 apiPath
+	Timeout       time.Duration           // 세션 백엔드 호출 상한 (기본 5s, 클라이언트 deadline 이 짧으면 그쪽)
+
+	Logger *slog.Logger
+}
+
+type Server struct {
+	cfg    Config
+	srv    *grpc.Server
+	health *health.Server
+	log    *slog.Logger
+}
+
+func New(cfg Config) *Server {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.Audit == nil {
+		cfg.Audit = &audit.Emitter{} // Sink 없음 → 적재 안 함
+	}
+	s := &Server{cfg: cfg, health: health.NewServer(), log: logx.Or(cfg.Logger)}
+	opts := []grpc.ServerOption{grpc.ChainUnaryInterceptor(s.unary)}
+	if cfg.TLS != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(cfg.TLS)))
+	}
+	s.srv = grpc.NewServer(opts...)
+
+	pb.RegisterHelloServiceServer(s.srv, helloServer{})
+	s.health.SetServingStatus(pb.HelloService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	if cfg.Session != nil {
+		pb.RegisterSessionServiceServer(s.srv, &sessionProxy{s: s})
+		s.health.SetServingStatus(pb.SessionService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(s.srv, s.health)
+	return s
+}
+
+func (s *Server) Serve(lis net.Listener) error { return s.srv.Serve(lis) }
+
+// GracefulStop: 헬스 NOT_SERVING 전환 후 진행 중 호출 완료 대기 (ctx 만료 시 강제 종료)
+func (s *Server) GracefulStop(ctx context.Context) {
+	s.health.Shutdown()
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.srv.Stop()
+	}
+}
+
+type callKey struct{}
+
+// call: 인터셉터가 확정한 요청 컨텍스트 (프록시 핸들러에서 사용)
+type call struct {
+	fw    map[string]string // 서버 기준 필드가 적용된 x-fw-header
+	trail *audit.Trail
+}
+
+func callFrom(ctx context.Context) *call {
+	c, _ := ctx.Value(callKey{}).(*call)
+	return c
+}
+
+func (s *Server) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	svc, _, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+	if svc == healthpb.Health_ServiceDesc.ServiceName {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	r := s.request(ctx, info.FullMethod, md)
+	inFw := header.Parse(r.Header.Get("X-Fw-Header"))
+	ctx = logx.WithTCID(ctx, inFw["TCID"])
+	logx.SetRoute(ctx, info.FullMethod)
+	ip := peerIP(ctx)
+	if ip.IsValid() {
+		logx.SetClientIP(ctx, ip.String())
+	}
+	r = r.WithContext(ctx)
+
+	if !s.cfg.RateLimit.Allow() {
+		return nil, s.status(ctx, &httpx.Error{Code: model.ErrCodeRateLimited, RetryAfter: time.Second})
+	}
+	if svc == pb.HelloService_ServiceDesc.ServiceName {
+		return handler(ctx, req)
+	}
+
+	// 인증 (실패는 감사 로그 시작 후 응답 - HTTP /gateway 와 동일)
+	bizCode, claimed := "SMP", inFw["BizSrvcCd"]
+	if claimed != "" {
+		bizCode = claimed
+	}
+	authErr := s.authenticate(r, inFw, claimed, &bizCode, ip)
+	merged := header.ApplyServerSideFields(inFw, bizCode, r.Host)
+	logx.SetTCID(ctx, merged["TCID"])
+
+	c := &call{fw: merged, trail: s.cfg.Audit.Begin(r, merged)}
+	c.trail.Request(auditBody(req))
+	if authErr == nil && s.cfg.Catalog != nil {
+		var rd model.RequestData
+		rd, authErr = s.cfg.Catalog.AuthorizeAPI(ctx, bizCode, info.FullMethod)
+		c.trail.Resolve(rd.ApiGroupCode, rd.ApiCode)
+	}
+	if authErr != nil {
+		c.trail.Respond(authErr.StatusCode(), authErr.Code, nil)
+		return nil, s.status(ctx, authErr)
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("x-fw-header", header.BumpTCIDSRNO(header.Serialize(merged))))
+	resp, err := handler(context.WithValue(ctx, callKey{}, c), req)
+	if err != nil {
+		var ge *httpx.Error
+		if errors.As(err, &ge) {
+			c.trail.Respond(ge.StatusCode(), ge.Code, nil)
+			return nil, s.status(ctx, ge)
+		}
+		c.trail.Respond(httpStatus(status.Code(err)), "", nil)
+		return nil, err
+	}
+	c.trail.Respond(http.StatusOK, "", auditBody(resp))
+	return resp, nil
+}
+
+// authenticate: jwt → 인증서 / API 키 → 업무서비스 IP → FwAuthorization
+func (s *Server) authenticate(r *http.Request, inFw map[string]string, claimed string, bizCode *string, ip netip.Addr) *httpx.Error {
+	ctx := r.Context()
+	if s.cfg.JWT != nil {
+		tok, ok := middleware.BearerToken(r)
+		switch {
+		case ok:
+			if _, err := s.cfg.JWT.Verify(ctx, tok); err != nil {
+				return httpx.Err(model.ErrCodeUnauthorized, err)
+			}
+		case s.cfg.JWTRequired:
+			return httpx.Err(model.ErrCodeUnauthorized, errors.New("jwt: bearer token required"))
+		}
+	}
+	if s.cfg.Callers != nil {
+		if ge := s.cfg.Callers.AuthenticateCaller(r, claimed, bizCode); ge != nil {
+			return ge
+		}
+	}
+	if !s.cfg.IPFilter.Allowed(ctx, *bizCode, ip) {
+		return httpx.Err(model.ErrCodeIPForbidden, errors.New("ipfilter: "+ip.String()+" not allowed for "+*bizCode))
+	}
+	if s.cfg.FwAuth != nil {
+		if tok := inFw["FwAuthorization"]; tok != "" {
+			if _, err := s.cfg.FwAuth.Verify(ctx, tok, *bizCode); err != nil {
+				return httpx.Err(model.ErrCodeUnauthorized, err)
+			}
+		} else if s.cfg.FwAuthRequired {
+			return httpx.Err(model.ErrCodeUnauthorized, errors.New("fwauth: FwAuthorization required"))
+		}
+	}
+	return nil
+}
+
+// request: 메타데이터 / 피어 TLS → HTTP 요청 형태 (HTTP 경로의 인증·감사 코드 재사용)
+func (s *Server) request(ctx context.Context, method string, md metadata.MD) *http.Request {
+	r := &http.Request{
+		Method:     http.MethodPost,
+		URL:        &url.URL{Path: method},
+		Proto:      "HTTP/2.0",
+		ProtoMajor: 2,
+		Header:     http.Header{},
+	}
+	for k, vs := range md {
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		for _, v := range vs {
+			r.Header.Add(k, v)
+		}
+	}
+	if a := md.Get(":authority"); len(a) > 0 {
+		r.Host = a[0]
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			r.RemoteAddr = p.Addr.String()
+		}
+		if ti, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			r.TLS = &ti.State
+		}
+	}
+	return r.WithContext(ctx)
+}
+
+// status: 게이트웨이 에러 → gRPC status (원인은 서버 로그에만)
+func (s *Server) status(ctx context.Context, ge *httpx.Error) error {
+	level := slog.LevelWarn
+	if ge.StatusCode() >= 500 {
+		level = slog.LevelError
+	}
+	s.log.Log(ctx, level, "gateway error", "code", ge.Code, "status", ge.StatusCode(), "cause", ge.Cause)
+	_ = grpc.SetTrailer(ctx, metadata.Pairs("x-fw-error-code", ge.Code))
+	return status.Error(grpcCode(ge.StatusCode()), "["+ge.Code+"] "+ge.Text())
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests, http.StatusRequestEntityTooLarge:
+		return codes.ResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
+
+// httpStatus: 감사 로그 status (HTTP 경로와 같은 기준으로 집계)
+func httpStatus(c codes.Code) int {
+	switch c {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+func peerIP(ctx context.Context) netip.Addr {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}
+	}
+	ap, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return netip.Addr{}
+	}
+	return ap.Addr().Unmap()
+}
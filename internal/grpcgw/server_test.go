@@ -0,0 +1,228 @@
+package grpcgw
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"service-gateway/internal/header"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/middleware"
+	"service-gateway/internal/model"
+	"service-gateway/internal/session/sessiontest"
+	pb "service-gateway/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// bufDial: bufconn 위에 서버를 띄우고 연결된 ClientConn 반환
+func bufDial(t *testing.T, serve func(net.Listener) error, stop func()) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = serve(lis) }()
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cc.Close()
+		stop()
+	})
+	return cc
+}
+
+// startGateway: FakeServer(세션 백엔드) + 게이트웨이 Server, 게이트웨이에 연결된 ClientConn
+func startGateway(t *testing.T, cfg Config) (*sessiontest.FakeServer, *grpc.ClientConn) {
+	t.Helper()
+	fake := sessiontest.NewFakeServer()
+	backend := grpc.NewServer()
+	pb.RegisterSessionServiceServer(backend, fake)
+	cfg.Session = pb.NewSessionServiceClient(bufDial(t, backend.Serve, backend.Stop))
+	cfg.SessionTarget = "bufconn"
+
+	s := New(cfg)
+	return fake, bufDial(t, s.Serve, s.srv.Stop)
+}
+
+// fakeCatalog: AuthorizeAPI 호출 기록 + 지정 에러
+type fakeCatalog struct {
+	mu    sync.Mutex
+	paths []string
+	err   *httpx.Error
+}
+
+func (f *fakeCatalog) AuthorizeAPI(_ context.Context, bizCode, apiPath string) (model.RequestData, *httpx.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.paths = append(f.paths, bizCode+" "+apiPath)
+	return model.RequestData{ApiGroupCode: "009", ApiCode: "S01"}, f.err
+}
+
+func testJWT(t *testing.T) (*middleware.JWTAuth, string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := middleware.NewJWTAuth(middleware.JWTConfig{Keys: middleware.StaticJWKS(map[string]crypto.PublicKey{"k": pub})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seg := func(v any) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signing := seg(map[string]string{"alg": "EdDSA", "kid": "k"}) + "." + seg(map[string]any{"sub": "svc", "exp": time.Now().Add(time.Hour).Unix()})
+	return a, signing + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(priv, []byte(signing)))
+}
+
+// wantGatewayError: status 코드 + 메시지 "[코드] ..." + 트레일러 x-fw-error-code
+func wantGatewayError(t *testing.T, err error, trailer metadata.MD, code codes.Code, gwCode string) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code || !strings.HasPrefix(st.Message(), "["+gwCode+"] ") {
+		t.Errorf("status = %s %q, want %s [%s]", st.Code(), st.Message(), code, gwCode)
+	}
+	if got := trailer.Get("x-fw-error-code"); len(got) != 1 || got[0] != gwCode {
+		t.Errorf("x-fw-error-code = %q, want %s", got, gwCode)
+	}
+}
+
+func TestHealthBypassesAuthAndRateLimit(t *testing.T) {
+	jwt, _ := testJWT(t)
+	rl := middleware.NewRateLimiter(0.001, 1)
+	rl.Allow() // 버킷 소진
+	_, cc := startGateway(t, Config{JWT: jwt, JWTRequired: true, RateLimit: rl})
+
+	resp, err := healthpb.NewHealthClient(cc).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: pb.SessionService_ServiceDesc.ServiceName,
+	})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("health: %v %v", resp, err)
+	}
+}
+
+func TestHelloWithoutAuth(t *testing.T) {
+	jwt, _ := testJWT(t)
+	cat := &fakeCatalog{err: httpx.Err(model.ErrCodeApiForbidden, nil)}
+	_, cc := startGateway(t, Config{JWT: jwt, JWTRequired: true, Catalog: cat})
+
+	resp, err := pb.NewHelloServiceClient(cc).SayHello(context.Background(), &pb.HelloRequest{Name: "me"})
+	if err != nil || resp.GetMessage() != "Hello, me" {
+		t.Fatalf("hello: %v %v", resp, err)
+	}
+	if len(cat.paths) != 0 {
+		t.Errorf("catalog consulted for HelloService: %v", cat.paths)
+	}
+}
+
+func TestSessionRequiresToken(t *testing.T) {
+	jwt, token := testJWT(t)
+	fake, cc := startGateway(t, Config{JWT: jwt, JWTRequired: true})
+	client := pb.NewSessionServiceClient(cc)
+
+	var trailer metadata.MD
+	_, err := client.GetSession(context.Background(), &pb.GetSessionRequest{Key: "k"}, grpc.Trailer(&trailer))
+	wantGatewayError(t, err, trailer, codes.Unauthenticated, model.ErrCodeUnauthorized)
+
+	trailer = nil
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer not.a.jwt")
+	_, err = client.GetSession(ctx, &pb.GetSessionRequest{Key: "k"}, grpc.Trailer(&trailer))
+	wantGatewayError(t, err, trailer, codes.Unauthenticated, model.ErrCodeUnauthorized)
+	if fake.Calls() != 0 {
+		t.Errorf("backend called %d times for rejected calls", fake.Calls())
+	}
+
+	// 스킴 대소문자 무시 (HTTP 와 같은 규칙)
+	ctx = metadata.AppendToOutgoingContext(context.Background(), "authorization", "bearer "+token)
+	if _, err := client.GetSession(ctx, &pb.GetSessionRequest{Key: "k"}); err != nil {
+		t.Errorf("lowercase bearer: %v", err)
+	}
+}
+
+func TestSessionCatalogForbidden(t *testing.T) {
+	cat := &fakeCatalog{err: httpx.Err(model.ErrCodeApiForbidden, nil)}
+	fake, cc := startGateway(t, Config{Catalog: cat})
+
+	var trailer metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-fw-header", "BizSrvcCd=ABC")
+	_, err := pb.NewSessionServiceClient(cc).GetSession(ctx, &pb.GetSessionRequest{Key: "k"}, grpc.Trailer(&trailer))
+	wantGatewayError(t, err, trailer, codes.PermissionDenied, model.ErrCodeApiForbidden)
+	if want := "ABC " + pb.SessionService_GetSession_FullMethodName; len(cat.paths) != 1 || cat.paths[0] != want {
+		t.Errorf("catalog calls = %q, want [%q]", cat.paths, want)
+	}
+	if fake.Calls() != 0 {
+		t.Errorf("backend called despite 403")
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	_, cc := startGateway(t, Config{RateLimit: middleware.NewRateLimiter(0.001, 1)})
+	client := pb.NewHelloServiceClient(cc)
+
+	if _, err := client.SayHello(context.Background(), &pb.HelloRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	var trailer metadata.MD
+	_, err := client.SayHello(context.Background(), &pb.HelloRequest{}, grpc.Trailer(&trailer))
+	wantGatewayError(t, err, trailer, codes.ResourceExhausted, model.ErrCodeRateLimited)
+}
+
+func TestSessionForward(t *testing.T) {
+	cat := &fakeCatalog{}
+	fake, cc := startGateway(t, Config{Catalog: cat})
+	fake.Put("sess-1", "v", 0)
+	client := pb.NewSessionServiceClient(cc)
+
+	var hdr metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-fw-header", "TCID=T1;TCIDSRNO=1")
+	resp, err := client.GetSession(ctx, &pb.GetSessionRequest{Key: "sess-1"}, grpc.Header(&hdr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.GetSuccess() || resp.GetMessage() != "v" {
+		t.Errorf("resp = %v", resp)
+	}
+	if fw := hdr.Get("x-fw-header"); len(fw) != 1 || header.Parse(fw[0])["BizSrvcCd"] != "SMP" {
+		t.Errorf("x-fw-header = %q", fw)
+	}
+
+	if _, err := client.CreateSession(context.Background(), &pb.CreateSessionRequest{Key: "sess-2", Ttl: 60}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := client.GetSession(context.Background(), &pb.GetSessionRequest{Key: "sess-2"}); !resp.GetSuccess() {
+		t.Error("created session not forwarded")
+	}
+	if len(cat.paths) != 3 || cat.paths[1] != "SMP "+pb.SessionService_CreateSession_FullMethodName {
+		t.Errorf("catalog calls = %q", cat.paths)
+	}
+
+	// 백엔드 Unavailable → 게이트웨이 19, 그 외 백엔드 status 는 그대로
+	var trailer metadata.MD
+	fake.FailNext(1, codes.Unavailable)
+	_, err = client.GetSession(context.Background(), &pb.GetSessionRequest{Key: "sess-1"}, grpc.Trailer(&trailer))
+	wantGatewayError(t, err, trailer, codes.Unavailable, model.ErrCodeUpstreamFailed)
+
+	fake.FailNext(1, codes.FailedPrecondition)
+	_, err = client.GetSession(context.Background(), &pb.GetSessionRequest{Key: "sess-1"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("backend status not passed through: %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package grpcgw
+
+import (
+	"context"
+	"errors"
+	"service-gateway/internal/header"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/model"
+
+	pb "service-gateway/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// helloServer: 통신 확인
+type helloServer struct {
+	pb.UnimplementedHelloServiceServer
+}
+
+func (helloServer) SayHello(_ context.Context, r *pb.HelloRequest) (*pb.HelloResponse, error) {
+	name := r.GetName()
+	if name == "" {
+		name = "service-gateway"
+	}
+	return &pb.HelloResponse{Message: "Hello, " + name}, nil
+}
+
+// sessionProxy: SessionService → 세션 백엔드 (session.grpc.target) 전달
+type sessionProxy struct {
+	pb.UnimplementedSessionServiceServer
+	s *Server
+}
+
+func (p *sessionProxy) SetSession(ctx context.Context, r *pb.SetSessionRequest) (*pb.GenericResponse, error) {
+	return forward(ctx, p.s, r, p.s.cfg.Session.SetSession)
+}
+
+func (p *sessionProxy) CreateSession(ctx context.Context, r *pb.CreateSessionRequest) (*pb.GenericResponse, error) {
+	return forward(ctx, p.s, r, p.s.cfg.Session.CreateSession)
+}
+
+func (p *sessionProxy) GetSession(ctx context.Context, r *pb.GetSessionRequest) (*pb.GenericResponse, error) {
+	return forward(ctx, p.s, r, p.s.cfg.Session.GetSession)
+}
+
+func (p *sessionProxy) SetSessionWithTarget(ctx context.Context, r *pb.SetSessionWithTargetRequest) (*pb.GenericResponse, error) {
+	return forward(ctx, p.s, r, p.s.cfg.Session.SetSessionWithTarget)
+}
+
+func (p *sessionProxy) GetSessionWithTarget(ctx context.Context, r *pb.GetSessionWithTargetRequest) (*pb.GenericResponse, error) {
+	return forward(ctx, p.s, r, p.s.cfg.Session.GetSessionWithTarget)
+}
+
+// forward: x-fw-header(서버 기준) 만 전달, 백엔드 연결 실패 / 시간 초과는 게이트웨이 에러(19 / 20)
+func forward[Req proto.Message](ctx context.Context, s *Server, req Req,
+	fn func(context.Context, Req, ...grpc.CallOption) (*pb.GenericResponse, error)) (*pb.GenericResponse, error) {
+	c := callFrom(ctx)
+	out := metadata.Pairs("x-fw-header", header.Serialize(c.fw))
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("x-fw-session-id"); len(v) > 0 {
+			out.Set("x-fw-session-id", v...)
+		}
+	}
+	uctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(ctx, out), s.cfg.Timeout)
+	defer cancel()
+
+	c.trail.Send(s.cfg.SessionTarget+callPath(ctx), auditBody(req))
+	resp, err := fn(uctx, req)
+	if err != nil {
+		st := status.Convert(err)
+		c.trail.Receive(httpStatus(st.Code()), nil)
+		switch {
+		case st.Code() == codes.DeadlineExceeded || errors.Is(uctx.Err(), context.DeadlineExceeded):
+			return nil, httpx.Err(model.ErrCodeUpstreamTimeout, err)
+		case st.Code() == codes.Unavailable:
+			return nil, httpx.Err(model.ErrCodeUpstreamFailed, err)
+		}
+		return nil, err // 백엔드 응답 status 그대로
+	}
+	c.trail.Receive(httpStatus(codes.OK), auditBody(resp))
+	return resp, nil
+}
+
+func callPath(ctx context.Context) string {
+	if m, ok := grpc.Method(ctx); ok {
+		return m
+	}
+	return ""
+}
+
+// auditBody: 감사 로그 data (비밀번호 필드는 적재 전 제거, 나머지는 감사 마스킹 규칙 적용)
+func auditBody(m any) []byte {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+	switch v := msg.(type) {
+	case *pb.SetSessionWithTargetRequest:
+		c := proto.Clone(v).(*pb.SetSessionWithTargetRequest)
+		c.Password = ""
+		msg = c
+	case *pb.GetSessionWithTargetRequest:
+		c := proto.Clone(v).(*pb.GetSessionWithTargetRequest)
+		c.Password = ""
+		msg = c
+	}
+	b, _ := protojson.Marshal(msg)
+	return b
+}
@@ -4,15 +4,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"service-gateway/internal/apikey"
+	"service-gateway/internal/audit"
 	config "service-gateway/internal/configs"
+	"service-gateway/internal/fwauth"
 	"service-gateway/internal/header"
 	"service-gateway/internal/httpx"
-	"service-gateway/internal/kafkax"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/logx"
+	"service-gateway/internal/maintenance"
+	"service-gateway/internal/middleware"
 	"service-gateway/internal/model"
+	httpadapter "service-gateway/internal/router/adapter/http"
 	"service-gateway/internal/store"
+	"service-gateway/internal/tlsx"
 	"strings"
 	"time"
 
@@ -20,9 +29,17 @@ import (
 )
 
 type DynamicGateway struct {
-	Repo   store.Repository
-	Client *http.Client
-	Log    kafkax.Publisher // kafka
+	Repo           store.Repository
+	Client         *http.Client         // Transport 는 httpadapter.Transport 공유 (main)
+	Audit          *audit.Emitter       // 감사 로그 (Sink / 토픽 라우팅 / 단계별 적재 여부)
+	Maintenance    *maintenance.Checker // 점검 시간대 (nil 이면 미사용)
+	Logger         *slog.Logger         // nil 이면 slog.Default
+	FwAuth         fwauth.Verifier      // X-Fw-Header FwAuthorization 검증 (nil 이면 미사용)
+	FwAuthRequired bool                 // true 면 FwAuthorization 없는 요청도 401
+	APIKeys        *apikey.Resolver     // X-Api-Key 인증 (nil 이면 미사용)
+	APIKeyRequired bool                 // true 면 X-Api-Key 없는 요청도 401
+	ClientCerts    *tlsx.IdentityMap    // mTLS 인증서 CN/SAN → BIZ_SRVC_CD (nil 이면 미사용)
+	IPFilter       *ipfilter.Registry   // 업무서비스별 IP 허용/차단 (nil 이면 미사용)
 }
 
 type requestBody struct {
@@ -30,52 +47,109 @@ type requestBody struct {
 	Data json.RawMessage `json:"data"`
 }
 
-// kafka
-type gwLog struct {
-	Ts           string `json:"timeStamp"`
-	Tcid         string `json:"tcId"`
-	TcidSrno     string `json:"tcIdSrno"`
-	TcidCreMabd  string `json:"tcIdCreMabd"`
-	BizSrvcCd    string `json:"bizSrvcCd"`
-	BizSrvcIp    string `json:"bizSrvcIp"`
-	RasTyp       string `json:"rasTyp"`
-	NmlYn        string `json:"nmlYn"`
-	ApiPath      string `json:"apiPath"`
-	CmnSrvcCd    string `json:"cmnSrvcCd"`
-	CmnBizSrvcCd string `json:"cmnBizSrvcCd"`
-	ApiGroupCd   string `json:"apiGroupCd"`
-	ApiCd        string `json:"apiCd"`
-	Guid         string `json:"guid"`
-	InterId      string `json:"interId"`
-	MessageCd    string `json:"messageCd"`
-	HostName     string `json:"hostName"`
-	Body         string `json:"data,omitempty"`
-	//Phase     string                 `json:"phase"` // inbound_request, outbound_request, outbound_response, inbound_response
-	//Method    string                 `json:"method"`
-	//URL       string                 `json:"url"`
-	//Status    int                    `json:"status,omitempty"`
-	//LatencyMs int64                  `json:"latency_ms,omitempty"`
-	//Headers   map[string][]string    `json:"headers"`
-	//Body      string                 `json:"body,omitempty"`
-	GroupCode string `json:"group_code,omitempty"`
-	ApiCode   string `json:"api_code,omitempty"`
-	//Upstream  string                 `json:"upstream,omitempty"`
-	//Meta      map[string]interface{} `json:"meta,omitempty"`
+func NewDynamicGateway(repo store.Repository, timeout time.Duration, log audit.Sink) *DynamicGateway {
+	h := &DynamicGateway{
+		Repo:   repo,
+		Client: &http.Client{Timeout: timeout},
+	}
+	h.Audit = audit.NewEmitter(log, h.logEnabled)
+	h.Audit.GroupCode = config.AppConfig.Application.GroupCode
+	return h
 }
 
-func truncate(s []byte, n int) string {
-	if len(s) <= n {
-		return string(s)
+// logEnabled: 단계별 적재 여부 = application.log.* 키가 SID_API_EST_MNG 에 등록되어 있는지
+func (h *DynamicGateway) logEnabled(ctx context.Context, p audit.Phase) bool {
+	configlog := config.AppConfig.Application.Log
+	var key string
+	switch p {
+	case audit.InboundRequest:
+		key = configlog.Inbound.Request
+	case audit.InboundResponse:
+		key = configlog.Inbound.Response
+	case audit.OutboundRequest:
+		key = configlog.Outbound.Request
+	case audit.OutboundResponse:
+		key = configlog.Outbound.Response
 	}
-	return string(s[:n])
+	ok, _ := h.Repo.ExistConfig(ctx, key)
+	return ok
 }
 
-func NewDynamicGateway(repo store.Repository, timeout time.Duration, log kafkax.Publisher) *DynamicGateway {
-	return &DynamicGateway{
-		Repo:   repo,
-		Client: &http.Client{Timeout: timeout},
-		Log:    log,
+// AuthenticateCaller: 클라이언트 인증서 / X-Api-Key 로 업무서비스가 확인되면 bizCode 확정 (gRPC 리스너와 공유)
+// (헤더/바디 BizSrvcCd 또는 서로의 값이 다르면 사칭으로 보고 401)
+func (h *DynamicGateway) AuthenticateCaller(r *http.Request, claimed string, bizCode *string) *httpx.Error {
+	bound := ""
+	if h.ClientCerts != nil {
+		if names := tlsx.PeerNames(r.TLS); names != nil {
+			biz, ok := h.ClientCerts.Lookup(names)
+			if !ok {
+				return httpx.Err(model.ErrCodeUnauthorized, errors.New("mtls: client certificate not mapped to BizSrvcCd"))
+			}
+			if claimed != "" && claimed != biz {
+				return httpx.Err(model.ErrCodeUnauthorized, errors.New("mtls: certificate bound to another BizSrvcCd"))
+			}
+			bound = biz
+		}
+	}
+
+	if h.APIKeys != nil {
+		plain := r.Header.Get("X-Api-Key")
+		if plain == "" && h.APIKeyRequired {
+			return httpx.Err(model.ErrCodeUnauthorized, errors.New("apikey: X-Api-Key required"))
+		}
+		if plain != "" {
+			key, err := h.APIKeys.Authenticate(r.Context(), plain)
+			switch {
+			case errors.Is(err, apikey.ErrMalformed), errors.Is(err, apikey.ErrUnknown),
+				errors.Is(err, apikey.ErrRevoked), errors.Is(err, apikey.ErrExpired):
+				return httpx.Err(model.ErrCodeUnauthorized, err)
+			case err != nil:
+				return httpx.Err(model.ErrCodeCatalog, err)
+			}
+			if (claimed != "" && claimed != key.BizServiceCode) || (bound != "" && bound != key.BizServiceCode) {
+				return httpx.Err(model.ErrCodeUnauthorized, errors.New("apikey: key bound to another BizSrvcCd"))
+			}
+			bound = key.BizServiceCode
+		}
+	}
+
+	if bound != "" {
+		*bizCode = bound
+	}
+	return nil
+}
+
+// claimedBizSrvcCd: 클라이언트가 선언한 BizSrvcCd (X-Fw-Header > 바디), 없으면 ""
+func claimedBizSrvcCd(fw map[string]string, body []byte) string {
+	if v := fw["BizSrvcCd"]; v != "" {
+		return v
+	}
+	var bodyMap map[string]interface{}
+	if err := json.Unmarshal(body, &bodyMap); err == nil {
+		if s, ok := bodyMap["BizSrvcCd"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// CallerIdentity: 검증된 호출자 식별자 (API 키 ID / 클라이언트 인증서 업무서비스), 인증 수단이 없거나 실패면 ""
+// 멱등키 범위처럼 클라이언트 선언값(BizSrvcCd)을 믿으면 안 되는 곳에서 사용
+func (h *DynamicGateway) CallerIdentity(r *http.Request) string {
+	var ids []string
+	if h.ClientCerts != nil {
+		if biz, ok := h.ClientCerts.Lookup(tlsx.PeerNames(r.TLS)); ok {
+			ids = append(ids, "cert:"+biz)
+		}
+	}
+	if h.APIKeys != nil {
+		if plain := r.Header.Get("X-Api-Key"); plain != "" {
+			if key, err := h.APIKeys.Authenticate(r.Context(), plain); err == nil {
+				ids = append(ids, "key:"+key.KeyID)
+			}
+		}
 	}
+	return strings.Join(ids, ",")
 }
 
 func (h *DynamicGateway) Post(w http.ResponseWriter, r *http.Request) {
@@ -85,70 +159,58 @@ func (h *DynamicGateway) Post(w http.ResponseWriter, r *http.Request) {
 	_, span := tracer.Start(r.Context(), "Gateway")
 	defer span.End()
 
-	// log 적재를 위한
-	configlog := config.AppConfig.Application.Log
+	// 요청 바디는 한 번만 읽어 재사용 (GET 은 빈 바디)
+	var reqBody []byte
+	if r.Method != http.MethodGet {
+		b, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeBadRequest, err))
+			return
+		}
+		reqBody = b
+	}
+
 	// ✅ FW Header 생성 (Host 기반)
 	// 1) 클라이언트가 보낸 X-Fw-Header 파싱
-	inFwRaw := r.Header.Get("X-Fw-Header")
-	inFw := header.Parse(inFwRaw)
+	inFw := header.Parse(r.Header.Get("X-Fw-Header"))
 
-	// 3) BizSrvcCd 결정: 헤더 > 바디 > 기본값(SMP)
+	// 3) BizSrvcCd 결정: 인증된 값(클라이언트 인증서 / API 키) > 헤더 > 바디 > 기본값(SMP)
 	bizCode := "SMP"
-	if v, ok := inFw["BizSrvcCd"]; ok && v != "" {
-		bizCode = v
-	} else {
-		// body에서 BizSrvcCd 추출
-		bodyBytes, _ := io.ReadAll(r.Body)
-		defer r.Body.Close()
-		var bodyMap map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &bodyMap); err == nil {
-			if v, ok := bodyMap["BizSrvcCd"]; ok {
-				if s, ok := v.(string); ok && s != "" {
-					bizCode = s
-				}
-			}
-		}
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 복원
+	claimed := claimedBizSrvcCd(inFw, reqBody)
+	if claimed != "" {
+		bizCode = claimed
 	}
+	authErr := h.AuthenticateCaller(r, claimed, &bizCode) // 실패는 감사 로그 시작 후 응답
 	merged := header.ApplyServerSideFields(inFw, bizCode, r.Host)
+	logx.SetTCID(r.Context(), merged["TCID"]) // 이 요청의 이후 로그는 게이트웨이 TCID 기준
+	logx.SetRoute(r.Context(), "gateway")
 
-	// ==== inbound request log ====
-	if ok, _ := h.Repo.ExistConfig(r.Context(), configlog.Inbound.Request); ok {
-		var bodyBytes []byte
-		if r.Method == http.MethodGet {
-			bodyBytes = []byte{}
-			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 빈 body로 복원
-		} else {
-			bodyBytes, _ = io.ReadAll(r.Body)
-			defer r.Body.Close()
-			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 복원
-		}
-		ev := gwLog{
-			Ts:           time.Now().Format("20060102150405000"),
-			Tcid:         merged["TCID"],
-			TcidSrno:     merged["TCIDSRNO"],
-			TcidCreMabd:  "00",
-			BizSrvcCd:    merged["BizSrvcCd"],
-			BizSrvcIp:    merged["BizSrvcIp"],
-			RasTyp:       "11", // 11 요청, 12 응답, 21 : 송신 22 수신
-			NmlYn:        "Y",
-			ApiPath:      r.URL.String(),
-			CmnSrvcCd:    "",
-			CmnBizSrvcCd: "",
-			ApiGroupCd:   config.AppConfig.Application.GroupCode,
-			ApiCd:        "00001",
-			Guid:         "",
-			InterId:      "",
-			MessageCd:    "",
-			HostName:     "",
-			Body:         truncate(bodyBytes, 4*1024), // 4KiB 제한
-			//Meta:         map[string]interface{}{"remote_addr": r.RemoteAddr},
+	// ==== 감사 로그: 요청 1건 컨텍스트 ====
+	trail := h.Audit.Begin(r, merged)
+	trail.Request(reqBody)
+
+	if authErr != nil {
+		h.fail(w, r, trail, merged, authErr)
+		return
+	}
+	// 업무서비스별 IP 허용/차단 (ip_filter.biz_srvc + SID_BIZ_SRVC_IP_ACL)
+	if ip := middleware.ClientIPFrom(r.Context()); !h.IPFilter.Allowed(r.Context(), bizCode, ip) {
+		h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeIPForbidden, errors.New("ipfilter: "+ip.String()+" not allowed for "+bizCode)))
+		return
+	}
+
+	// FwAuthorization 검증: DB 정책 체크 전에 차단 (존재 시만, required 면 필수)
+	if h.FwAuth != nil {
+		if tok := inFw["FwAuthorization"]; tok != "" {
+			if _, err := h.FwAuth.Verify(r.Context(), tok, bizCode); err != nil {
+				h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeUnauthorized, err))
+				return
+			}
+		} else if h.FwAuthRequired {
+			h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeUnauthorized, errors.New("fwauth: FwAuthorization required")))
+			return
 		}
-		buf, _ := json.Marshal(ev)
-		// 키: TCID 우선, 없으면 빈키
-		key := []byte(header.Parse(r.Header.Get("X-Fw-Header"))["TCID"])
-		// kafka 송신
-		h.Log.Publish(key, buf)
 	}
 
 	var in requestBody
@@ -156,89 +218,29 @@ func (h *DynamicGateway) Post(w http.ResponseWriter, r *http.Request) {
 		// GET 방식일 때는 /gateway/* 전체 경로에서 /gateway prefix를 제거하여 in.URL에 넣어줌
 		in.URL = strings.TrimPrefix(r.URL.Path, "/gateway")
 		in.Data = nil
-	} else {
-		bodyBytes, err := io.ReadAll(r.Body)
-		if err != nil {
-			returnlog(r, h, merged, []byte("failed to read body"))
-			httpx.WriteJSON(w, http.StatusBadRequest, httpx.NewError("failed to read body", err))
-			return
-		}
-		defer r.Body.Close()
-		if err := json.Unmarshal(bodyBytes, &in); err != nil {
-			returnlog(r, h, merged, []byte("invalid JSON"))
-			httpx.WriteJSON(w, http.StatusBadRequest, httpx.NewError("invalid JSON", err))
-			return
-		}
+	} else if err := json.Unmarshal(reqBody, &in); err != nil {
+		h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeBadRequest, err))
+		return
 	}
 
-	var requestData model.RequestData
 	// DB 체크용: 쿼리스트링 없는 path만 사용
 	urlOnly := in.URL
 	if idx := strings.Index(urlOnly, "?"); idx != -1 {
 		urlOnly = urlOnly[:idx]
 	}
-	requestData.RequestURL = urlOnly
-	requestData.BizServiceCode = bizCode
-
-	// DB 확인
-	var err error
-	requestData, err = h.Repo.FindRequestData(r.Context(), requestData)
-	if err != nil {
-		returnlog(r, h, merged, []byte("Request Api error"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("Request Api error", err))
-
-		return
-	}
-
-	// Roll check
-	existUseApiFlag, err := h.Repo.ExistUseAPIList(r.Context(), requestData)
-
-	if err != nil {
-		returnlog(r, h, merged, []byte("Using Api error"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("Using Api error", err))
-		return
-	}
-
-	if !existUseApiFlag {
-		returnlog(r, h, merged, []byte("Access not allowed by use API policy"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("Access not allowed by use API policy", err))
-		return
-	}
-
-	// API Group 체크
-	existApiGroupFlag, err := h.Repo.ExistAPIGroup(r.Context(), requestData)
-
-	if err != nil {
-		returnlog(r, h, merged, []byte("To use API Group error"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("To use API Group error", err))
-		return
-	}
-
-	if !existApiGroupFlag {
-		returnlog(r, h, merged, []byte("Api Group URL not allowed"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("Api Group URL not allowed", err))
-		return
+	requestData, ge := h.AuthorizeAPI(r.Context(), bizCode, urlOnly)
+	if requestData.ApiCode != "" {
+		trail.Resolve(requestData.ApiGroupCode, requestData.ApiCode)
+		logx.SetRoute(r.Context(), "gateway/"+requestData.ApiGroupCode+"/"+requestData.ApiCode)
 	}
-
-	// API 체크
-	existApiFlag, err := h.Repo.ExistAPI(r.Context(), requestData)
-
-	if err != nil {
-		returnlog(r, h, merged, []byte("To use API error"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("To use API error", err))
-		return
-	}
-
-	if !existApiFlag {
-		returnlog(r, h, merged, []byte("Api URL not allowed"))
-		httpx.WriteJSON(w, http.StatusNotFound, httpx.NewError("Api URL not allowed", err))
+	if ge != nil {
+		h.fail(w, r, trail, merged, ge)
 		return
 	}
 
 	// 업스트림 바디 및 URL 준비: 메서드별 처리
 	method := r.Method
-	var outBody io.Reader
-	var hasBody bool
+	var outBody []byte
 	ctx, cancel := context.WithTimeout(r.Context(), h.Client.Timeout)
 	defer cancel()
 
@@ -251,111 +253,55 @@ func (h *DynamicGateway) Post(w http.ResponseWriter, r *http.Request) {
 	}
 	// host가 빈값 또는 null이면 에러 반환
 	if host == "" {
-		returnlog(r, h, merged, []byte("Host not found for API data"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("Host not found for API data", nil))
+		h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeHostNotFound, nil))
 		return
 	}
 
-	var upstreamURL string
-
-	if method == http.MethodGet {
-		// 업스트림 송신 시에는 in.URL 전체(쿼리 포함) 그대로 사용
-		upstreamURL = host + in.URL
-		outBody = nil
-		hasBody = false
-	} else {
-		upstreamURL = host + in.URL
-		if len(in.Data) > 0 && string(in.Data) != "null" {
-			outBody = bytes.NewReader(in.Data)
-			hasBody = true
-		}
+	// 업스트림 송신 시에는 in.URL 전체(쿼리 포함) 그대로 사용
+	upstreamURL := host + in.URL
+	if method != http.MethodGet && len(in.Data) > 0 && string(in.Data) != "null" {
+		outBody = in.Data
 	}
 
-	log.Printf("host : %s, URL : %s, apiGroupCode : %s", host, upstreamURL, requestData.ApiGroupCode)
+	logx.SetUpstream(r.Context(), host)
+	lg := logx.Or(h.Logger)
+	lg.DebugContext(r.Context(), "upstream request", "host", host, "url", trail.Mask().String(upstreamURL), "api_group_cd", requestData.ApiGroupCode)
 
 	// 2) 업스트림 요청 생성 (메서드 그대로 사용)
-	reqUp, err := http.NewRequestWithContext(ctx, method, upstreamURL, outBody)
+	var bodyReader io.Reader
+	if outBody != nil {
+		bodyReader = bytes.NewReader(outBody)
+	}
+	// API 그룹별 업스트림 TLS (upstream_tls.groups, 공유 Transport)
+	upCtx := httpadapter.WithTLSProfile(ctx, httpadapter.GroupProfile(requestData.ApiGroupCode))
+	reqUp, err := http.NewRequestWithContext(upCtx, method, upstreamURL, bodyReader)
 	if err != nil {
-		returnlog(r, h, merged, []byte("build outbound request failed"))
-		httpx.WriteJSON(w, http.StatusInternalServerError, httpx.NewError("build outbound request failed", err))
+		h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeInternal, err))
 		return
 	}
 
-	// ✅ FW Header 생성 (Host 기반)
-	// 1) 클라이언트가 보낸 X-Fw-Header 파싱
-	//inFwRaw := r.Header.Get("X-Fw-Header")
-	//inFw := header.Parse(inFwRaw)
-	if ua := r.Header.Get("X-Fw-Header"); ua != "" {
-		reqUp.Header.Set("X-Fw-Header", ua)
-	}
-
 	// 4) 다시 문자열로 직렬화하여 업스트림에 전달
-	outFw := header.Serialize(merged)
-	r.Header.Set("X-Fw-Header", outFw)
+	r.Header.Set("X-Fw-Header", header.Serialize(merged))
 	// 헤더 복사 (필요시 hop-by-hop 필터링 추가 가능)
 	copySecureHeaders(reqUp.Header, r.Header)
-	if hasBody && reqUp.Header.Get("Content-Type") == "" {
+	// JWT 검증 후 전달할 클레임 헤더 (forward_claims)
+	for k, vv := range middleware.ForwardedClaimHeaders(r.Context()) {
+		reqUp.Header[k] = vv
+	}
+	if outBody != nil && reqUp.Header.Get("Content-Type") == "" {
 		reqUp.Header.Set("Content-Type", "application/json")
 	}
 
-	// outbound log
-	if ok, _ := h.Repo.ExistConfig(r.Context(), configlog.Outbound.Request); ok {
-
-		var bodyBytes []byte
-		if r.Method == http.MethodGet {
-			bodyBytes = []byte{}
-			reqUp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 빈 body로 복원
-		} else {
-			bodyBytes, _ = io.ReadAll(reqUp.Body)
-			defer reqUp.Body.Close()
-			reqUp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 복원
-		}
-
-		if err != nil {
-			log.Printf("failed to read request body: %v", err)
-			// 필요하다면 에러 처리
-		}
-		defer reqUp.Body.Close()
-
-		// body를 다시 사용할 수 있게 복원
-		reqUp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		headerJson, _ := json.Marshal(reqUp.Header)
-		log.Printf("target request log : body=%s, header=%s", string(bodyBytes), string(headerJson))
-
-		ev := gwLog{
-			Ts:           strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1),
-			Tcid:         header.Parse(reqUp.Header.Get("X-Fw-Header"))["TCID"],
-			TcidSrno:     header.Parse(reqUp.Header.Get("X-Fw-Header"))["TCIDSRNO"],
-			TcidCreMabd:  "00",
-			BizSrvcCd:    header.Parse(reqUp.Header.Get("X-Fw-Header"))["BizSrvcCd"],
-			BizSrvcIp:    header.Parse(reqUp.Header.Get("X-Fw-Header"))["BizSrvcIp"],
-			RasTyp:       "21", // 11 요청, 12 응답, 21 : 송신 22 수신
-			NmlYn:        "Y",
-			ApiPath:      reqUp.URL.String(),
-			CmnSrvcCd:    "",
-			CmnBizSrvcCd: "",
-			ApiGroupCd:   config.AppConfig.Application.GroupCode,
-			ApiCd:        "00001",
-			Guid:         "",
-			InterId:      "",
-			MessageCd:    "",
-			HostName:     "",
-			Body:         truncate(bodyBytes, 4*1024), // 4KiB 제한
-			//Meta:         map[string]interface{}{"remote_addr": r.RemoteAddr},
-		}
-		buf, _ := json.Marshal(ev)
-		// 키: TCID 우선, 없으면 빈키
-		key := []byte(header.Parse(reqUp.Header.Get("X-Fw-Header"))["TCID"])
-		// kafka 송신
-		h.Log.Publish(key, buf)
-
-	}
+	// ==== 감사 로그: 업스트림 송신 ====
+	trail.Send(reqUp.URL.String(), outBody)
 
 	resp, err := h.Client.Do(reqUp)
 	if err != nil {
-		merged["TCIDSRNO"] = header.BumpTCIDSRNO(merged["TCIDSRNO"])
-		returnlog(r, h, merged, []byte("upstream request failed"))
-		httpx.WriteJSON(w, http.StatusBadGateway, httpx.NewError("upstream request failed", err))
+		code := model.ErrCodeUpstreamFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			code = model.ErrCodeUpstreamTimeout
+		}
+		h.fail(w, r, trail, merged, httpx.Err(code, err))
 		return
 	}
 	defer resp.Body.Close()
@@ -363,109 +309,91 @@ func (h *DynamicGateway) Post(w http.ResponseWriter, r *http.Request) {
 	// 업스트림 응답 body 읽기 및 로그
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("failed to read upstream response body: %v", err)
-		httpx.WriteJSON(w, http.StatusBadGateway, httpx.NewError("upstream response read failed", err))
+		h.fail(w, r, trail, merged, httpx.Err(model.ErrCodeUpstreamFailed, err))
 		return
 	}
-	log.Printf("upstream response body: %s", string(bodyBytes))
+	if lg.Enabled(r.Context(), slog.LevelDebug) {
+		lg.DebugContext(r.Context(), "upstream response", "status", resp.StatusCode, "body", string(trail.Mask().Body(bodyBytes)))
+	}
+
+	// ==== 감사 로그: 업스트림 수신 ====
+	trail.Receive(resp.StatusCode, bodyBytes)
 
 	// 헤더 복사 및 상태코드 설정
-	fwHeader := resp.Header.Get("X-Fw-Header")
-	bumped := header.BumpTCIDSRNO(fwHeader)
+	bumped := header.BumpTCIDSRNO(resp.Header.Get("X-Fw-Header"))
 	copyHeaders(w.Header(), resp.Header)
 	w.Header().Set("X-Fw-Header", bumped)
+	// GET 방식이면 업스트림 Content-Type 그대로, POST 등은 json
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+	}
 	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(bodyBytes)
 
-	// GET 방식이면 bodyBytes 그대로 반환, POST 등은 Content-Type을 json으로 설정
-	if r.Method == http.MethodGet {
-		_, _ = w.Write(bodyBytes)
-	} else {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write(bodyBytes)
-	}
-
-	// outbound log (원하는 위치에 bodyBytes 사용)
-	if ok, _ := h.Repo.ExistConfig(r.Context(), configlog.Outbound.Response); ok {
-		headerJson, _ := json.Marshal(resp.Header)
-		log.Printf("target response log : body=%s, header=%s", string(bodyBytes), string(headerJson))
-		ev := gwLog{
-			Ts:           strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1),
-			Tcid:         header.Parse(w.Header().Get("X-Fw-Header"))["TCID"],
-			TcidSrno:     header.Parse(w.Header().Get("X-Fw-Header"))["TCIDSRNO"],
-			TcidCreMabd:  "00",
-			BizSrvcCd:    header.Parse(w.Header().Get("X-Fw-Header"))["BizSrvcCd"],
-			BizSrvcIp:    header.Parse(w.Header().Get("X-Fw-Header"))["BizSrvcIp"],
-			RasTyp:       "12",
-			NmlYn:        "Y",
-			ApiPath:      r.URL.String(),
-			CmnSrvcCd:    "",
-			CmnBizSrvcCd: "",
-			ApiGroupCd:   config.AppConfig.Application.GroupCode,
-			ApiCd:        "00001",
-			Guid:         "",
-			InterId:      "",
-			MessageCd:    "",
-			HostName:     "",
-			Body:         truncate(bodyBytes, 4*1024),
+	// ==== 감사 로그: 인바운드 응답 ====
+	trail.Respond(resp.StatusCode, "", bodyBytes)
+}
+
+// AuthorizeAPI: API 카탈로그 조회 → 사용 권한(ExistUseAPIList) → API 그룹 / API 사용 여부 → 점검 시간대
+// 카탈로그에서 찾았으면 거부되더라도 RequestData 를 돌려줌 (감사 로그 API 코드 기록용)
+func (h *DynamicGateway) AuthorizeAPI(ctx context.Context, bizCode, apiPath string) (model.RequestData, *httpx.Error) {
+	requestData, err := h.Repo.FindRequestData(ctx, model.RequestData{RequestURL: apiPath, BizServiceCode: bizCode})
+	if err != nil {
+		code := model.ErrCodeCatalog
+		if errors.Is(err, store.ErrNotFound) {
+			code = model.ErrCodeApiNotFound
 		}
-		buf, _ := json.Marshal(ev)
-		key := []byte(header.Parse(w.Header().Get("X-Fw-Header"))["TCID"])
-		h.Log.Publish(key, buf)
+		return model.RequestData{}, httpx.Err(code, err)
 	}
 
-	copyHeaders(w.Header(), resp.Header)
-	w.Header().Set("X-Fw-Header", bumped)
-	w.WriteHeader(resp.StatusCode)
-
-	var buf bytes.Buffer
-	tee := io.TeeReader(resp.Body, &buf)
+	// Roll check
+	existUseApiFlag, err := h.Repo.ExistUseAPIList(ctx, requestData)
+	if err != nil {
+		return requestData, httpx.Err(model.ErrCodeCatalog, err)
+	}
+	if !existUseApiFlag {
+		return requestData, httpx.Err(model.ErrCodeApiForbidden, nil)
+	}
 
-	// 먼저 w로 카피
-	_, _ = io.Copy(w, tee)
+	// API Group 체크
+	existApiGroupFlag, err := h.Repo.ExistAPIGroup(ctx, requestData)
+	if err != nil {
+		return requestData, catalogError(err)
+	}
+	if !existApiGroupFlag {
+		return requestData, httpx.Err(model.ErrCodeGroupForbidden, nil)
+	}
 
-	// outbound log
-	if ok, _ := h.Repo.ExistConfig(r.Context(), configlog.Inbound.Response); ok {
-		bodyBytes, err := io.ReadAll(bytes.NewReader(buf.Bytes()))
-		if err != nil {
-			log.Printf("failed to read request body: %v", err)
-			// 필요하다면 에러 처리
-		}
-		defer resp.Body.Close()
-
-		// body를 다시 사용할 수 있게 복원
-		resp.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-		headerJson, _ := json.Marshal(resp.Header)
-		log.Printf("target request log : body=%s, header=%s", string(bodyBytes), string(headerJson))
-
-		ev := gwLog{
-			Ts:           strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1),
-			Tcid:         header.Parse(w.Header().Get("X-Fw-Header"))["TCID"],
-			TcidSrno:     header.Parse(w.Header().Get("X-Fw-Header"))["TCIDSRNO"],
-			TcidCreMabd:  "00",
-			BizSrvcCd:    header.Parse(w.Header().Get("X-Fw-Header"))["BizSrvcCd"],
-			BizSrvcIp:    header.Parse(w.Header().Get("X-Fw-Header"))["BizSrvcIp"],
-			RasTyp:       "12", // 11 요청, 12 응답, 21 : 송신 22 수신
-			NmlYn:        "Y",
-			ApiPath:      r.URL.String(),
-			CmnSrvcCd:    "",
-			CmnBizSrvcCd: "",
-			ApiGroupCd:   config.AppConfig.Application.GroupCode,
-			ApiCd:        "00001",
-			Guid:         "",
-			InterId:      "",
-			MessageCd:    "",
-			HostName:     "",
-			Body:         truncate(bodyBytes, 4*1024), // 4KiB 제한
-			//Meta:         map[string]interface{}{"remote_addr": r.RemoteAddr},
-		}
-		buf, _ := json.Marshal(ev)
-		// 키: TCID 우선, 없으면 빈키
-		key := []byte(header.Parse(w.Header().Get("X-Fw-Header"))["TCID"])
-		// kafka 송신
-		h.Log.Publish(key, buf)
+	// API 체크
+	existApiFlag, err := h.Repo.ExistAPI(ctx, requestData)
+	if err != nil {
+		return requestData, catalogError(err)
+	}
+	if !existApiFlag {
+		return requestData, httpx.Err(model.ErrCodeApiNotFound, nil)
+	}
 
+	// 점검 시간대 체크 (API / 그룹 단위, 반복 스케줄 포함)
+	if ce := h.Maintenance.Check(ctx, time.Now(), requestData.ApiGroupCode, requestData.ApiCode); ce != nil {
+		return requestData, httpx.AsError(ce)
 	}
+	return requestData, nil
+}
 
+// fail: 응답 로그(12, NmlYn=N) 적재 후 카탈로그 코드로 에러 응답
+// 감사 data 에는 코드 + 응답 문구만 (Cause 는 SQL / 내부 호스트 등이 담길 수 있어 WriteError 의 서버 로그에만)
+func (h *DynamicGateway) fail(w http.ResponseWriter, r *http.Request, trail *audit.Trail, merged map[string]string, ge *httpx.Error) {
+	trail.Respond(ge.StatusCode(), ge.Code, []byte(ge.Code+" "+ge.Text()))
+	httpx.WriteError(w, r, ge.WithTcid(merged["TCID"]))
+}
+
+// catalogError: 거래통제(ControlError)는 503 + 통제코드, 그 외 카탈로그 조회 실패
+func catalogError(err error) *httpx.Error {
+	var ce *model.ControlError
+	if errors.As(err, &ce) {
+		return httpx.AsError(ce)
+	}
+	return httpx.Err(model.ErrCodeCatalog, err)
 }
 
 func copyHeaders(dst, src http.Header) {
@@ -494,53 +422,3 @@ func copySecureHeaders(dst, src http.Header) {
 		}
 	}
 }
-
-func returnlog(r *http.Request, h *DynamicGateway, merged map[string]string, data []byte) {
-
-	configlog := config.AppConfig.Application.Log
-
-	merged["TCIDSRNO"] = header.BumpTCIDSRNO(merged["TCIDSRNO"])
-
-	// ==== inbound response log ====
-	if ok, _ := h.Repo.ExistConfig(r.Context(), configlog.Inbound.Response); ok {
-		var bodyBytes []byte
-		if r.Method == http.MethodGet {
-			bodyBytes = []byte{}
-			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 빈 body로 복원
-		} else {
-			bodyBytes, _ = io.ReadAll(r.Body)
-			defer r.Body.Close()
-			r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 복원
-		}
-
-		defer r.Body.Close()
-		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // 복원
-		ev := gwLog{
-			Ts:           strings.Replace(time.Now().Format("20060102150405.000"), ".", "", 1),
-			Tcid:         merged["TCID"],
-			TcidSrno:     merged["TCIDSRNO"],
-			TcidCreMabd:  "00",
-			BizSrvcCd:    merged["BizSrvcCd"],
-			BizSrvcIp:    merged["BizSrvcIp"],
-			RasTyp:       "12", // 11 요청, 12 응답, 21 : 송신 22 수신
-			NmlYn:        "N",
-			ApiPath:      r.URL.String(),
-			CmnSrvcCd:    "",
-			CmnBizSrvcCd: "",
-			ApiGroupCd:   config.AppConfig.Application.GroupCode,
-			ApiCd:        "00001",
-			Guid:         "",
-			InterId:      "",
-			MessageCd:    "",
-			HostName:     "",
-			Body:         truncate(data, 4*1024), // 4KiB 제한
-			//Meta:         map[string]interface{}{"remote_addr": r.RemoteAddr},
-		}
-		buf, _ := json.Marshal(ev)
-		// 키: TCID 우선, 없으면 빈키
-		key := []byte(header.Parse(r.Header.Get("X-Fw-Header"))["TCID"])
-		// kafka 송신
-		h.Log.Publish(key, buf)
-	}
-
-}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"service-gateway/internal/apikey"
+	"service-gateway/internal/header"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+	"strings"
+	"testing"
+	"time"
+)
+
+// keySource: apikey.Source 대역
+type keySource map[string]model.ApiKey
+
+func (s keySource) FindApiKey(_ context.Context, keyID string) (model.ApiKey, error) {
+	if k, ok := s[keyID]; ok {
+		return k, nil
+	}
+	return model.ApiKey{}, store.ErrNotFound
+}
+
+func newKey(t *testing.T, src keySource, biz string) string {
+	t.Helper()
+	plain, id, hash, err := apikey.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src[id] = model.ApiKey{KeyID: id, BizServiceCode: biz, Hash: hash, UseYn: "Y"}
+	return plain
+}
+
+func TestClaimedBizSrvcCd(t *testing.T) {
+	cases := []struct {
+		name string
+		fw   string
+		body string
+		want string
+	}{
+		{"header", "TCID=T1;BizSrvcCd=ABC", `{"BizSrvcCd":"XYZ"}`, "ABC"},
+		{"body", "TCID=T1", `{"BizSrvcCd":"XYZ"}`, "XYZ"},
+		{"body not string", "", `{"BizSrvcCd":7}`, ""},
+		{"body not json", "", `BizSrvcCd=XYZ`, ""},
+		{"none", "", "", ""},
+	}
+	for _, c := range cases {
+		if got := claimedBizSrvcCd(header.Parse(c.fw), []byte(c.body)); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+// API 키에 묶인 업무서비스와 다른 BizSrvcCd 를 헤더 / 바디로 선언하면 사칭으로 401
+func TestAuthenticateCallerAPIKey(t *testing.T) {
+	src := keySource{}
+	key := newKey(t, src, "SMP")
+	h := &DynamicGateway{APIKeys: apikey.NewResolver(src, time.Minute)}
+
+	cases := []struct {
+		name     string
+		key      string
+		fw       string
+		body     string
+		wantCode string // "" 면 통과
+		wantBiz  string
+	}{
+		{"no claim → key binding", key, "", "", "", "SMP"},
+		{"header matches", key, "BizSrvcCd=SMP", "", "", "SMP"},
+		{"body matches", key, "", `{"BizSrvcCd":"SMP"}`, "", "SMP"},
+		{"header differs", key, "BizSrvcCd=ABC", "", model.ErrCodeUnauthorized, ""},
+		{"body differs", key, "", `{"BizSrvcCd":"ABC"}`, model.ErrCodeUnauthorized, ""},
+		{"unknown key", "gwk_" + strings.Repeat("a", 12) + "_" + strings.Repeat("b", 32), "", "", model.ErrCodeUnauthorized, ""},
+		{"malformed key", "secret", "", "", model.ErrCodeUnauthorized, ""},
+		{"no key, optional", "", "BizSrvcCd=ABC", "", "", "ABC"},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest(http.MethodPost, "/gateway", nil)
+		if c.key != "" {
+			r.Header.Set("X-Api-Key", c.key)
+		}
+		claimed := claimedBizSrvcCd(header.Parse(c.fw), []byte(c.body))
+		biz := claimed
+		ge := h.AuthenticateCaller(r, claimed, &biz)
+		switch {
+		case c.wantCode != "":
+			if ge == nil || ge.Code != c.wantCode {
+				t.Errorf("%s: err = %v, want code %s", c.name, ge, c.wantCode)
+			}
+		case ge != nil:
+			t.Errorf("%s: unexpected %v", c.name, ge)
+		case biz != c.wantBiz:
+			t.Errorf("%s: bizCode = %q, want %q", c.name, biz, c.wantBiz)
+		}
+	}
+
+	h.APIKeyRequired = true
+	r := httptest.NewRequest(http.MethodPost, "/gateway", nil)
+	biz := ""
+	if ge := h.AuthenticateCaller(r, "", &biz); ge == nil || ge.Code != model.ErrCodeUnauthorized {
+		t.Errorf("required, missing key: %v", ge)
+	}
+}
+
+func TestAuthenticateCallerSourceError(t *testing.T) {
+	h := &DynamicGateway{APIKeys: apikey.NewResolver(failingSource{}, time.Minute)}
+	r := httptest.NewRequest(http.MethodPost, "/gateway", nil)
+	r.Header.Set("X-Api-Key", "gwk_"+strings.Repeat("a", 12)+"_"+strings.Repeat("b", 32))
+	biz := ""
+	if ge := h.AuthenticateCaller(r, "", &biz); ge == nil || ge.Code != model.ErrCodeCatalog {
+		t.Errorf("db error: %v, want code %s", ge, model.ErrCodeCatalog)
+	}
+}
+
+type failingSource struct{}
+
+func (failingSource) FindApiKey(context.Context, string) (model.ApiKey, error) {
+	return model.ApiKey{}, errors.New("db down")
+}
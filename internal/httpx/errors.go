@@ -0,0 +1,258 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"service-gateway/internal/header"
+	"service-gateway/internal/model"
+	"strconv"
+	"time"
+)
+
+/*
+게이트웨이 에러 응답
+
+WHY:
+- 핸들러마다 다른 상태코드/문구로 응답하고 Go 에러 문자열(SQL 에러 등)이 그대로 노출되던 문제 정리.
+- 모든 에러는 model.ErrorCatalog 의 코드로 응답 → 클라이언트는 문구가 아니라 코드로 분기.
+- 원인(Cause)은 서버 로그에만 남기고 응답에는 코드/문구/TCID 만 포함.
+
+응답 형식 (Accept 로 협상, 미지정 시 errors.format):
+- json   : {"success":false,"message":"...","data":{"code":"15","status":403,"tcId":"...","timestamp":"..."}}
+- problem: RFC 7807 application/problem+json
+- xml    : <error><success>false</success><message>...</message><code>15</code>...</error>
+- plain  : "15 사용 권한이 없는 API (tcId=...)"
+문구는 Accept-Language, 시각은 x-timezone 기준 (negotiate.go)
+*/
+
+const (
+	FormatJSON    = "json"
+	FormatProblem = "problem"
+
+	problemContentType = "application/problem+json"
+)
+
+var (
+	errorFormat = FormatJSON
+	errorLang   = "ko"
+	errorLoc    = time.FixedZone("KST", 9*60*60)
+)
+
+// ErrorConfig: gateway.yaml errors 블록
+type ErrorConfig struct {
+	Format    string // json | problem (Accept 미지정 시)
+	Lang      string // Accept-Language 미지정/미지원 시 언어
+	BundleDir string // 문구 번들 디렉터리 (비어 있으면 내장 문구만)
+	TimeZone  string // x-timezone 미지정 시 (기본 Asia/Seoul)
+}
+
+// ConfigureErrors: 기동 시 1회
+func ConfigureErrors(cfg ErrorConfig) error {
+	if cfg.Format == FormatProblem {
+		errorFormat = FormatProblem
+	}
+	if cfg.Lang != "" {
+		errorLang = baseLang(cfg.Lang)
+	}
+	tz := cfg.TimeZone
+	if tz == "" {
+		tz = "Asia/Seoul"
+	}
+	var errs []error
+	if loc, err := time.LoadLocation(tz); err != nil {
+		errs = append(errs, fmt.Errorf("errors.timezone: %w", err))
+	} else {
+		errorLoc = loc
+	}
+	if cfg.BundleDir != "" {
+		if err := LoadMessageBundles(cfg.BundleDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Error: 카탈로그 코드 기반 게이트웨이 에러
+type Error struct {
+	Code       string
+	Status     int           // 0 이면 카탈로그 상태코드
+	Message    string        // 비어 있으면 카탈로그 문구
+	RetryAfter time.Duration // > 0 이면 Retry-After 헤더
+	Tcid       string        // 비어 있으면 요청 X-Fw-Header 의 TCID
+	Cause      error         // 서버 로그 전용 (응답 미노출)
+}
+
+// Err: 코드 + 원인으로 에러 생성
+func Err(code string, cause error) *Error {
+	return &Error{Code: code, Cause: cause}
+}
+
+func (e *Error) Error() string {
+	if e.Cause != nil {
+		return e.Code + ": " + e.Cause.Error()
+	}
+	return e.Code + ": " + e.message(errorLang)
+}
+
+func (e *Error) Unwrap() error { return e.Cause }
+
+// WithMessage: 카탈로그 문구 대신 사용할 안내 문구 (점검 안내 등)
+func (e *Error) WithMessage(msg string) *Error {
+	e.Message = msg
+	return e
+}
+
+// WithTcid: 응답에 실을 TCID 지정 (요청 헤더와 다른 TCID 로 로그를 남기는 경우)
+func (e *Error) WithTcid(tcid string) *Error {
+	e.Tcid = tcid
+	return e
+}
+
+// Text: 기본 언어(errors.lang) 응답 문구 (HTTP 외 응답용)
+func (e *Error) Text() string { return e.message(errorLang) }
+
+// StatusCode: 응답 HTTP 상태코드 (감사 로그 등)
+func (e *Error) StatusCode() int { return e.status() }
+
+func (e *Error) status() int {
+	if e.Status != 0 {
+		return e.Status
+	}
+	if def, ok := model.ErrorCatalog[e.Code]; ok {
+		return def.Status
+	}
+	return http.StatusInternalServerError
+}
+
+func (e *Error) message(lang string) string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return catalogMessage(e.Code, lang)
+}
+
+// AsError: 임의 에러 → *Error (ControlError 는 503 + 통제코드, 그 외는 내부 오류)
+func AsError(err error) *Error {
+	var ge *Error
+	if errors.As(err, &ge) {
+		return ge
+	}
+	var ce *model.ControlError
+	if errors.As(err, &ce) {
+		msg := ce.Message
+		if msg == model.ErrorCodeMap[ce.Code] { // 기본 문구면 언어별 번들 문구 사용
+			msg = ""
+		}
+		return &Error{Code: ce.Code, Status: http.StatusServiceUnavailable, Message: msg, RetryAfter: ce.RetryAfter, Cause: err}
+	}
+	return Err(model.ErrCodeInternal, err)
+}
+
+type errorData struct {
+	Code      string `json:"code" xml:"code"`
+	Status    int    `json:"status" xml:"status"`
+	Tcid      string `json:"tcId,omitempty" xml:"tcId,omitempty"`
+	Timestamp string `json:"timestamp" xml:"timestamp"`
+	RetryAt   string `json:"retryAt,omitempty" xml:"retryAt,omitempty"`
+}
+
+// RFC 7807 + 확장 멤버(code, tcId)
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	Tcid      string `json:"tcId,omitempty"`
+	Timestamp string `json:"timestamp"`
+	RetryAt   string `json:"retryAt,omitempty"`
+}
+
+type xmlError struct {
+	XMLName xml.Name `xml:"error"`
+	Success bool     `xml:"success"`
+	Message string   `xml:"message"`
+	errorData
+}
+
+// WriteError: 모든 에러 응답의 단일 출구
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	ge := AsError(err)
+	status := ge.status()
+
+	tcid := ge.Tcid
+	if tcid == "" && r != nil {
+		tcid = header.Parse(r.Header.Get("X-Fw-Header"))["TCID"]
+	}
+	if ge.Cause != nil || status >= 500 {
+		ctx, path := context.Background(), ""
+		if r != nil {
+			ctx, path = r.Context(), r.URL.Path
+		}
+		level := slog.LevelWarn
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		// tcid / route / trace_id 는 요청 컨텍스트에서 (logx)
+		slog.Log(ctx, level, "gateway error", "code", ge.Code, "status", status, "path", path, "cause", ge.Cause)
+	}
+
+	lang := negotiateLang(r)
+	now := time.Now().In(clientLocation(r))
+	data := errorData{Code: ge.Code, Status: status, Tcid: tcid, Timestamp: now.Format(time.RFC3339)}
+	if ge.RetryAfter > 0 {
+		secs := int(math.Ceil(ge.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+		data.RetryAt = now.Add(time.Duration(secs) * time.Second).Format(time.RFC3339)
+	}
+	// 협상 결과가 요청 헤더에 따라 달라지므로 캐시 분리
+	w.Header().Add("Vary", "Accept, Accept-Language, X-Timezone")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Language", lang)
+
+	switch negotiateFormat(r) {
+	case FormatProblem:
+		p := problem{
+			Type:      "urn:service-gateway:error:" + ge.Code,
+			Title:     catalogMessage(ge.Code, lang),
+			Status:    status,
+			Detail:    ge.Message,
+			Code:      ge.Code,
+			Tcid:      tcid,
+			Timestamp: data.Timestamp,
+			RetryAt:   data.RetryAt,
+		}
+		if r != nil {
+			p.Instance = r.URL.Path
+		}
+		w.Header().Set("Content-Type", problemContentType)
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(p)
+	case formatXML:
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(xml.Header))
+		_ = xml.NewEncoder(w).Encode(xmlError{Message: ge.message(lang), errorData: data})
+	case formatPlain:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(status)
+		line := ge.Code + " " + ge.message(lang)
+		if tcid != "" {
+			line += " (tcId=" + tcid + ")"
+		}
+		_, _ = w.Write([]byte(line + "\n"))
+	default:
+		WriteJSON(w, status, Response{
+			Success: false,
+			Message: ge.message(lang),
+			Data:    data,
+		})
+	}
+}
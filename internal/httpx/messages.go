@@ -0,0 +1,88 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"service-gateway/internal/model"
+	"sort"
+	"strings"
+)
+
+/*
+에러 문구 번들
+
+WHY:
+- 문구 수정/언어 추가를 재배포 없이 파일로 관리 (configs/messages/<lang>.json, {"코드": "문구"}).
+- 번들에 없는 코드/언어는 model.ErrorCatalog 내장 문구(ko/en)로 대체 → 파일 누락으로 응답이 비지 않음.
+*/
+
+// bundles: 언어(소문자 기본 태그) → 코드 → 문구. 기동 시 1회 적재 후 읽기 전용
+var bundles = map[string]map[string]string{}
+
+// LoadMessageBundles: dir 의 *.json 을 언어별 번들로 적재 (파일명 = 언어 태그, ko.json / en.json)
+func LoadMessageBundles(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	loaded := map[string]map[string]string{}
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		msgs := map[string]string{}
+		if err := json.Unmarshal(raw, &msgs); err != nil {
+			return fmt.Errorf("message bundle %s: %w", f, err)
+		}
+		lang := baseLang(strings.TrimSuffix(filepath.Base(f), ".json"))
+		loaded[lang] = msgs
+	}
+	bundles = loaded
+	slog.Info("message bundles loaded", "component", "httpx", "langs", strings.Join(bundleLangs(), ","))
+	return nil
+}
+
+// bundleLangs: 응답 가능한 언어 (번들 + 내장 ko/en)
+func bundleLangs() []string {
+	set := map[string]bool{"ko": true, "en": true}
+	for l := range bundles {
+		set[l] = true
+	}
+	out := make([]string, 0, len(set))
+	for l := range set {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// catalogMessage: 번들 → 내장 카탈로그 순으로 문구 조회
+func catalogMessage(code, lang string) string {
+	if msg := bundles[lang][code]; msg != "" {
+		return msg
+	}
+	def, ok := model.ErrorCatalog[code]
+	if !ok {
+		if msg := bundles[lang][model.ErrCodeInternal]; msg != "" {
+			return msg
+		}
+		def = model.ErrorCatalog[model.ErrCodeInternal]
+	}
+	if lang == "ko" {
+		return def.Ko
+	}
+	return def.En
+}
+
+// baseLang: "en-US" → "en"
+func baseLang(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
@@ -0,0 +1,120 @@
+package httpx
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+에러 응답 콘텐츠 협상
+
+- Accept          : application/json(기본) | application/problem+json | application/xml, text/xml | text/plain
+- Accept-Language : 번들/내장 언어 중 q 값이 가장 높은 언어, 없으면 errors.lang
+- x-timezone      : IANA TZ (header.json 의 요청 헤더). 응답 timestamp / retryAt 표기용, 잘못된 값이면 errors.timezone
+*/
+
+const (
+	formatXML   = "xml"
+	formatPlain = "plain"
+)
+
+type qItem struct {
+	value string
+	q     float64
+}
+
+// parseQList: "ko-KR, en-US;q=0.8" → q 내림차순 (동일 q 는 입력 순서 유지), q=0 제외
+func parseQList(h string) []qItem {
+	var out []qItem
+	for _, part := range strings.Split(h, ",") {
+		fields := strings.Split(part, ";")
+		v := strings.ToLower(strings.TrimSpace(fields[0]))
+		if v == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range fields[1:] {
+			k, val, ok := strings.Cut(strings.TrimSpace(p), "=")
+			if ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		out = append(out, qItem{value: v, q: q})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].q > out[j].q })
+	return out
+}
+
+// negotiateFormat: Accept 기준 응답 형식 (미지정/*/* 이면 errors.format)
+func negotiateFormat(r *http.Request) string {
+	if r == nil {
+		return errorFormat
+	}
+	for _, it := range parseQList(r.Header.Get("Accept")) {
+		switch it.value {
+		case problemContentType:
+			return FormatProblem
+		case "application/json":
+			return FormatJSON
+		case "application/xml", "text/xml":
+			return formatXML
+		case "text/plain":
+			return formatPlain
+		case "*/*", "application/*":
+			return errorFormat
+		}
+	}
+	return errorFormat
+}
+
+// negotiateLang: Accept-Language 기준 문구 언어
+func negotiateLang(r *http.Request) string {
+	if r == nil {
+		return errorLang
+	}
+	langs := bundleLangs()
+	for _, it := range parseQList(r.Header.Get("Accept-Language")) {
+		if it.value == "*" {
+			return errorLang
+		}
+		want := baseLang(it.value)
+		for _, l := range langs {
+			if l == want {
+				return l
+			}
+		}
+	}
+	return errorLang
+}
+
+// 유효한 TZ 만 캐시 (LoadLocation 은 매번 zoneinfo 를 파싱)
+var tzCache sync.Map
+
+// clientLocation: x-timezone → *time.Location (없거나 잘못된 값이면 기본 TZ)
+func clientLocation(r *http.Request) *time.Location {
+	if r == nil {
+		return errorLoc
+	}
+	name := strings.TrimSpace(r.Header.Get("X-Timezone"))
+	if name == "" || len(name) > 64 {
+		return errorLoc
+	}
+	if loc, ok := tzCache.Load(name); ok {
+		return loc.(*time.Location)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return errorLoc
+	}
+	tzCache.Store(name, loc)
+	return loc
+}
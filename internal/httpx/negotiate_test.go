@@ -0,0 +1,180 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func reqWith(h map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/gateway", nil)
+	for k, v := range h {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestParseQList(t *testing.T) {
+	got := parseQList(" ko-KR , en-US;q=0.8, ja;q=0.9 ,fr;q=0, de; q = 0.8 ,, zh;q=abc")
+	want := []qItem{{"ko-kr", 1}, {"zh", 1}, {"ja", 0.9}, {"en-us", 0.8}, {"de", 0.8}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	cases := []struct {
+		accept string
+		want   string
+	}{
+		{"", FormatJSON},
+		{"application/json", FormatJSON},
+		{"application/problem+json", FormatProblem},
+		{"application/xml", formatXML},
+		{"text/xml", formatXML},
+		{"text/plain", formatPlain},
+		{"*/*", FormatJSON},
+		{"application/*", FormatJSON},
+		{"text/html", FormatJSON},                                   // 지원하지 않는 형식만 → 기본
+		{"text/html, text/plain;q=0.5", formatPlain},                // 지원 형식으로 대체
+		{"application/json;q=0.5, application/xml", formatXML},      // q 우선
+		{"application/xml;q=0.9, text/plain;q=0.9", formatXML},      // 동일 q 는 입력 순서
+		{"application/xml;q=0, application/json;q=0.1", FormatJSON}, // q=0 은 거부
+		{"APPLICATION/PROBLEM+JSON", FormatProblem},
+	}
+	for _, c := range cases {
+		if got := negotiateFormat(reqWith(map[string]string{"Accept": c.accept})); got != c.want {
+			t.Errorf("Accept %q: got %s, want %s", c.accept, got, c.want)
+		}
+	}
+	if got := negotiateFormat(nil); got != errorFormat {
+		t.Errorf("nil request: got %s", got)
+	}
+}
+
+func TestNegotiateFormatDefault(t *testing.T) {
+	prev := errorFormat
+	t.Cleanup(func() { errorFormat = prev })
+	errorFormat = FormatProblem
+
+	for _, accept := range []string{"", "*/*", "text/html"} {
+		if got := negotiateFormat(reqWith(map[string]string{"Accept": accept})); got != FormatProblem {
+			t.Errorf("Accept %q: got %s, want errors.format", accept, got)
+		}
+	}
+}
+
+func TestNegotiateLang(t *testing.T) {
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"", "ko"},
+		{"en", "en"},
+		{"en-US,en;q=0.9", "en"},
+		{"EN_gb", "en"},
+		{"fr-FR, en;q=0.5", "en"},    // 미지원 언어는 다음 후보
+		{"fr, de", "ko"},             // 후보 모두 미지원 → errors.lang
+		{"ko;q=0.1, en;q=0.9", "en"}, // q 우선
+		{"*", "ko"},                  // 와일드카드 → errors.lang
+		{"en;q=0, ko;q=0.5", "ko"},   // q=0 은 거부
+		{"ja;q=0.9, *;q=0.5, en;q=0.1", "ko"},
+	}
+	for _, c := range cases {
+		if got := negotiateLang(reqWith(map[string]string{"Accept-Language": c.header})); got != c.want {
+			t.Errorf("Accept-Language %q: got %s, want %s", c.header, got, c.want)
+		}
+	}
+}
+
+func TestNegotiateLangBundle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ja.json"), []byte(`{"15":"権限がありません"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	prev := bundles
+	t.Cleanup(func() { bundles = prev })
+	if err := LoadMessageBundles(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := negotiateLang(reqWith(map[string]string{"Accept-Language": "ja-JP, en;q=0.5"})); got != "ja" {
+		t.Errorf("bundle lang: got %s, want ja", got)
+	}
+	if got := catalogMessage("15", "ja"); got != "権限がありません" {
+		t.Errorf("bundle message: got %q", got)
+	}
+	// 번들에 없는 코드는 내장 문구(영문)
+	if got := catalogMessage("17", "ja"); got == "" || got == catalogMessage("17", "ko") {
+		t.Errorf("fallback message: got %q", got)
+	}
+}
+
+func TestClientLocation(t *testing.T) {
+	cases := []struct {
+		header string
+		want   string
+	}{
+		{"", errorLoc.String()},
+		{"America/New_York", "America/New_York"},
+		{"  Europe/London ", "Europe/London"},
+		{"UTC", "UTC"},
+		{"Mars/Olympus", errorLoc.String()},
+		{"Local", errorLoc.String()}, // 서버 로컬 TZ 노출 방지
+		{"../../etc/passwd", errorLoc.String()},
+		{strings.Repeat("A", 65), errorLoc.String()},
+	}
+	for _, c := range cases {
+		if got := clientLocation(reqWith(map[string]string{"X-Timezone": c.header})).String(); got != c.want {
+			t.Errorf("X-Timezone %q: got %s, want %s", c.header, got, c.want)
+		}
+	}
+}
+
+func TestWriteErrorNegotiated(t *testing.T) {
+	r := reqWith(map[string]string{
+		"Accept":          "application/problem+json",
+		"Accept-Language": "en",
+		"X-Timezone":      "America/New_York",
+	})
+	w := httptest.NewRecorder()
+	WriteError(w, r, Err("15", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != problemContentType {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cl := w.Header().Get("Content-Language"); cl != "en" {
+		t.Errorf("Content-Language = %q", cl)
+	}
+	if v := w.Header().Get("Vary"); !strings.Contains(v, "Accept-Language") || !strings.Contains(v, "X-Timezone") {
+		t.Errorf("Vary = %q", v)
+	}
+	var p problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Code != "15" || p.Title != catalogMessage("15", "en") || p.Instance != "/gateway" {
+		t.Errorf("problem = %+v", p)
+	}
+	ts, err := time.Parse(time.RFC3339, p.Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny, _ := time.LoadLocation("America/New_York")
+	_, got := ts.Zone()
+	_, want := time.Now().In(ny).Zone()
+	if got != want {
+		t.Errorf("timestamp %s: offset %d, want America/New_York %d", p.Timestamp, got, want)
+	}
+}
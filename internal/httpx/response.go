@@ -18,16 +18,3 @@ func WriteJSON(w http.ResponseWriter, status int, v any) {
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(v)
 }
-
-// 유틸 함수: 에러 응답 생성 (Gin 제거)
-func NewError(msg string, err error) Response {
-	var reason string
-	if err != nil {
-		reason = err.Error()
-	}
-	return Response{
-		Success: false,
-		Message: msg,
-		Data:    map[string]any{"error": reason},
-	}
-}
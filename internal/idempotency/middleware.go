@@ -0,0 +1,279 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"service-gateway/internal/header"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/model"
+	"strings"
+	"time"
+)
+
+/*
+멱등키(IdempotencyKey) 이중거래 체크
+
+WHY:
+- X-Fw-Header 의 IdempotencyKey 는 "존재 시 G/W 에서 이중거래 체크" 로 정의돼 있었지만 검사하는 곳이 없어
+  클라이언트 재시도(타임아웃 후 재전송)가 업무 서비스에 그대로 두 번 전달됐음.
+
+정책:
+- 대상: POST / PUT (methods 로 변경), 키는 X-Fw-Header IdempotencyKey > Idempotency-Key 헤더, 없으면 미체크
+- 범위: 인증된 호출자(Identity: API 키 ID / 인증서 / JWT sub, 없으면 클라이언트 IP) + 키
+  → 클라이언트가 선언한 BizSrvcCd 로 범위를 잡으면 다른 호출자가 같은 키로 남의 응답을 재응답받거나 422 로 막을 수 있음
+- 첫 요청: 처리 중으로 선점 → 응답(상태/헤더/본문)을 ttl 동안 저장
+- 재전송: 저장된 응답 그대로 + Idempotent-Replayed: true
+- 처리 중 중복: wait 동안 완료를 기다렸다가 재전송 응답, 시간 초과 시 409(23)
+- 같은 키로 메서드/경로/본문이 다르면 422(25)
+- 5xx 응답, max_body 초과 응답은 저장하지 않고 선점 해제 → 재시도 허용
+- 400/401/403/404/429 도 저장하지 않음: 대부분 업스트림 호출 전 게이트웨이 인증·정책 거부이고,
+  지문에 X-Fw-Header 가 없어 FwAuthorization 갱신 / 권한 부여 후 재시도에도 ttl 동안 같은 거부가 재응답됐음
+- 저장소 오류 시 503(22) (중복 전달 위험이 있으므로 통과시키지 않음)
+
+배치: JWT / ProxyHeaders 안쪽(호출자 식별 후), BodyLimit 안쪽(본문 크기 제한 후), 세션 미들웨어 바깥(재응답에 세션 미발급)
+*/
+
+// Config: gateway.yaml idempotency 블록
+type Config struct {
+	Methods []string      // 기본 POST, PUT
+	TTL     time.Duration // 완료 응답 보관 (기본 24h)
+	LockTTL time.Duration // 처리 중 선점 유지 (기본 60s, 업스트림 타임아웃보다 길게)
+	Wait    time.Duration // 처리 중 중복 요청 대기 (0 이면 즉시 409)
+	MaxBody int64         // 저장할 응답 본문 최대 크기 (기본 1MiB)
+	// Identity: 키 범위가 되는 인증된 호출자 식별자 (nil 이면 RemoteAddr IP)
+	Identity func(r *http.Request) string
+	Logger   *slog.Logger
+}
+
+// ReplayedHeader: 저장된 응답으로 응답했음을 표시
+const ReplayedHeader = "Idempotent-Replayed"
+
+const pollInterval = 50 * time.Millisecond
+
+// unstored: 저장하지 않는 4xx (재시도 시 결과가 바뀔 수 있는 인증 / 정책 거부)
+var unstored = map[int]bool{
+	http.StatusBadRequest:      true,
+	http.StatusUnauthorized:    true,
+	http.StatusForbidden:       true,
+	http.StatusNotFound:        true,
+	http.StatusTooManyRequests: true,
+}
+
+type Guard struct {
+	store   Store
+	cfg     Config
+	methods map[string]bool
+	log     *slog.Logger
+}
+
+func New(store Store, cfg Config) *Guard {
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = []string{http.MethodPost, http.MethodPut}
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if cfg.LockTTL <= 0 {
+		cfg.LockTTL = time.Minute
+	}
+	if cfg.MaxBody <= 0 {
+		cfg.MaxBody = 1 << 20
+	}
+	methods := make(map[string]bool, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		methods[strings.ToUpper(strings.TrimSpace(m))] = true
+	}
+	lg := cfg.Logger
+	if lg == nil {
+		lg = slog.Default()
+	}
+	return &Guard{store: store, cfg: cfg, methods: methods, log: lg.With("component", "idempotency")}
+}
+
+// Key: 요청의 멱등키 (없으면 "")
+func Key(r *http.Request) string {
+	if k := header.Parse(r.Header.Get("X-Fw-Header"))["IdempotencyKey"]; k != "" {
+		return k
+	}
+	return strings.TrimSpace(r.Header.Get("Idempotency-Key"))
+}
+
+func (m *Guard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idemKey := Key(r)
+		if idemKey == "" || !m.methods[r.Method] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeBodyTooLarge, err))
+				return
+			}
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeBadRequest, err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		key := m.identity(r) + "|" + idemKey
+		fp := fingerprint(r, body)
+		deadline := time.Now().Add(m.cfg.Wait)
+
+		for {
+			rec, reserved, err := m.store.Reserve(ctx, key, fp, m.cfg.LockTTL)
+			if err != nil {
+				m.log.ErrorContext(ctx, "reserve failed", "err", err)
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeUnavailable, err))
+				return
+			}
+			if reserved {
+				m.serve(w, r, next, key, fp)
+				return
+			}
+			if rec.Fingerprint != fp {
+				m.log.WarnContext(ctx, "key reused with different request", "key", idemKey)
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeIdemMismatch, nil))
+				return
+			}
+			if rec.Done {
+				m.log.DebugContext(ctx, "replay stored response", "key", idemKey, "status", rec.Status)
+				replay(w, rec)
+				return
+			}
+
+			// 처리 중: 완료(또는 해제)될 때까지 대기
+			if !time.Now().Before(deadline) {
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeConflict, errors.New("idempotency key in progress")))
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollInterval):
+			}
+		}
+	})
+}
+
+func (m *Guard) identity(r *http.Request) string {
+	if m.cfg.Identity != nil {
+		return m.cfg.Identity(r)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// serve: 선점한 요청 처리 후 응답 저장 (실패/패닉 시 해제)
+func (m *Guard) serve(w http.ResponseWriter, r *http.Request, next http.Handler, key, fp string) {
+	cw := &captureWriter{ResponseWriter: w, max: m.cfg.MaxBody}
+	// 클라이언트가 끊겨도 저장/해제는 마무리 (재전송이 올 수 있음)
+	ctx := context.WithoutCancel(r.Context())
+	done := false
+	defer func() {
+		if !done {
+			// 5xx / 인증·정책 거부 / 패닉 등으로 완료하지 못한 경우: 선점을 풀어 재시도 허용
+			if err := m.store.Release(ctx, key); err != nil {
+				m.log.WarnContext(ctx, "release failed", "err", err)
+			}
+		}
+	}()
+
+	next.ServeHTTP(cw, r)
+
+	status := cw.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if status >= 500 || unstored[status] || cw.overflow {
+		return
+	}
+	rec := &Record{
+		Fingerprint: fp,
+		Status:      status,
+		Header:      storableHeader(cw.Header()),
+		Body:        cw.buf.Bytes(),
+	}
+	if err := m.store.Complete(ctx, key, rec, m.cfg.TTL); err != nil {
+		m.log.WarnContext(ctx, "complete failed", "err", err)
+		return
+	}
+	done = true
+}
+
+func replay(w http.ResponseWriter, rec *Record) {
+	for k, vv := range rec.Header {
+		w.Header()[k] = append([]string(nil), vv...)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(rec.Status)
+	_, _ = w.Write(rec.Body)
+}
+
+// fingerprint: 같은 키로 다른 요청이 들어왔는지 판별
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	h.Write([]byte{0})
+	io.WriteString(h, r.URL.RequestURI())
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// storableHeader: 재전송 시 다시 만들어지는 헤더 제외
+func storableHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, k := range []string{"Connection", "Keep-Alive", "Transfer-Encoding", "Date", "Content-Length", "X-Fw-Header", ReplayedHeader} {
+		out.Del(k)
+	}
+	return out
+}
+
+// captureWriter: 응답을 그대로 내보내면서 max 까지 복사
+type captureWriter struct {
+	http.ResponseWriter
+	status   int
+	buf      bytes.Buffer
+	max      int64
+	overflow bool
+}
+
+func (w *captureWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.overflow {
+		if int64(w.buf.Len()+len(b)) > w.max {
+			w.overflow = true
+			w.buf.Reset()
+		} else {
+			w.buf.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
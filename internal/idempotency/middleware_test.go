@@ -0,0 +1,227 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counting: 호출 수를 세고 status / body 로 응답하는 업스트림 대역
+type counting struct {
+	calls  atomic.Int32
+	status atomic.Int32
+	body   string
+	block  chan struct{} // nil 이 아니면 닫힐 때까지 응답 보류
+}
+
+func (c *counting) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.calls.Add(1)
+	if c.block != nil {
+		<-c.block
+	}
+	w.Header().Set("X-Upstream", "yes")
+	w.WriteHeader(int(c.status.Load()))
+	_, _ = w.Write([]byte(c.body))
+}
+
+func newCounting(status int, body string) *counting {
+	c := &counting{body: body}
+	c.status.Store(int32(status))
+	return c
+}
+
+func idemRequest(key, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/pay", strings.NewReader(body))
+	r.RemoteAddr = "198.51.100.7:5000"
+	if key != "" {
+		r.Header.Set("X-Fw-Header", "TCID=T1;IdempotencyKey="+key)
+	}
+	return r
+}
+
+func do(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestReserveAndReplay(t *testing.T) {
+	up := newCounting(http.StatusCreated, "created")
+	h := New(NewMemoryStore(), Config{}).Middleware(up)
+
+	first := do(h, idemRequest("k1", `{"amt":1}`))
+	second := do(h, idemRequest("k1", `{"amt":1}`))
+
+	if up.calls.Load() != 1 {
+		t.Fatalf("upstream calls = %d, want 1", up.calls.Load())
+	}
+	if first.Header().Get(ReplayedHeader) != "" {
+		t.Error("first response marked as replay")
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != "created" ||
+		second.Header().Get("X-Upstream") != "yes" || second.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("replay = %d %q %v", second.Code, second.Body, second.Header())
+	}
+}
+
+func TestPassThrough(t *testing.T) {
+	up := newCounting(http.StatusOK, "ok")
+	h := New(NewMemoryStore(), Config{}).Middleware(up)
+
+	do(h, idemRequest("", "x"))
+	do(h, idemRequest("", "x"))
+	get := httptest.NewRequest(http.MethodGet, "/api/pay", nil)
+	get.Header.Set("Idempotency-Key", "k1")
+	do(h, get)
+	do(h, get.Clone(context.Background()))
+	if up.calls.Load() != 4 {
+		t.Errorf("upstream calls = %d, want 4 (no key / GET not guarded)", up.calls.Load())
+	}
+}
+
+func TestKeyScopedByIdentity(t *testing.T) {
+	up := newCounting(http.StatusOK, "ok")
+	h := New(NewMemoryStore(), Config{}).Middleware(up)
+
+	do(h, idemRequest("k1", "x"))
+	other := idemRequest("k1", "x")
+	other.RemoteAddr = "203.0.113.9:5000"
+	if w := do(h, other); w.Header().Get(ReplayedHeader) != "" {
+		t.Error("other caller got a replay")
+	}
+	if up.calls.Load() != 2 {
+		t.Errorf("upstream calls = %d, want 2", up.calls.Load())
+	}
+}
+
+func TestFingerprintMismatch(t *testing.T) {
+	up := newCounting(http.StatusOK, "ok")
+	h := New(NewMemoryStore(), Config{}).Middleware(up)
+
+	do(h, idemRequest("k1", `{"amt":1}`))
+	if w := do(h, idemRequest("k1", `{"amt":2}`)); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different body: status %d, want 422", w.Code)
+	}
+	r := idemRequest("k1", `{"amt":1}`)
+	r.URL.Path = "/api/refund"
+	if w := do(h, r); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("different path: status %d, want 422", w.Code)
+	}
+	if up.calls.Load() != 1 {
+		t.Errorf("upstream calls = %d, want 1", up.calls.Load())
+	}
+}
+
+func TestInProgress(t *testing.T) {
+	up := newCounting(http.StatusOK, "ok")
+	up.block = make(chan struct{})
+	store := NewMemoryStore()
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- do(New(store, Config{}).Middleware(up), idemRequest("k1", "x")) }()
+	for up.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// wait 0 → 즉시 409
+	if w := do(New(store, Config{}).Middleware(up), idemRequest("k1", "x")); w.Code != http.StatusConflict {
+		t.Errorf("no wait: status %d, want 409", w.Code)
+	}
+	// wait 초과 → 409
+	start := time.Now()
+	if w := do(New(store, Config{Wait: 120 * time.Millisecond}).Middleware(up), idemRequest("k1", "x")); w.Code != http.StatusConflict {
+		t.Errorf("wait timeout: status %d, want 409", w.Code)
+	}
+	if time.Since(start) < 100*time.Millisecond {
+		t.Error("409 returned before wait elapsed")
+	}
+
+	// wait 중 완료 → 첫 응답 재응답
+	waiter := make(chan *httptest.ResponseRecorder)
+	go func() {
+		waiter <- do(New(store, Config{Wait: 5 * time.Second}).Middleware(up), idemRequest("k1", "x"))
+	}()
+	time.Sleep(2 * pollInterval)
+	close(up.block)
+	<-first
+	if w := <-waiter; w.Code != http.StatusOK || w.Header().Get(ReplayedHeader) != "true" {
+		t.Errorf("waiter: status %d, replayed %q", w.Code, w.Header().Get(ReplayedHeader))
+	}
+	if up.calls.Load() != 1 {
+		t.Errorf("upstream calls = %d, want 1", up.calls.Load())
+	}
+}
+
+// 5xx / 인증·정책 거부 / max_body 초과는 저장하지 않고 선점 해제 → 같은 키 재시도가 다시 처리됨
+func TestReleaseOnUnstored(t *testing.T) {
+	for _, c := range []struct {
+		name   string
+		status int
+		body   string
+		cfg    Config
+	}{
+		{"5xx", http.StatusBadGateway, "down", Config{}},
+		{"400", http.StatusBadRequest, "bad header", Config{}},
+		{"401", http.StatusUnauthorized, "bad token", Config{}},
+		{"403", http.StatusForbidden, "no permission", Config{}},
+		{"404", http.StatusNotFound, "unknown api", Config{}},
+		{"429", http.StatusTooManyRequests, "slow down", Config{}},
+		{"overflow", http.StatusOK, "0123456789", Config{MaxBody: 4}},
+	} {
+		up := newCounting(c.status, c.body)
+		h := New(NewMemoryStore(), c.cfg).Middleware(up)
+
+		if w := do(h, idemRequest("k1", "x")); w.Code != c.status || w.Body.String() != c.body {
+			t.Errorf("%s: first %d %q", c.name, w.Code, w.Body)
+		}
+		up.status.Store(http.StatusOK)
+		w := do(h, idemRequest("k1", "x"))
+		if w.Code != http.StatusOK || w.Header().Get(ReplayedHeader) != "" {
+			t.Errorf("%s: retry %d replayed=%q", c.name, w.Code, w.Header().Get(ReplayedHeader))
+		}
+		if up.calls.Load() != 2 {
+			t.Errorf("%s: upstream calls = %d, want 2", c.name, up.calls.Load())
+		}
+	}
+}
+
+func TestReleaseOnPanic(t *testing.T) {
+	store := NewMemoryStore()
+	h := New(store, Config{}).Middleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("boom") }))
+	func() {
+		defer func() { _ = recover() }()
+		do(h, idemRequest("k1", "x"))
+	}()
+	if _, reserved, _ := store.Reserve(context.Background(), "198.51.100.7|k1", "fp", time.Minute); !reserved {
+		t.Error("reservation kept after panic")
+	}
+}
+
+func TestMemoryStoreReleaseKeepsDone(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryStore()
+	if _, reserved, _ := m.Reserve(ctx, "k", "fp", time.Minute); !reserved {
+		t.Fatal("first reserve failed")
+	}
+	if err := m.Complete(ctx, "k", &Record{Fingerprint: "fp", Status: 200}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	_ = m.Release(ctx, "k")
+	rec, reserved, _ := m.Reserve(ctx, "k", "fp", time.Minute)
+	if reserved || rec == nil || !rec.Done || rec.Status != 200 {
+		t.Errorf("completed record released: %v %v", rec, reserved)
+	}
+
+	// 선점 만료 후에는 다시 선점 가능
+	if _, reserved, _ := m.Reserve(ctx, "short", "fp", time.Millisecond); !reserved {
+		t.Fatal("reserve failed")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, reserved, _ := m.Reserve(ctx, "short", "fp", time.Minute); !reserved {
+		t.Error("expired lock not reclaimed")
+	}
+}
@@ -0,0 +1,89 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record: 멱등키 1건 (처리 중이면 Done=false, 완료되면 첫 응답 보관)
+type Record struct {
+	Fingerprint string // 메서드 + 경로 + 본문 해시 (같은 키로 다른 요청 판별)
+	Done        bool
+	Status      int
+	Header      http.Header
+	Body        []byte
+}
+
+// Store: 멱등키 저장소 (여러 인스턴스 공유가 필요하면 Redis/DB 구현으로 교체)
+type Store interface {
+	// Reserve: 키가 없거나 만료됐으면 처리 중으로 선점 후 (nil, true), 이미 있으면 (기존 Record, false)
+	Reserve(ctx context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error)
+	// Complete: 응답 저장 (ttl 동안 재전송에 그대로 응답)
+	Complete(ctx context.Context, key string, rec *Record, ttl time.Duration) error
+	// Release: 선점 해제 (5xx 등 재시도를 허용할 때)
+	Release(ctx context.Context, key string) error
+}
+
+// MemoryStore: 단일 인스턴스용 기본 구현 (재기동 시 유실)
+type MemoryStore struct {
+	mu        sync.Mutex
+	items     map[string]memEntry
+	lastSweep time.Time
+}
+
+type memEntry struct {
+	rec     Record
+	expires time.Time
+}
+
+const sweepInterval = time.Minute
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: map[string]memEntry{}, lastSweep: time.Now()}
+}
+
+func (m *MemoryStore) Reserve(_ context.Context, key, fingerprint string, lockTTL time.Duration) (*Record, bool, error) {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(now)
+	if e, ok := m.items[key]; ok && now.Before(e.expires) {
+		rec := e.rec
+		return &rec, false, nil
+	}
+	m.items[key] = memEntry{rec: Record{Fingerprint: fingerprint}, expires: now.Add(lockTTL)}
+	return nil, true, nil
+}
+
+func (m *MemoryStore) Complete(_ context.Context, key string, rec *Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := *rec
+	r.Done = true
+	m.items[key] = memEntry{rec: r, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+func (m *MemoryStore) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.items[key]; ok && !e.rec.Done {
+		delete(m.items, key)
+	}
+	return nil
+}
+
+// sweep: 만료 항목 정리 (별도 goroutine 없이 Reserve 시 주기적으로)
+func (m *MemoryStore) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	for k, e := range m.items {
+		if !now.Before(e.expires) {
+			delete(m.items, k)
+		}
+	}
+	m.lastSweep = now
+}
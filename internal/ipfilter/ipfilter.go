@@ -0,0 +1,110 @@
+package ipfilter
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+/*
+IP 허용/차단 목록 (CIDR)
+
+- 항목은 단일 IP(10.0.0.1) 또는 CIDR(10.0.0.0/8), IPv4-mapped IPv6 는 IPv4 로 정규화
+- 판정: deny 매칭 → 차단, allow 가 있으면 매칭되어야 허용, 둘 다 비어 있으면 허용
+- 라우트(routes[].options.ip_allow / ip_deny), 업무서비스(ip_filter.biz_srvc, SID_BIZ_SRVC_IP_ACL) 에서 사용
+*/
+
+// List: 허용/차단 목록 (nil 이면 전체 허용)
+type List struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// Parse: 항목 형식 오류는 에러 (기동 / 관리 API 검증 시점에 발견)
+func Parse(allow, deny []string) (*List, error) {
+	l := &List{}
+	var err error
+	if l.allow, err = ParsePrefixes(allow); err != nil {
+		return nil, err
+	}
+	if l.deny, err = ParsePrefixes(deny); err != nil {
+		return nil, err
+	}
+	if len(l.allow) == 0 && len(l.deny) == 0 {
+		return nil, nil
+	}
+	return l, nil
+}
+
+// ParsePrefixes: IP / CIDR 목록
+func ParsePrefixes(items []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(items))
+	for _, s := range items {
+		p, err := ParsePrefix(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// ParsePrefix: "10.0.0.1" → 10.0.0.1/32, "10.0.0.0/8" 그대로
+func ParsePrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR %q", s)
+		}
+		if p.Addr().Is4In6() {
+			p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+		}
+		return p.Masked(), nil
+	}
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP %q", s)
+	}
+	a = a.Unmap()
+	return netip.PrefixFrom(a, a.BitLen()), nil
+}
+
+// Contains: 목록 중 하나라도 포함
+func Contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, p := range prefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed: 판정 (IP 를 모르면 allow 목록이 있을 때 차단)
+func (l *List) Allowed(ip netip.Addr) bool {
+	if l == nil {
+		return true
+	}
+	if !ip.IsValid() {
+		return len(l.allow) == 0
+	}
+	if Contains(l.deny, ip) {
+		return false
+	}
+	return len(l.allow) == 0 || Contains(l.allow, ip)
+}
+
+// Merge: 두 목록 합침 (설정 + DB)
+func Merge(a, b *List) *List {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return &List{
+		allow: append(append([]netip.Prefix{}, a.allow...), b.allow...),
+		deny:  append(append([]netip.Prefix{}, a.deny...), b.deny...),
+	}
+}
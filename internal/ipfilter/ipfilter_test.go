@@ -0,0 +1,92 @@
+package ipfilter
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func mustList(t *testing.T, allow, deny []string) *List {
+	t.Helper()
+	l, err := Parse(allow, deny)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func TestAllowed(t *testing.T) {
+	cases := []struct {
+		name        string
+		allow, deny []string
+		ip          string // "" 이면 알 수 없는 IP (zero Addr)
+		want        bool
+	}{
+		{"empty list", nil, nil, "203.0.113.5", true},
+		{"allow match", []string{"10.0.0.0/8"}, nil, "10.1.2.3", true},
+		{"allow miss", []string{"10.0.0.0/8"}, nil, "11.0.0.1", false},
+		{"deny match", nil, []string{"203.0.113.0/24"}, "203.0.113.5", false},
+		{"deny miss", nil, []string{"203.0.113.0/24"}, "198.51.100.1", true},
+		{"deny over allow", []string{"10.0.0.0/8"}, []string{"10.0.0.5"}, "10.0.0.5", false},
+		{"allow with deny elsewhere", []string{"10.0.0.0/8"}, []string{"10.0.0.5"}, "10.0.0.6", true},
+		{"IPv4-mapped vs IPv4 rule", []string{"10.0.0.0/8"}, nil, "::ffff:10.0.0.1", true},
+		{"IPv4-mapped rule vs IPv4", []string{"::ffff:10.0.0.0/104"}, nil, "10.0.0.1", true},
+		{"IPv6 allow", []string{"2001:db8::/32"}, nil, "2001:db8::1", true},
+		{"IPv6 vs IPv4 rule", []string{"10.0.0.0/8"}, nil, "2001:db8::1", false},
+		{"invalid IP with allow list", []string{"10.0.0.0/8"}, nil, "", false},
+		{"invalid IP with deny only", nil, []string{"10.0.0.0/8"}, "", true},
+	}
+	for _, c := range cases {
+		l := mustList(t, c.allow, c.deny)
+		var ip netip.Addr
+		if c.ip != "" {
+			ip = netip.MustParseAddr(c.ip)
+		}
+		if got := l.Allowed(ip); got != c.want {
+			t.Errorf("%s: Allowed(%s) = %v, want %v", c.name, c.ip, got, c.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	if l := mustList(t, nil, nil); l != nil {
+		t.Errorf("empty lists = %+v, want nil (allow all)", l)
+	}
+	var nilList *List
+	if !nilList.Allowed(netip.Addr{}) {
+		t.Error("nil list must allow")
+	}
+	for _, bad := range []string{"10.0.0.256", "10.0.0.0/33", "host.example.com", "10.0.0.0/x", ""} {
+		if _, err := Parse([]string{bad}, nil); err == nil {
+			t.Errorf("Parse(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParsePrefix(t *testing.T) {
+	cases := map[string]string{
+		"10.0.0.1":            "10.0.0.1/32",
+		" 10.0.0.1 ":          "10.0.0.1/32",
+		"10.1.2.3/8":          "10.0.0.0/8", // 호스트 비트 정리
+		"2001:db8::1":         "2001:db8::1/128",
+		"::ffff:10.0.0.1":     "10.0.0.1/32",
+		"::ffff:10.0.0.0/104": "10.0.0.0/8",
+	}
+	for in, want := range cases {
+		p, err := ParsePrefix(in)
+		if err != nil || p.String() != want {
+			t.Errorf("ParsePrefix(%q) = %s %v, want %s", in, p, err, want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	cfg := mustList(t, []string{"10.0.0.0/8"}, nil)
+	db := mustList(t, nil, []string{"10.0.0.5"})
+	m := Merge(cfg, db)
+	if m.Allowed(netip.MustParseAddr("10.0.0.5")) || !m.Allowed(netip.MustParseAddr("10.0.0.6")) {
+		t.Error("merged list must apply both allow and deny")
+	}
+	if Merge(nil, db) != db || Merge(cfg, nil) != cfg {
+		t.Error("merge with nil must return the other list")
+	}
+}
@@ -0,0 +1,108 @@
+package ipfilter
+
+import (
+	"context"
+	"log/slog"
+	"net/netip"
+	"service-gateway/internal/model"
+	"sync"
+	"time"
+)
+
+/*
+업무서비스(BIZ_SRVC_CD)별 IP 허용/차단
+
+- 설정(ip_filter.biz_srvc) + DB(SID_BIZ_SRVC_IP_ACL, ACL_TYP A=허용 / D=차단) 를 합쳐 판정
+- ttl 동안 메모리 캐시, 관리 API 변경 시 Invalidate(), 적재 실패 시 이전 목록 유지
+*/
+
+// Source: DB 목록 조회 (store.Repository 가 구현, nil 이면 설정만 사용)
+type Source interface {
+	ListIPAcls(ctx context.Context) ([]model.IPAcl, error)
+}
+
+type Registry struct {
+	src    Source
+	ttl    time.Duration
+	static map[string]*List
+
+	mu       sync.RWMutex
+	lists    map[string]*List
+	loadedAt time.Time
+}
+
+func NewRegistry(src Source, static map[string]*List, ttl time.Duration) *Registry {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Registry{src: src, ttl: ttl, static: static, lists: static}
+}
+
+// Allowed: 업무서비스 목록이 없으면 허용
+func (r *Registry) Allowed(ctx context.Context, bizCd string, ip netip.Addr) bool {
+	if r == nil {
+		return true
+	}
+	return r.load(ctx)[bizCd].Allowed(ip)
+}
+
+// Invalidate: 다음 Allowed 에서 DB 재조회 (admin.Invalidator)
+func (r *Registry) Invalidate() {
+	r.mu.Lock()
+	r.loadedAt = time.Time{}
+	r.mu.Unlock()
+}
+
+func (r *Registry) load(ctx context.Context) map[string]*List {
+	if r.src == nil {
+		return r.static
+	}
+	r.mu.RLock()
+	if time.Since(r.loadedAt) < r.ttl {
+		ls := r.lists
+		r.mu.RUnlock()
+		return ls
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.loadedAt) < r.ttl { // 다른 goroutine 이 먼저 적재
+		return r.lists
+	}
+
+	rows, err := r.src.ListIPAcls(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "load ip acls failed, keep previous", "component", "ipfilter", "err", err)
+		r.loadedAt = time.Now()
+		return r.lists
+	}
+	allow := map[string][]netip.Prefix{}
+	deny := map[string][]netip.Prefix{}
+	for _, a := range rows {
+		p, err := ParsePrefix(a.CIDR)
+		if err != nil {
+			slog.WarnContext(ctx, "skip ip acl", "component", "ipfilter", "acl_id", a.ID, "err", err)
+			continue
+		}
+		if a.Type == "D" {
+			deny[a.BizServiceCode] = append(deny[a.BizServiceCode], p)
+		} else {
+			allow[a.BizServiceCode] = append(allow[a.BizServiceCode], p)
+		}
+	}
+	lists := make(map[string]*List, len(r.static)+len(allow)+len(deny))
+	for cd, l := range r.static {
+		lists[cd] = l
+	}
+	for cd := range allow {
+		lists[cd] = Merge(lists[cd], &List{allow: allow[cd], deny: deny[cd]})
+		delete(deny, cd)
+	}
+	for cd := range deny {
+		lists[cd] = Merge(lists[cd], &List{deny: deny[cd]})
+	}
+	r.lists = lists
+	r.loadedAt = time.Now()
+	return lists
+}
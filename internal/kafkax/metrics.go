@@ -0,0 +1,26 @@
+package kafkax
+
+import "expvar"
+
+// 발행 통계: expvar "kafkax" (관리 리스너 GET /admin/v1/metrics 로 노출)
+var (
+	metrics = expvar.NewMap("kafkax")
+
+	publishedCount = new(expvar.Int) // Publish 호출 수
+	droppedCount   = new(expvar.Int) // 버퍼/스풀 가득 참, Close 이후 발행 등으로 버린 수
+	spooledCount   = new(expvar.Int) // 디스크 스풀에 기록한 수
+	sentCount      = new(expvar.Int) // Kafka 전송 성공 수
+	failedCount    = new(expvar.Int) // Kafka 전송 실패(재시도 전) 수
+	spoolBytes     = new(expvar.Int) // 현재 스풀 디스크 사용량
+	spoolCorrupt   = new(expvar.Int) // 손상되어 건너뛴 레코드 수
+)
+
+func init() {
+	metrics.Set("published", publishedCount)
+	metrics.Set("dropped", droppedCount)
+	metrics.Set("spooled", spooledCount)
+	metrics.Set("sent", sentCount)
+	metrics.Set("failed", failedCount)
+	metrics.Set("spool_bytes", spoolBytes)
+	metrics.Set("spool_corrupt", spoolCorrupt)
+}
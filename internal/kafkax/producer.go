@@ -1,12 +1,15 @@
 package kafkax
 
 import (
-	"context"    // WriteMessages에 타임아웃을 적용하기 위해 필요
-	"crypto/tls" // TLS 설정을 위해 필요
-	"errors"     // 설정 검증 시 에러 리턴을 위해 필요
-	"log"        // 장애 시 서버 다운 방지용 경고 로그용
-	"sync"       // 안전한 종료 및 버퍼 처리 동기화를 위해 필요
-	"time"       // 타임아웃/배치 시간 제어를 위해 필요
+	"context"         // WriteMessages에 타임아웃을 적용하기 위해 필요
+	"crypto/tls"      // TLS 설정을 위해 필요
+	"encoding/binary" // 스풀 레코드 직렬화
+	"errors"          // 설정 검증 시 에러 리턴을 위해 필요
+	"log/slog"        // 장애 시 서버 다운 방지용 경고 로그용 (main 에서 주입)
+	"sync"            // 안전한 종료 및 버퍼 처리 동기화를 위해 필요
+	"time"            // 타임아웃/배치 시간 제어를 위해 필요
+
+	"service-gateway/internal/logx" // 주입 Logger 미지정 시 slog.Default
 
 	"github.com/segmentio/kafka-go"            // Kafka 클라이언트
 	"github.com/segmentio/kafka-go/sasl/plain" // SASL/PLAIN 메커니즘 사용
@@ -16,7 +19,7 @@ import (
 type Config struct {
 	Enabled      bool          // 런타임에서 켜고 끌 수 있도록 분기
 	Brokers      []string      // 브로커 리스트
-	Topic        string        // ✅ 단일 토픽
+	Topic        string        // 기본 토픽 (Message.Topic 이 비어 있을 때)
 	ClientID     string        // 브로커 모니터링/제한 정책을 위한 식별자
 	Acks         string        // 내구성/지연 정책
 	Compression  string        // 네트워크/스토리지 비용 최적화
@@ -33,35 +36,151 @@ type Config struct {
 		Enabled            bool
 		InsecureSkipVerify bool
 	}
+	Spool SpoolConfig // 디스크 스풀 (감사 로그 유실 방지)
+
+	BatchSize    int            // 배치당 최대 메시지 수 (기본 100)
+	MaxInFlight  int            // 동시 전송 배치 수 (기본 4, 메모리 모드만. 1 이면 순서 보장)
+	DrainTimeout time.Duration  // Close 시 잔여 메시지 전송 대기 상한 (기본 10s)
+	OnDelivery   func(Delivery) // 메시지별 전송 결과 콜백 (전송 goroutine 에서 호출, 블로킹 금지)
+	Partitioner  string         // hash(기본) | crc32 | least_bytes
+	Logger       *slog.Logger   // nil 이면 slog.Default
+}
+
+// Delivery: 메시지 전송 결과 (Err == nil 이면 브로커 ack 수신)
+type Delivery struct {
+	Message
+	Err error
 }
 
 // Publisher: 호출 측에서 의존성 역전을 위해 인터페이스로 노출
 type Publisher interface {
-	Publish(key, value []byte) // 비동기로 넣고, 흐름 차단 방지
-	Close() error              // 애플리케이션 종료 시 자원 정리
+	Publish(m Message) // 비동기로 넣고, 흐름 차단 방지
+	Close() error      // 애플리케이션 종료 시 자원 정리
 }
 
 // noop: Kafka 꺼짐/미설정 환경에서도 앱이 동작하도록 보강
 type noop struct{}
 
-func (noop) Publish(key, value []byte) {}
-func (noop) Close() error              { return nil }
-func Noop() Publisher                  { return noop{} }
+func (noop) Publish(Message) {}
+func (noop) Close() error    { return nil }
+func Noop() Publisher        { return noop{} }
+
+// 스풀 레코드 payload
+// v1: [1B 버전][uvarint 키 길이][키][값]
+// v2: [1B 버전][토픽][uvarint 헤더 수]{[키][값]}...[키][값]  (문자열/바이트는 uvarint 길이 + 내용, 마지막 값은 나머지 전체)
+const (
+	spoolRecordV1 = 1
+	spoolRecordV2 = 2
+)
+
+func encodeMessage(m Message) []byte {
+	n := 1 + len(m.Topic) + len(m.Key) + len(m.Value) + 4*binary.MaxVarintLen64
+	for _, h := range m.Headers {
+		n += len(h.Key) + len(h.Value) + 2*binary.MaxVarintLen64
+	}
+	buf := make([]byte, 0, n)
+	buf = append(buf, spoolRecordV2)
+	buf = appendBytes(buf, []byte(m.Topic))
+	buf = binary.AppendUvarint(buf, uint64(len(m.Headers)))
+	for _, h := range m.Headers {
+		buf = appendBytes(buf, []byte(h.Key))
+		buf = appendBytes(buf, []byte(h.Value))
+	}
+	buf = appendBytes(buf, m.Key)
+	return append(buf, m.Value...)
+}
+
+func appendBytes(buf, b []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func decodeMessage(b []byte) (Message, error) {
+	if len(b) < 1 {
+		return Message{}, errCorruptRecord
+	}
+	d := decoder{b: b[1:]}
+	var m Message
+	switch b[0] {
+	case spoolRecordV1:
+	case spoolRecordV2:
+		m.Topic = string(d.bytes())
+		cnt := d.uvarint()
+		if cnt > uint64(len(d.b)) {
+			return Message{}, errCorruptRecord
+		}
+		for i := uint64(0); i < cnt && d.err == nil; i++ {
+			m.Headers = append(m.Headers, Header{Key: string(d.bytes()), Value: string(d.bytes())})
+		}
+	default:
+		return Message{}, errCorruptRecord
+	}
+	m.Key = d.bytes()
+	if d.err != nil {
+		return Message{}, d.err
+	}
+	if len(m.Key) == 0 {
+		m.Key = nil
+	}
+	m.Value = d.b
+	return m, nil
+}
+
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errCorruptRecord
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
 
-// 내부 전송 큐 타입: backpressure 없이 드롭 가능하도록 설계
-type message struct {
-	key []byte
-	val []byte
+func (d *decoder) bytes() []byte {
+	n := d.uvarint()
+	if d.err != nil {
+		return nil
+	}
+	if n > uint64(len(d.b)) {
+		d.err = errCorruptRecord
+		return nil
+	}
+	out := d.b[:n]
+	d.b = d.b[n:]
+	return out
 }
 
 type publisher struct {
-	cfg    Config         // 런타임 파라미터 보관
-	w      *kafka.Writer  // ✅ 단일 토픽용 Writer 하나만 유지(오버헤드 최소화)
-	ch     chan message   // 비동기 버퍼 채널(요청 경로 차단 방지)
-	wg     sync.WaitGroup // 안전한 종료를 위한 goroutine join
-	closed chan struct{}  // 종료 시그널
+	cfg     Config             // 런타임 파라미터 보관
+	w       messageWriter      // ✅ 단일 토픽용 Writer 하나만 유지(오버헤드 최소화)
+	ch      chan Message       // 비동기 버퍼 채널(요청 경로 차단 방지)
+	batches chan []Message     // 수집된 배치 → 전송 goroutine
+	sp      *spool             // 디스크 스풀 (nil 이면 메모리 채널 모드)
+	wg      sync.WaitGroup     // 안전한 종료를 위한 goroutine join
+	mu      sync.RWMutex       // closing 과 ch 송신 / 스풀 append 사이 경합 방지
+	closing bool               // Close 이후 Publish 는 드롭
+	closed  chan struct{}      // 종료 시그널
+	ctx     context.Context    // 전송 기본 컨텍스트 (drain 기한 초과 시 취소)
+	cancel  context.CancelFunc // 진행 중 전송 중단
+	log     *slog.Logger
+}
+
+// messageWriter: publisher 가 쓰는 *kafka.Writer 메서드 (테스트에서 브로커 없이 교체)
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
 }
 
+var errDrainTimeout = errors.New("kafka drain timeout")
+
 // NewPublisher: Transport 기반 writer 생성 (kafka-go v0.4.49 호환)
 func NewPublisher(cfg Config) (Publisher, error) {
 	// 기능 비활성화 시 noop으로 대체 → 런타임 토글 편의성
@@ -75,6 +194,17 @@ func NewPublisher(cfg Config) (Publisher, error) {
 	if cfg.Topic == "" {
 		return nil, errors.New("kafka topic empty")
 	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 4
+	}
+	if cfg.DrainTimeout <= 0 {
+		cfg.DrainTimeout = 10 * time.Second
+	}
+
+	logger := logx.Or(cfg.Logger).With("component", "kafka")
 
 	// ✅ v0.4.49: Dialer가 아닌 Transport에 TLS/SASL/Timeout/ClientID를 설정
 	tr := &kafka.Transport{
@@ -125,69 +255,306 @@ func NewPublisher(cfg Config) (Publisher, error) {
 		comp = kafka.Snappy // 기본값: 안정적인 선택
 	}
 
-	// ✅ 단일 토픽 Writer 구성: 토픽별 캐시는 불필요하므로 하나만 유지
+	// 파티셔너: 키(기본 TCID)가 같으면 같은 파티션 → 거래 단위 순서 보장
+	var balancer kafka.Balancer
+	switch cfg.Partitioner {
+	case "least_bytes":
+		balancer = &kafka.LeastBytes{} // 키 무시, 파티션 간 균등 분산
+	case "crc32":
+		balancer = &kafka.CRC32Balancer{} // librdkafka 기본 파티셔너와 동일 분배
+	default:
+		balancer = &kafka.Hash{} // 키 없으면 라운드로빈
+	}
+
+	// ✅ Writer 하나로 모든 토픽 전송: 토픽은 메시지마다 지정 (Writer.Topic 비움)
 	w := &kafka.Writer{
 		Addr:         kafka.TCP(cfg.Brokers...), // 브로커 접속점 설정
-		Topic:        cfg.Topic,                 // ✅ 모든 로그가 이 토픽으로 간다
-		Balancer:     &kafka.LeastBytes{},       // 파티션 간 균등 분산(키 제공 시 파티셔너가 재정의)
+		Balancer:     balancer,                  // 키 기반 파티션 선택
 		RequiredAcks: requiredAcks,              // 위에서 매핑한 내구성 정책 적용
 		Compression:  comp,                      // 네트워크 비용 절감
+		BatchSize:    cfg.BatchSize,             // 게이트웨이 배치 단위와 일치 → 한 번의 WriteMessages 가 한 요청으로 전송
 		BatchBytes:   cfg.BatchBytes,            // 배치 크기 제한으로 메모리/지연 균형
 		BatchTimeout: cfg.BatchTimeout,          // 배치 플러시 지연으로 처리량 향상
-		Async:        false,                     // 쓰기 자체는 동기(batch단), 외부는 비동기 채널로 감싼다
+		Async:        false,                     // 쓰기 자체는 동기(batch단), 외부는 배치 수집 + 동시 전송 goroutine 으로 감싼다
 		Transport:    tr,                        // ✅ v0.4.49 핵심: Dialer 대신 Transport 주입
 		// AllowAutoTopicCreation: false,                  // 운영에서 토픽 사전생성 강제하려면 해제(기본 false)
 	}
 
 	p := &publisher{
-		cfg:    cfg,                      // 종료/리뷰 시 설정 접근 필요
-		w:      w,                        // 단일 writer
-		ch:     make(chan message, 1000), // 버스트 트래픽 방어용 버퍼(가득 차면 드롭)
-		closed: make(chan struct{}),      // 종료 신호 전달
-	}
-	// 백그라운드 루프 시작: 채널의 메시지를 실제 Kafka로 전송
-	p.wg.Add(1)
-	go p.loop()
+		cfg:     cfg,                                   // 종료/리뷰 시 설정 접근 필요
+		log:     logger,                                // tcid 없는 백그라운드 로그 (component=kafka)
+		w:       w,                                     // 단일 writer
+		ch:      make(chan Message, 1000),              // 버스트 트래픽 방어용 버퍼(가득 차면 드롭)
+		batches: make(chan []Message, cfg.MaxInFlight), // 수집기 → 전송 goroutine
+		closed:  make(chan struct{}),                   // 종료 신호 전달
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	// 디스크 스풀 모드: Publish → 스풀, 전송 루프가 스풀을 순서대로 배치 소비 (순서 보장을 위해 in-flight 1)
+	if cfg.Spool.Enabled {
+		sp, err := openSpool(cfg.Spool, p.log.With("component", "kafka-spool"))
+		if err != nil {
+			return nil, err
+		}
+		p.sp = sp
+		p.wg.Add(1)
+		go p.spoolLoop()
+		return p, nil
+	}
+
+	// 메모리 모드: 수집기 1 + 전송 goroutine MaxInFlight 개
+	p.wg.Add(1 + cfg.MaxInFlight)
+	go p.collect()
+	for i := 0; i < cfg.MaxInFlight; i++ {
+		go p.sendLoop()
+	}
 	return p, nil
 }
 
-// loop: 백그라운드에서 채널을 소비하며 카프카에 쓰기
-func (p *publisher) loop() {
-	defer p.wg.Done() // 종료 대기 보장을 위해 필요
+// collect: 채널에서 BatchSize/BatchBytes 까지 모으거나 BatchTimeout 경과 시 배치 전달
+func (p *publisher) collect() {
+	defer p.wg.Done()
+	defer close(p.batches) // 전송 goroutine 종료 신호
+
+	var (
+		batch []Message
+		size  int64
+		timer *time.Timer
+		flush <-chan time.Time
+	)
+	emit := func() {
+		if len(batch) > 0 {
+			p.batches <- batch
+		}
+		batch, size = nil, 0
+		if timer != nil {
+			timer.Stop()
+			timer, flush = nil, nil
+		}
+	}
+	add := func(m Message) {
+		batch = append(batch, m)
+		size += int64(len(m.Key) + len(m.Value))
+		if len(batch) >= p.cfg.BatchSize || (p.cfg.BatchBytes > 0 && size >= p.cfg.BatchBytes) {
+			emit()
+			return
+		}
+		if timer == nil {
+			timer = time.NewTimer(max(p.cfg.BatchTimeout, time.Millisecond))
+			flush = timer.C
+		}
+	}
+
 	for {
 		select {
-		case m, ok := <-p.ch: // 버퍼에서 메시지 수신
-			if !ok {
-				return
-			} // 채널 닫힘: 정상 종료
-			ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout) // write 상한 시간 부여
-			err := p.w.WriteMessages(ctx, kafka.Message{
-				Key:   m.key, // 동일 키(TCID 등)로 파티션 일관성 보장
-				Value: m.val, // 직렬화된 로그 페이로드
-			})
-			cancel() // 리소스 누수 방지
-			if err != nil {
-				log.Printf("[kafka] write failed: %v", err) // 장애 시 서비스 흐름 차단 금지, 경고만 남김
+		case m := <-p.ch:
+			add(m)
+		case <-flush:
+			timer, flush = nil, nil
+			emit()
+		case <-p.closed:
+			// 종료: 버퍼 잔여분까지 배치로 넘기고 종료 (Publish 는 이미 차단됨)
+			for {
+				select {
+				case m := <-p.ch:
+					add(m)
+				default:
+					emit()
+					return
+				}
 			}
-		case <-p.closed: // 종료 신호 수신
+		}
+	}
+}
+
+// sendLoop: 배치 단위 동기 전송 (MaxInFlight 개가 동시에 동작)
+func (p *publisher) sendLoop() {
+	defer p.wg.Done()
+	for batch := range p.batches {
+		err := p.write(batch)
+		if err != nil {
+			failedCount.Add(int64(len(batch)))
+			p.log.Warn("write failed", "messages", len(batch), "err", err) // 장애 시 서비스 흐름 차단 금지, 경고만 남김
+		} else {
+			sentCount.Add(int64(len(batch)))
+		}
+		p.report(batch, err)
+	}
+}
+
+func (p *publisher) write(batch []Message) error {
+	msgs := make([]kafka.Message, len(batch))
+	for i, m := range batch {
+		topic := m.Topic
+		if topic == "" {
+			topic = p.cfg.Topic
+		}
+		msgs[i] = kafka.Message{
+			Topic: topic,
+			Key:   m.Key,   // 동일 키(TCID 등)로 파티션 일관성 보장
+			Value: m.Value, // 직렬화된 로그 페이로드
+		}
+		for _, h := range m.Headers {
+			msgs[i].Headers = append(msgs[i].Headers, kafka.Header{Key: h.Key, Value: []byte(h.Value)})
+		}
+	}
+	ctx, cancel := context.WithTimeout(p.ctx, p.cfg.Timeout) // write 상한 시간 부여
+	defer cancel()
+	return p.w.WriteMessages(ctx, msgs...)
+}
+
+func (p *publisher) report(batch []Message, err error) {
+	if p.cfg.OnDelivery == nil {
+		return
+	}
+	var werrs kafka.WriteErrors
+	perMsg := errors.As(err, &werrs) && len(werrs) == len(batch)
+	for i, m := range batch {
+		e := err
+		if perMsg {
+			e = werrs[i]
+		}
+		p.cfg.OnDelivery(Delivery{Message: m, Err: e})
+	}
+}
+
+// spoolLoop: 스풀을 기록 순서대로 배치로 읽어 전송, 성공해야 커서 전진 (브로커 장애 시 백오프 재시도)
+func (p *publisher) spoolLoop() {
+	defer p.wg.Done()
+	var batch []Message
+	for {
+		// 첫 레코드는 대기, 이후는 이미 쌓여 있는 만큼만 (전송 중 누적분이 다음 배치가 됨)
+		payload, next, err := p.sp.next(p.closed)
+		if err == errSpoolClosed {
 			return
 		}
+		var last spoolPos
+		consumed := false
+		for err == nil {
+			last, consumed = next, true
+			if m, derr := decodeMessage(payload); derr != nil {
+				spoolCorrupt.Add(1)
+				p.sp.log.Warn("skip undecodable record", "seg", next.seg, "off", next.off)
+			} else {
+				batch = append(batch, m)
+			}
+			if len(batch) >= p.cfg.BatchSize {
+				break
+			}
+			payload, next, err = p.sp.poll()
+		}
+		if err != nil && err != errSpoolEmpty {
+			p.sp.log.Error("read failed", "err", err)
+			if len(batch) == 0 && !p.sleep(time.Second) {
+				return
+			}
+		}
+		if len(batch) > 0 && !p.writeUntilSent(batch) {
+			return // 종료: 커서 미전진 → 재기동 시 이 배치부터 재전송
+		}
+		batch = batch[:0]
+		if !consumed {
+			continue
+		}
+		if err := p.sp.commit(last); err != nil {
+			p.sp.log.Error("cursor commit failed", "err", err)
+		}
+	}
+}
+
+// writeUntilSent: 전송 성공까지 재시도 (1s → 최대 30s 백오프), 종료 신호 시 false
+func (p *publisher) writeUntilSent(batch []Message) bool {
+	backoff := time.Second
+	for {
+		err := p.write(batch)
+		if err == nil {
+			sentCount.Add(int64(len(batch)))
+			p.report(batch, nil)
+			return true
+		}
+		failedCount.Add(int64(len(batch)))
+		p.sp.log.Warn("write failed, retry", "messages", len(batch), "backoff", backoff, "err", err)
+		if !p.sleep(backoff) {
+			return false
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+func (p *publisher) sleep(d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-p.closed:
+		return false
 	}
 }
 
-// Publish: 요청 경로를 차단하지 않도록 채널에 넣고 가득 차면 드롭
-func (p *publisher) Publish(key, value []byte) {
+// Publish: 요청 경로를 차단하지 않도록 채널에 넣고 가득 차면 드롭 (스풀 모드는 디스크 기록)
+func (p *publisher) Publish(m Message) {
+	publishedCount.Add(1)
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closing { // 종료 중 늦게 도착한 이벤트: 건별 에러 로그 없이 드롭 집계만
+		droppedCount.Add(1)
+		return
+	}
+	if p.sp != nil {
+		// RLock 유지 → Close 는 진행 중 append 가 끝난 뒤 스풀을 닫음
+		if err := p.sp.append(encodeMessage(m)); err != nil {
+			droppedCount.Add(1)
+			p.sp.log.Error("append failed, drop message", "err", err)
+			return
+		}
+		spooledCount.Add(1)
+		return
+	}
 	select {
-	case p.ch <- message{key: key, val: value}: // 평시: 비동기 큐 적재
+	case p.ch <- m: // 평시: 비동기 큐 적재
 	default:
-		log.Printf("[kafka] buffer full, drop message") // 폭주 시: 드롭해 게이트웨이 지연 전파 차단
+		droppedCount.Add(1)
+		p.log.Warn("buffer full, drop message") // 폭주 시: 드롭해 게이트웨이 지연 전파 차단
 	}
 }
 
-// Close: graceful shutdown을 위해 goroutine 종료 및 writer 닫기
+// Close: 신규 Publish 차단 → 잔여 배치 전송을 DrainTimeout 까지 대기 → 초과 시 진행 중 전송 취소
 func (p *publisher) Close() error {
-	close(p.closed)    // 루프 종료 신호
-	close(p.ch)        // 생산자 종료
-	p.wg.Wait()        // 루프가 남은 메시지 처리 후 종료되도록 대기
-	return p.w.Close() // 네트워크 자원 정리
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closing = true
+	p.mu.Unlock()
+	close(p.closed) // 수집기/스풀 루프 종료 신호
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-time.After(p.cfg.DrainTimeout):
+		err = errDrainTimeout
+		p.log.Warn("drain timeout, cancel in-flight writes", "timeout", p.cfg.DrainTimeout)
+		p.cancel()
+		<-done
+	}
+	p.cancel()
+
+	if p.sp != nil {
+		// 미전송분은 스풀에 남아 재기동 시 재전송
+		if cerr := p.sp.close(); cerr != nil {
+			p.sp.log.Error("close failed", "err", cerr)
+		}
+	}
+	if cerr := p.w.Close(); err == nil {
+		err = cerr // 네트워크 자원 정리
+	}
+	return err
 }
@@ -0,0 +1,147 @@
+package kafkax
+
+import (
+	"fmt"
+	"slices"
+)
+
+/*
+토픽 라우팅 / 파티션 키
+
+WHY:
+- 인바운드 요청/응답(11/12), 아웃바운드 송신/수신(21/22) 로그를 토픽별로 분리해 보존 기간/소비자를 다르게 운영.
+- 규칙은 위에서부터 첫 매칭 적용. match 항목끼리는 AND, 항목 내 값은 OR, match 가 비어 있으면 전체 매칭.
+- 매칭 없으면 기본 토픽(application.log.topic) + 기본 키(tcId, 기존 동작).
+
+키(partition key): tcId | bizSrvcCd | apiGroupCd | apiCd | guid | none
+- 같은 키는 같은 파티션 → 파티션 내 순서 보장 (partitioner: hash | crc32 | least_bytes)
+*/
+
+// Header: Kafka 레코드 헤더
+type Header struct {
+	Key   string
+	Value string
+}
+
+// Message: 발행 단위 (Topic 이 비어 있으면 기본 토픽)
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers []Header
+}
+
+// Fields: 라우팅/키 산출에 쓰는 로그 속성
+type Fields struct {
+	RasTyp     string
+	ApiGroupCd string
+	ApiCd      string
+	BizSrvcCd  string
+	Tcid       string
+	Guid       string
+}
+
+type RouteMatch struct {
+	RasTyp     []string
+	ApiGroupCd []string
+	BizSrvcCd  []string
+}
+
+type Route struct {
+	Match RouteMatch
+	Topic string // 비어 있으면 기본 토픽 (키만 바꾸는 규칙)
+	Key   string // 비어 있으면 기본 키
+}
+
+const (
+	KeyTcid       = "tcId"
+	KeyBizSrvcCd  = "bizSrvcCd"
+	KeyApiGroupCd = "apiGroupCd"
+	KeyApiCd      = "apiCd"
+	KeyGuid       = "guid"
+	KeyNone       = "none"
+)
+
+var keyNames = []string{KeyTcid, KeyBizSrvcCd, KeyApiGroupCd, KeyApiCd, KeyGuid, KeyNone}
+
+type Router struct {
+	routes     []Route
+	defaultKey string
+}
+
+// NewRouter: 규칙 검증 후 생성 (defaultKey 비어 있으면 tcId)
+func NewRouter(routes []Route, defaultKey string) (*Router, error) {
+	if defaultKey == "" {
+		defaultKey = KeyTcid
+	}
+	if !slices.Contains(keyNames, defaultKey) {
+		return nil, fmt.Errorf("kafka routing: unknown default key %q", defaultKey)
+	}
+	for i, rt := range routes {
+		if rt.Key != "" && !slices.Contains(keyNames, rt.Key) {
+			return nil, fmt.Errorf("kafka routing rule %d: unknown key %q", i, rt.Key)
+		}
+		if rt.Topic == "" && rt.Key == "" {
+			return nil, fmt.Errorf("kafka routing rule %d: topic or key required", i)
+		}
+	}
+	return &Router{routes: routes, defaultKey: defaultKey}, nil
+}
+
+// Message: 속성 → 토픽/키/헤더가 채워진 메시지 (nil Router 는 기본 토픽 + tcId 키)
+func (r *Router) Message(f Fields, value []byte) Message {
+	topic, key := "", KeyTcid
+	if r != nil {
+		key = r.defaultKey
+		for _, rt := range r.routes {
+			if !rt.Match.matches(f) {
+				continue
+			}
+			topic = rt.Topic
+			if rt.Key != "" {
+				key = rt.Key
+			}
+			break
+		}
+	}
+	return Message{
+		Topic: topic,
+		Key:   f.key(key),
+		Value: value,
+		Headers: []Header{
+			{Key: "rasTyp", Value: f.RasTyp},
+			{Key: "tcId", Value: f.Tcid},
+			{Key: "bizSrvcCd", Value: f.BizSrvcCd},
+			{Key: "apiGroupCd", Value: f.ApiGroupCd},
+		},
+	}
+}
+
+func (m RouteMatch) matches(f Fields) bool {
+	return in(m.RasTyp, f.RasTyp) && in(m.ApiGroupCd, f.ApiGroupCd) && in(m.BizSrvcCd, f.BizSrvcCd)
+}
+
+// in: 조건 없음(빈 목록)이면 매칭
+func in(values []string, v string) bool {
+	return len(values) == 0 || slices.Contains(values, v)
+}
+
+func (f Fields) key(name string) []byte {
+	var v string
+	switch name {
+	case KeyTcid:
+		v = f.Tcid
+	case KeyBizSrvcCd:
+		v = f.BizSrvcCd
+	case KeyApiGroupCd:
+		v = f.ApiGroupCd
+	case KeyApiCd:
+		v = f.ApiCd
+	case KeyGuid:
+		v = f.Guid
+	}
+	if v == "" {
+		return nil // 빈 키 → 파티셔너 라운드로빈
+	}
+	return []byte(v)
+}
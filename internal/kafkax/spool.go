@@ -0,0 +1,461 @@
+package kafkax
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+디스크 스풀 (write-ahead)
+
+WHY:
+- 거래 로그(RasTyp 11/12/21/22)는 감사 대상 → 채널 가득 참/브로커 장애/파드 재기동 시 유실되면 안 됨.
+- Publish 는 로컬 디스크 세그먼트에 먼저 기록하고, 전송 루프가 순서대로 읽어 Kafka 로 보낸 뒤 커서를 전진.
+- 브로커 장애 중에는 디스크에 누적 → 복구되면 기록 순서대로 재전송 (at-least-once, 재기동 직후 중복 가능).
+
+구조:
+- <dir>/<20자리 세그먼트 번호>.seg : [4B 길이][4B CRC32][payload] 레코드 연속
+- <dir>/cursor                       : "<세그먼트 번호> <오프셋>" (전송 완료 위치)
+- 커서 이전 세그먼트는 삭제, 전체 크기가 max_bytes 를 넘으면 신규 메시지 드롭(드롭 카운트 증가)
+
+fsync:
+- always  : 레코드마다 fsync (가장 안전, Publish 지연 증가)
+- interval: fsync_interval 마다 fsync (기본, 장애 시 최대 interval 분량 유실 가능)
+- none    : OS 페이지 캐시에 위임
+*/
+
+// SpoolConfig: 디스크 스풀 설정 (Enabled=false 면 기존 메모리 채널 방식)
+type SpoolConfig struct {
+	Enabled       bool
+	Dir           string
+	SegmentBytes  int64         // 세그먼트 파일 크기 (기본 16MiB)
+	MaxBytes      int64         // 스풀 전체 상한 (기본 1GiB)
+	Fsync         string        // always | interval | none
+	FsyncInterval time.Duration // interval 모드 주기 (기본 1s)
+}
+
+const (
+	spoolHeaderLen = 8
+	spoolMaxRecord = 16 << 20 // 손상된 길이 필드로 과도한 할당 방지
+	segSuffix      = ".seg"
+	cursorFile     = "cursor"
+)
+
+var (
+	errSpoolFull   = errors.New("kafka spool full")
+	errSpoolClosed = errors.New("kafka spool closed")
+	errSpoolEmpty  = errors.New("kafka spool empty")
+)
+
+type spoolPos struct {
+	seg uint64
+	off int64
+}
+
+type spool struct {
+	cfg SpoolConfig
+	log *slog.Logger
+
+	mu      sync.Mutex
+	segs    []uint64         // 오름차순, 마지막이 쓰기 세그먼트
+	sizes   map[uint64]int64 // 세그먼트별 크기
+	total   int64
+	w       *os.File
+	dirty   bool
+	closed  bool
+	notify  chan struct{} // append 시 읽기 측 깨우기 (1 버퍼)
+	stopped chan struct{}
+	wg      sync.WaitGroup
+
+	// 읽기 측: 전송 루프 goroutine 전용
+	r    *os.File
+	rSeg uint64
+	rPos spoolPos
+}
+
+func openSpool(cfg SpoolConfig, logger *slog.Logger) (*spool, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("kafka spool dir empty")
+	}
+	if cfg.SegmentBytes <= 0 {
+		cfg.SegmentBytes = 16 << 20
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = 1 << 30
+	}
+	if cfg.FsyncInterval <= 0 {
+		cfg.FsyncInterval = time.Second
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	s := &spool{
+		cfg:     cfg,
+		log:     logger,
+		sizes:   map[uint64]int64{},
+		notify:  make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+	if err := s.scan(); err != nil {
+		return nil, err
+	}
+	cur := s.readCursor()
+	s.dropBefore(cur.seg)
+	if cur.seg < s.firstSeg() {
+		cur = spoolPos{seg: s.firstSeg()}
+	}
+
+	// 쓰기 세그먼트: 마지막 세그먼트의 깨진 꼬리(비정상 종료) 잘라내고 이어쓰기
+	last := s.segs[len(s.segs)-1]
+	valid, err := validLength(s.segPath(last))
+	if err != nil {
+		return nil, err
+	}
+	if valid < s.sizes[last] {
+		s.log.Warn("truncate torn tail", "seg", last, "from", s.sizes[last], "to", valid)
+		if err := os.Truncate(s.segPath(last), valid); err != nil {
+			return nil, err
+		}
+		s.total -= s.sizes[last] - valid
+		s.sizes[last] = valid
+	}
+	if cur.seg > last || (cur.seg == last && cur.off > valid) {
+		cur = spoolPos{seg: last, off: valid}
+	}
+	s.rPos = cur
+	if s.w, err = os.OpenFile(s.segPath(last), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640); err != nil {
+		return nil, err
+	}
+	spoolBytes.Set(s.total)
+
+	if cfg.Fsync == "interval" || cfg.Fsync == "" {
+		s.wg.Add(1)
+		go s.syncLoop()
+	}
+	s.log.Info("open", "dir", cfg.Dir, "segments", len(s.segs), "bytes", s.total, "cursor_seg", cur.seg, "cursor_off", cur.off)
+	return s, nil
+}
+
+func (s *spool) segPath(id uint64) string {
+	return filepath.Join(s.cfg.Dir, fmt.Sprintf("%020d%s", id, segSuffix))
+}
+
+func (s *spool) firstSeg() uint64 {
+	if len(s.segs) == 0 {
+		return 0
+	}
+	return s.segs[0]
+}
+
+// scan: 세그먼트 목록/크기 적재 (없으면 1번 생성)
+func (s *spool) scan() error {
+	entries, err := os.ReadDir(s.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return err
+		}
+		s.segs = append(s.segs, id)
+		s.sizes[id] = info.Size()
+		s.total += info.Size()
+	}
+	sort.Slice(s.segs, func(i, j int) bool { return s.segs[i] < s.segs[j] })
+	if len(s.segs) == 0 {
+		s.segs = []uint64{1}
+		s.sizes[1] = 0
+	}
+	return nil
+}
+
+func (s *spool) readCursor() spoolPos {
+	raw, err := os.ReadFile(filepath.Join(s.cfg.Dir, cursorFile))
+	if err != nil {
+		return spoolPos{}
+	}
+	var p spoolPos
+	if _, err := fmt.Sscanf(string(raw), "%d %d", &p.seg, &p.off); err != nil {
+		s.log.Warn("invalid cursor, replay from first segment", "err", err)
+		return spoolPos{}
+	}
+	return p
+}
+
+// validLength: 마지막으로 온전한 레코드까지의 길이
+func validLength(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer f.Close()
+	var off int64
+	for {
+		_, n, err := readRecord(f, off)
+		if err != nil {
+			return off, nil
+		}
+		off += n
+	}
+}
+
+// readRecord: off 위치 레코드 → payload, 레코드 전체 길이
+func readRecord(f *os.File, off int64) ([]byte, int64, error) {
+	var hdr [spoolHeaderLen]byte
+	if _, err := f.ReadAt(hdr[:], off); err != nil {
+		return nil, 0, err
+	}
+	n := binary.BigEndian.Uint32(hdr[0:4])
+	if n == 0 || n > spoolMaxRecord {
+		return nil, 0, errCorruptRecord
+	}
+	payload := make([]byte, n)
+	if _, err := f.ReadAt(payload, off+spoolHeaderLen); err != nil {
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(hdr[4:8]) {
+		return nil, 0, errCorruptRecord
+	}
+	return payload, spoolHeaderLen + int64(n), nil
+}
+
+var errCorruptRecord = errors.New("kafka spool corrupt record")
+
+// append: 레코드 기록 (Publish 경로)
+func (s *spool) append(payload []byte) error {
+	rec := make([]byte, spoolHeaderLen+len(payload))
+	binary.BigEndian.PutUint32(rec[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:8], crc32.ChecksumIEEE(payload))
+	copy(rec[spoolHeaderLen:], payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errSpoolClosed
+	}
+	if s.total+int64(len(rec)) > s.cfg.MaxBytes {
+		return errSpoolFull
+	}
+	last := s.segs[len(s.segs)-1]
+	if s.sizes[last] > 0 && s.sizes[last]+int64(len(rec)) > s.cfg.SegmentBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+		last = s.segs[len(s.segs)-1]
+	}
+	if _, err := s.w.Write(rec); err != nil {
+		return err
+	}
+	s.sizes[last] += int64(len(rec))
+	s.total += int64(len(rec))
+	spoolBytes.Set(s.total)
+	if s.cfg.Fsync == "always" {
+		if err := s.w.Sync(); err != nil {
+			return err
+		}
+	} else {
+		s.dirty = true
+	}
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// rotate: 현재 세그먼트 fsync 후 다음 번호로 교체 (mu 보유 상태)
+func (s *spool) rotate() error {
+	if err := s.w.Sync(); err != nil {
+		return err
+	}
+	if err := s.w.Close(); err != nil {
+		return err
+	}
+	next := s.segs[len(s.segs)-1] + 1
+	w, err := os.OpenFile(s.segPath(next), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	s.w = w
+	s.dirty = false
+	s.segs = append(s.segs, next)
+	s.sizes[next] = 0
+	return nil
+}
+
+func (s *spool) syncLoop() {
+	defer s.wg.Done()
+	t := time.NewTicker(s.cfg.FsyncInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			s.mu.Lock()
+			if s.dirty && !s.closed {
+				if err := s.w.Sync(); err != nil {
+					s.log.Error("fsync failed", "err", err)
+				}
+				s.dirty = false
+			}
+			s.mu.Unlock()
+		case <-s.stopped:
+			return
+		}
+	}
+}
+
+// next: 읽기 위치의 다음 레코드 (없으면 append/stop 까지 대기). 반환 pos 는 이 레코드 다음 위치
+func (s *spool) next(stop <-chan struct{}) ([]byte, spoolPos, error) {
+	return s.read(stop, true)
+}
+
+// poll: 대기 없이 다음 레코드 (없으면 errSpoolEmpty)
+func (s *spool) poll() ([]byte, spoolPos, error) {
+	return s.read(nil, false)
+}
+
+func (s *spool) read(stop <-chan struct{}, wait bool) ([]byte, spoolPos, error) {
+	for {
+		if s.r == nil || s.rSeg != s.rPos.seg {
+			if s.r != nil {
+				s.r.Close()
+				s.r = nil
+			}
+			f, err := os.Open(s.segPath(s.rPos.seg))
+			if err != nil && !os.IsNotExist(err) {
+				return nil, s.rPos, err
+			}
+			if err == nil {
+				s.r, s.rSeg = f, s.rPos.seg
+			}
+		}
+
+		if s.r != nil {
+			payload, n, err := readRecord(s.r, s.rPos.off)
+			if err == nil {
+				s.rPos.off += n
+				return payload, s.rPos, nil
+			}
+			if err != io.EOF && err != io.ErrUnexpectedEOF && err != errCorruptRecord {
+				return nil, s.rPos, err
+			}
+			if err == errCorruptRecord && s.sealed(s.rPos.seg) {
+				s.log.Error("corrupt record, skip rest of segment", "seg", s.rPos.seg, "off", s.rPos.off)
+				spoolCorrupt.Add(1)
+			}
+		}
+
+		// 세그먼트 끝: 봉인된 세그먼트면 다음 세그먼트로, 쓰기 세그먼트면 append 대기
+		if nextSeg, ok := s.after(s.rPos.seg); ok {
+			s.rPos = spoolPos{seg: nextSeg}
+			continue
+		}
+		if !wait {
+			return nil, s.rPos, errSpoolEmpty
+		}
+		select {
+		case <-s.notify:
+		case <-stop:
+			return nil, s.rPos, errSpoolClosed
+		}
+	}
+}
+
+// sealed: 쓰기 세그먼트가 아닌지
+func (s *spool) sealed(seg uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return seg != s.segs[len(s.segs)-1]
+}
+
+func (s *spool) after(seg uint64) (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range s.segs {
+		if id > seg {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// commit: 전송 완료 위치 기록 + 지난 세그먼트 삭제
+func (s *spool) commit(pos spoolPos) error {
+	tmp := filepath.Join(s.cfg.Dir, cursorFile+".tmp")
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%d %d", pos.seg, pos.off)), 0o640); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.cfg.Dir, cursorFile)); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.dropBefore(pos.seg)
+	spoolBytes.Set(s.total)
+	s.mu.Unlock()
+	return nil
+}
+
+// dropBefore: seg 이전 세그먼트 파일 삭제 (쓰기 세그먼트는 유지)
+func (s *spool) dropBefore(seg uint64) {
+	keep := s.segs[:0]
+	for i, id := range s.segs {
+		if id < seg && i < len(s.segs)-1 {
+			if err := os.Remove(s.segPath(id)); err != nil && !os.IsNotExist(err) {
+				s.log.Error("remove segment failed", "seg", id, "err", err)
+				keep = append(keep, id)
+				continue
+			}
+			s.total -= s.sizes[id]
+			delete(s.sizes, id)
+			continue
+		}
+		keep = append(keep, id)
+	}
+	s.segs = keep
+}
+
+func (s *spool) close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stopped)
+	err := s.w.Sync()
+	if cerr := s.w.Close(); err == nil {
+		err = cerr
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	if s.r != nil {
+		s.r.Close()
+	}
+	return err
+}
@@ -0,0 +1,496 @@
+package kafkax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func openTestSpool(t *testing.T, cfg SpoolConfig) *spool {
+	t.Helper()
+	if cfg.Fsync == "" {
+		cfg.Fsync = "none"
+	}
+	s, err := openSpool(cfg, discard)
+	if err != nil {
+		t.Fatalf("openSpool: %v", err)
+	}
+	t.Cleanup(func() { s.close() })
+	return s
+}
+
+func record(i int) []byte { return []byte(fmt.Sprintf("record-%02d", i)) } // 9B → 레코드 17B
+
+func appendN(t *testing.T, s *spool, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		if err := s.append(record(i)); err != nil {
+			t.Fatalf("append %d: %v", i, err)
+		}
+	}
+}
+
+// drain: 대기 없이 남은 레코드 전부 (마지막 위치 포함)
+func drain(t *testing.T, s *spool) ([]string, spoolPos) {
+	t.Helper()
+	var out []string
+	var last spoolPos
+	for {
+		payload, pos, err := s.poll()
+		if errors.Is(err, errSpoolEmpty) {
+			return out, last
+		}
+		if err != nil {
+			t.Fatalf("poll: %v", err)
+		}
+		out = append(out, string(payload))
+		last = pos
+	}
+}
+
+func wantRecords(t *testing.T, got []string, from, to int) {
+	t.Helper()
+	if len(got) != to-from {
+		t.Fatalf("got %d records %q, want %d..%d", len(got), got, from, to-1)
+	}
+	for i, g := range got {
+		if want := string(record(from + i)); g != want {
+			t.Fatalf("record[%d] = %q, want %q", i, g, want)
+		}
+	}
+}
+
+func segFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	m, err := filepath.Glob(filepath.Join(dir, "*"+segSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestSpoolSegmentRoll(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestSpool(t, SpoolConfig{Dir: dir, SegmentBytes: 40}) // 세그먼트당 레코드 2개
+	appendN(t, s, 0, 5)
+
+	if n := len(segFiles(t, dir)); n != 3 {
+		t.Fatalf("segments = %d, want 3", n)
+	}
+	if s.total != 5*17 {
+		t.Errorf("total = %d, want %d", s.total, 5*17)
+	}
+	got, last := drain(t, s)
+	wantRecords(t, got, 0, 5)
+	if last != (spoolPos{seg: 3, off: 17}) {
+		t.Errorf("last pos = %+v", last)
+	}
+
+	// 커서 이전 세그먼트만 삭제, 쓰기 세그먼트는 유지
+	if err := s.commit(last); err != nil {
+		t.Fatal(err)
+	}
+	if files := segFiles(t, dir); len(files) != 1 || filepath.Base(files[0]) != fmt.Sprintf("%020d%s", 3, segSuffix) {
+		t.Errorf("segments after commit = %v", files)
+	}
+	if s.total != 17 {
+		t.Errorf("total after commit = %d, want 17", s.total)
+	}
+}
+
+func TestSpoolRecordLargerThanSegment(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestSpool(t, SpoolConfig{Dir: dir, SegmentBytes: 10})
+	appendN(t, s, 0, 2) // 빈 세그먼트에는 크기와 무관하게 기록
+	if n := len(segFiles(t, dir)); n != 2 {
+		t.Fatalf("segments = %d, want 2", n)
+	}
+	got, _ := drain(t, s)
+	wantRecords(t, got, 0, 2)
+}
+
+func TestSpoolFull(t *testing.T) {
+	s := openTestSpool(t, SpoolConfig{Dir: t.TempDir(), SegmentBytes: 40, MaxBytes: 17 * 3})
+	appendN(t, s, 0, 3) // seg1: 0,1  seg2: 2
+	if err := s.append(record(3)); !errors.Is(err, errSpoolFull) {
+		t.Fatalf("append over max = %v, want errSpoolFull", err)
+	}
+	// 전송 완료(커밋)로 봉인 세그먼트가 삭제되면 다시 기록 가능
+	_, last := drain(t, s)
+	if err := s.commit(last); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.append(record(3)); err != nil {
+		t.Fatalf("append after commit = %v", err)
+	}
+	got, _ := drain(t, s)
+	wantRecords(t, got, 3, 4)
+}
+
+func TestSpoolReplayAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	cfg := SpoolConfig{Dir: dir, SegmentBytes: 40, Fsync: "always"}
+
+	s, err := openSpool(cfg, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendN(t, s, 0, 5)
+	var pos spoolPos
+	for range 3 {
+		if _, pos, err = s.poll(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.commit(pos); err != nil {
+		t.Fatal(err)
+	}
+	// 커밋 이후 읽었지만 전송 완료 전 종료된 레코드
+	if _, _, err := s.poll(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openTestSpool(t, cfg)
+	got, _ := drain(t, s)
+	wantRecords(t, got, 3, 5)
+
+	// 재기동 후 이어쓰기
+	appendN(t, s, 5, 7)
+	got, _ = drain(t, s)
+	wantRecords(t, got, 5, 7)
+}
+
+func TestSpoolReplayWithoutCursor(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(SpoolConfig{Dir: dir, Fsync: "none"}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendN(t, s, 0, 3)
+	drain(t, s) // 읽기만 하고 커밋 없음
+	s.close()
+
+	// 커서 파일이 깨져도 처음부터 재전송
+	if err := os.WriteFile(filepath.Join(dir, cursorFile), []byte("garbage"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	s = openTestSpool(t, SpoolConfig{Dir: dir})
+	got, _ := drain(t, s)
+	wantRecords(t, got, 0, 3)
+}
+
+func TestSpoolTornTail(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(SpoolConfig{Dir: dir, Fsync: "none"}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendN(t, s, 0, 3)
+	s.close()
+
+	// 비정상 종료: 헤더는 100B 라고 쓰고 본문 일부만 기록
+	path := filepath.Join(dir, fmt.Sprintf("%020d%s", 1, segSuffix))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 100, 1, 2, 3, 4, 'p', 'a', 'r', 't'})
+	f.Close()
+
+	s = openTestSpool(t, SpoolConfig{Dir: dir})
+	if info, _ := os.Stat(path); info.Size() != 3*17 {
+		t.Errorf("size after open = %d, want truncated to %d", info.Size(), 3*17)
+	}
+	if s.total != 3*17 {
+		t.Errorf("total = %d", s.total)
+	}
+	appendN(t, s, 3, 4) // 잘라낸 위치부터 이어쓰기 → 새 레코드가 깨진 꼬리 뒤에 묻히지 않음
+	got, _ := drain(t, s)
+	wantRecords(t, got, 0, 4)
+}
+
+func TestSpoolCRCMismatchInWriteSegment(t *testing.T) {
+	dir := t.TempDir()
+	s, err := openSpool(SpoolConfig{Dir: dir, Fsync: "none"}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendN(t, s, 0, 3)
+	s.close()
+
+	// 두 번째 레코드 본문 1바이트 변조 → 그 위치부터 깨진 꼬리로 취급
+	path := filepath.Join(dir, fmt.Sprintf("%020d%s", 1, segSuffix))
+	flipByte(t, path, 17+spoolHeaderLen)
+
+	s = openTestSpool(t, SpoolConfig{Dir: dir})
+	got, _ := drain(t, s)
+	wantRecords(t, got, 0, 1)
+	if info, _ := os.Stat(path); info.Size() != 17 {
+		t.Errorf("size = %d, want truncated to 17", info.Size())
+	}
+}
+
+func TestSpoolCRCMismatchInSealedSegment(t *testing.T) {
+	dir := t.TempDir()
+	s := openTestSpool(t, SpoolConfig{Dir: dir, SegmentBytes: 40})
+	appendN(t, s, 0, 5) // seg1: 0,1  seg2: 2,3  seg3: 4
+
+	// 봉인된 seg1 의 두 번째 레코드 손상 → 세그먼트 나머지를 건너뛰고 다음 세그먼트부터 계속
+	flipByte(t, filepath.Join(dir, fmt.Sprintf("%020d%s", 1, segSuffix)), 17+spoolHeaderLen+2)
+
+	before := spoolCorrupt.Value()
+	got, _ := drain(t, s)
+	if want := []string{"record-00", "record-02", "record-03", "record-04"}; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if spoolCorrupt.Value() != before+1 {
+		t.Errorf("spool_corrupt = %d, want %d", spoolCorrupt.Value(), before+1)
+	}
+}
+
+func TestSpoolNextWaitsForAppend(t *testing.T) {
+	s := openTestSpool(t, SpoolConfig{Dir: t.TempDir()})
+	stop := make(chan struct{})
+	type res struct {
+		payload []byte
+		err     error
+	}
+	done := make(chan res, 1)
+	go func() {
+		p, _, err := s.next(stop)
+		done <- res{p, err}
+	}()
+
+	select {
+	case r := <-done:
+		t.Fatalf("next returned before append: %q %v", r.payload, r.err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	appendN(t, s, 0, 1)
+	select {
+	case r := <-done:
+		if r.err != nil || string(r.payload) != "record-00" {
+			t.Fatalf("next = %q %v", r.payload, r.err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("next did not wake on append")
+	}
+
+	go func() {
+		_, _, err := s.next(stop)
+		done <- res{nil, err}
+	}()
+	close(stop)
+	if r := <-done; !errors.Is(r.err, errSpoolClosed) {
+		t.Fatalf("next after stop = %v", r.err)
+	}
+}
+
+func flipByte(t *testing.T, path string, off int64) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b := make([]byte, 1)
+	if _, err := f.ReadAt(b, off); err != nil {
+		t.Fatal(err)
+	}
+	b[0] ^= 0xff
+	if _, err := f.WriteAt(b, off); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeWriter: 호출마다 calls 로 배치를 넘기고 results 에서 결과를 받음
+type fakeWriter struct {
+	calls   chan []kafka.Message
+	results chan error
+}
+
+func newFakeWriter() *fakeWriter {
+	return &fakeWriter{calls: make(chan []kafka.Message), results: make(chan error)}
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	select {
+	case w.calls <- msgs:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-w.results:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *fakeWriter) Close() error { return nil }
+
+// newSpoolPublisher: NewPublisher 의 스풀 모드와 같은 구성 (writer 만 교체), 전송 루프는 startLoop 로 시작
+func newSpoolPublisher(t *testing.T, dir string, w messageWriter) *publisher {
+	t.Helper()
+	p := &publisher{
+		cfg:    Config{Topic: "audit", BatchSize: 10, Timeout: 5 * time.Second, DrainTimeout: 200 * time.Millisecond},
+		w:      w,
+		closed: make(chan struct{}),
+		log:    discard,
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	sp, err := openSpool(SpoolConfig{Dir: dir, Fsync: "always"}, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.sp = sp
+	return p
+}
+
+func startLoop(p *publisher) {
+	p.wg.Add(1)
+	go p.spoolLoop()
+}
+
+func readCursorFile(t *testing.T, dir string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, cursorFile))
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestSpoolCursorCommittedOnlyAfterSend(t *testing.T) {
+	dir := t.TempDir()
+	w := newFakeWriter()
+	p := newSpoolPublisher(t, dir, w)
+	for i := range 3 {
+		p.Publish(Message{Key: []byte("k"), Value: record(i)})
+	}
+	startLoop(p)
+
+	// 전송 중 / 실패 후에는 커서 없음
+	batch := <-w.calls
+	if len(batch) != 3 {
+		t.Fatalf("batch = %d messages, want 3", len(batch))
+	}
+	if got := readCursorFile(t, dir); got != "" {
+		t.Fatalf("cursor %q written before send completed", got)
+	}
+	w.results <- errors.New("broker down")
+
+	batch = <-w.calls // 백오프 후 같은 배치 재전송
+	if got := readCursorFile(t, dir); got != "" {
+		t.Fatalf("cursor %q advanced after failed send", got)
+	}
+	for i, m := range batch {
+		if string(m.Value) != string(record(i)) || m.Topic != "audit" {
+			t.Fatalf("retry batch[%d] = %s %q", i, m.Topic, m.Value)
+		}
+	}
+	w.results <- nil
+
+	// 성공 후 커서 전진 (3 레코드 × (8B 헤더 + payload))
+	want := fmt.Sprintf("1 %d", 3*(spoolHeaderLen+len(encodeMessage(Message{Key: []byte("k"), Value: record(0)}))))
+	deadline := time.Now().Add(2 * time.Second)
+	for readCursorFile(t, dir) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("cursor = %q, want %q", readCursorFile(t, dir), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 재기동: 전송 완료분은 재전송하지 않음
+	s := openTestSpool(t, SpoolConfig{Dir: dir})
+	if got, _ := drain(t, s); len(got) != 0 {
+		t.Fatalf("replayed %d sent records", len(got))
+	}
+}
+
+func TestSpoolUnsentReplayedAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	w := newFakeWriter()
+	p := newSpoolPublisher(t, dir, w)
+	startLoop(p)
+
+	p.Publish(Message{Key: []byte("k"), Value: record(0)})
+	<-w.calls
+	w.results <- errors.New("broker down")
+
+	// 백오프 중 종료 → 커서 미전진
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readCursorFile(t, dir); got != "" {
+		t.Fatalf("cursor = %q after failed send", got)
+	}
+
+	s := openTestSpool(t, SpoolConfig{Dir: dir})
+	payload, _, err := s.poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := decodeMessage(payload)
+	if err != nil || string(m.Value) != string(record(0)) || string(m.Key) != "k" {
+		t.Fatalf("replayed = %+v %v", m, err)
+	}
+}
+
+// levelCounter: 레벨별 로그 건수 집계
+type levelCounter struct {
+	slog.Handler
+	errors int
+}
+
+func (h *levelCounter) Handle(_ context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelError {
+		h.errors++
+	}
+	return nil
+}
+
+// Close 이후 늦게 도착한 이벤트는 닫힌 스풀에 쓰지 않고 드롭 집계만 (건별 에러 로그 없음)
+func TestSpoolPublishAfterClose(t *testing.T) {
+	dir := t.TempDir()
+	p := newSpoolPublisher(t, dir, newFakeWriter())
+	h := &levelCounter{Handler: discard.Handler()}
+	p.log, p.sp.log = slog.New(h), slog.New(h)
+	startLoop(p)
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	before, spooled := droppedCount.Value(), spooledCount.Value()
+	for i := range 5 {
+		p.Publish(Message{Key: []byte("k"), Value: record(i)})
+	}
+	if got := droppedCount.Value() - before; got != 5 {
+		t.Errorf("dropped = %d, want 5", got)
+	}
+	if spooledCount.Value() != spooled {
+		t.Error("late publish appended to closed spool")
+	}
+	if h.errors != 0 {
+		t.Errorf("error logs = %d, want 0", h.errors)
+	}
+}
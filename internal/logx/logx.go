@@ -0,0 +1,195 @@
+package logx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"service-gateway/internal/header"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+/*
+구조화 로그 (log/slog)
+
+WHY:
+- 패키지마다 log.Printf 로 레벨 없이 찍혀 운영 중 디버그 덤프를 끌 수도, 요청 단위로 모아 볼 수도 없었음.
+- main 에서 Logger 하나를 만들어 핸들러/프록시/스토어/kafkax 에 주입, slog.SetDefault 로 나머지 패키지도 동일 출력.
+- 모든 줄에 tcid / route / trace_id 자동 포함 (요청 컨텍스트 기준, *Context 메서드 사용 시).
+- 레벨은 LevelVar 라 관리 API(PUT /admin/v1/log-level) 로 재기동 없이 변경.
+*/
+
+// Config: gateway.yaml logging 블록
+type Config struct {
+	Level     string // debug | info | warn | error (기본 info)
+	Format    string // json | text (기본 json)
+	AddSource bool
+}
+
+// New: Logger + 런타임 변경용 LevelVar
+func New(cfg Config, w io.Writer) (*slog.Logger, *slog.LevelVar, error) {
+	lv := new(slog.LevelVar)
+	if cfg.Level != "" {
+		lvl, err := ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		lv.Set(lvl)
+	}
+	opts := &slog.HandlerOptions{Level: lv, AddSource: cfg.AddSource}
+	var h slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, nil, fmt.Errorf("logging.format: unknown %q (json|text)", cfg.Format)
+	}
+	return slog.New(&contextHandler{Handler: h}), lv, nil
+}
+
+// ParseLevel: "debug" / "INFO" / "warn" / "error" (slog 표기 "INFO+2" 등도 허용)
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("logging.level: unknown %q (debug|info|warn|error)", s)
+	}
+	return l, nil
+}
+
+// ==== 요청 컨텍스트 속성 ====
+
+type fieldsKey struct{}
+
+// fields: 요청 처리 중 채워지는 값 (라우트/업스트림은 매칭 이후에 확정되므로 포인터로 공유)
+// 비동기 ACK 라우트는 응답 이후 백그라운드에서 갱신하므로 잠금 필요
+type fields struct {
+	mu       sync.Mutex
+	tcid     string
+	route    string
+	upstream string
+	clientIP string
+}
+
+// Values: 요청 컨텍스트에 기록된 값 (access log 용)
+type Values struct {
+	TCID     string
+	Route    string
+	Upstream string
+	ClientIP string // 신뢰 프록시 체인 기준 실제 클라이언트 IP (middleware.ProxyHeaders)
+}
+
+func (f *fields) set(fn func(f *fields)) {
+	f.mu.Lock()
+	fn(f)
+	f.mu.Unlock()
+}
+
+func (f *fields) values() Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return Values{TCID: f.tcid, Route: f.route, Upstream: f.upstream, ClientIP: f.clientIP}
+}
+
+func from(ctx context.Context) *fields {
+	f, _ := ctx.Value(fieldsKey{}).(*fields)
+	return f
+}
+
+// WithTCID: 요청 컨텍스트에 TCID 기록 (이후 SetRoute 로 라우트 추가)
+func WithTCID(ctx context.Context, tcid string) context.Context {
+	if f := from(ctx); f != nil {
+		f.set(func(f *fields) { f.tcid = tcid })
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey{}, &fields{tcid: tcid})
+}
+
+// SetTCID: 처리 도중 TCID 가 바뀌는 경우 (동적 게이트웨이의 서버 기준 TCID)
+func SetTCID(ctx context.Context, tcid string) {
+	if f := from(ctx); f != nil {
+		f.set(func(f *fields) { f.tcid = tcid })
+	}
+}
+
+// SetRoute: 라우트 매칭 후 이름 기록 (WithTCID/Middleware 를 거친 컨텍스트에서만 유효)
+func SetRoute(ctx context.Context, route string) {
+	if f := from(ctx); f != nil {
+		f.set(func(f *fields) { f.route = route })
+	}
+}
+
+// SetUpstream: 프록시 대상 호스트 기록
+func SetUpstream(ctx context.Context, host string) {
+	if f := from(ctx); f != nil {
+		f.set(func(f *fields) { f.upstream = host })
+	}
+}
+
+// SetClientIP: 실제 클라이언트 IP 기록
+func SetClientIP(ctx context.Context, ip string) {
+	if f := from(ctx); f != nil {
+		f.set(func(f *fields) { f.clientIP = ip })
+	}
+}
+
+// FromContext: 기록된 값 조회 (Middleware 밖이면 빈 값)
+func FromContext(ctx context.Context) Values {
+	if f := from(ctx); f != nil {
+		return f.values()
+	}
+	return Values{}
+}
+
+// Middleware: 요청마다 로그 필드(공유 *fields)를 만들고 클라이언트가 보낸 X-Fw-Header TCID 로 시작
+// main 에서는 가장 바깥(access log / FwHeaderTrace 보다 먼저)에 위치 → 이 시점 TCID 는 클라이언트 값(없으면 빈 값).
+// 안쪽에서 WithTCID(FwHeaderTrace 발급) / SetTCID(동적 게이트웨이 서버 기준) 로 바꾸면 같은 *fields 를 갱신하므로
+// 바깥의 access log 도 최종 TCID 로 기록됨 (새 컨텍스트를 만들지 않고 필드를 변경하는 것이 전제)
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tcid := header.Parse(r.Header.Get("X-Fw-Header"))["TCID"]
+		next.ServeHTTP(w, r.WithContext(WithTCID(r.Context(), tcid)))
+	})
+}
+
+// contextHandler: 레코드에 tcid / route / trace_id 추가
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if ctx != nil {
+		v := FromContext(ctx)
+		if v.TCID != "" {
+			rec.AddAttrs(slog.String("tcid", v.TCID))
+		}
+		if v.Route != "" {
+			rec.AddAttrs(slog.String("route", v.Route))
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			rec.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, rec)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+// Or: 주입되지 않은(nil) Logger 는 slog.Default
+func Or(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return l
+}
@@ -0,0 +1,98 @@
+package maintenance
+
+import (
+	"context"
+	"log/slog"
+	"service-gateway/internal/model"
+	"sync"
+	"time"
+)
+
+/*
+점검 시간대 체크
+
+WHY:
+- 기존 API/그룹 CLOT 코드 08 은 구간 하나만 표현 가능 → 정기 점검(매주 일요일 02~04시 등), 다중 구간,
+  타임존, 안내 메시지, Retry-After 를 별도 테이블(SID_API_MNT_WIN)로 관리.
+- 요청마다 DB 조회하지 않도록 ttl 동안 메모리 캐시, 관리 API 변경 시 Invalidate() 로 즉시 재적재.
+- 적재 실패 시 이전 목록 유지 (점검 조회 장애로 전체 거래가 막히지 않도록).
+*/
+
+// Source: 점검 시간대 조회 (store.Repository 가 구현)
+type Source interface {
+	ListMaintenanceWindows(ctx context.Context) ([]model.MaintenanceWindow, error)
+}
+
+type Checker struct {
+	src Source
+	ttl time.Duration
+
+	mu       sync.RWMutex
+	windows  []*window
+	loadedAt time.Time
+}
+
+func NewChecker(src Source, ttl time.Duration) *Checker {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Checker{src: src, ttl: ttl}
+}
+
+// Check: 차단 대상이면 ControlError, 아니면 nil
+func (c *Checker) Check(ctx context.Context, now time.Time, groupCd, apiCd string) *model.ControlError {
+	if c == nil {
+		return nil
+	}
+	for _, w := range c.load(ctx) {
+		if !w.appliesTo(groupCd, apiCd) {
+			continue
+		}
+		if until, ok := w.activeUntil(now); ok {
+			return w.controlError(now, until)
+		}
+	}
+	return nil
+}
+
+// Invalidate: 다음 Check 에서 DB 재조회 (admin.Invalidator)
+func (c *Checker) Invalidate() {
+	c.mu.Lock()
+	c.loadedAt = time.Time{}
+	c.mu.Unlock()
+}
+
+func (c *Checker) load(ctx context.Context) []*window {
+	c.mu.RLock()
+	if time.Since(c.loadedAt) < c.ttl {
+		ws := c.windows
+		c.mu.RUnlock()
+		return ws
+	}
+	c.mu.RUnlock()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Since(c.loadedAt) < c.ttl { // 다른 goroutine 이 먼저 적재
+		return c.windows
+	}
+
+	rows, err := c.src.ListMaintenanceWindows(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "load windows failed, keep previous", "component", "maintenance", "err", err)
+		c.loadedAt = time.Now()
+		return c.windows
+	}
+	ws := make([]*window, 0, len(rows))
+	for _, r := range rows {
+		w, err := compile(r)
+		if err != nil {
+			slog.WarnContext(ctx, "skip window", "component", "maintenance", "win_id", r.ID, "err", err)
+			continue
+		}
+		ws = append(ws, w)
+	}
+	c.windows = ws
+	c.loadedAt = time.Now()
+	return ws
+}
@@ -0,0 +1,188 @@
+package maintenance
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule: 5필드 cron ("분 시 일 월 요일") 비트셋
+// 지원: *, a-b, a,b,c, */n, a-b/n  (요일 0,7 = 일요일)
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type fieldSpec struct {
+	name     string
+	min, max int
+}
+
+var fields = []fieldSpec{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+func ParseCron(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(parts))
+	}
+	var bits [5]uint64
+	for i, p := range parts {
+		b, err := parseField(p, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+	// 요일 7 → 0 (일요일)
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = (bits[4] | 1) &^ (1 << 7)
+	}
+	return &Schedule{
+		minute: bits[0], hour: bits[1], dom: bits[2], month: bits[3], dow: bits[4],
+		domStar: parts[2] == "*", dowStar: parts[4] == "*",
+	}, nil
+}
+
+func parseField(s string, f fieldSpec) (uint64, error) {
+	var out uint64
+	for _, item := range strings.Split(s, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, stepStr)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(a)
+			hi, err2 = strconv.Atoi(b)
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("%s: invalid range %q", f.name, rng)
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return 0, fmt.Errorf("%s: invalid value %q", f.name, rng)
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%s: %q out of range %d-%d", f.name, item, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			out |= 1 << uint(v)
+		}
+	}
+	return out, nil
+}
+
+// Match: t(분 단위) 가 스케줄 발생 시각인지
+// 일/요일 둘 다 지정된 경우 표준 cron 과 같이 OR 조건
+func (s *Schedule) Match(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 || s.hour&(1<<uint(t.Hour())) == 0 {
+		return false
+	}
+	return s.dayMatch(t)
+}
+
+// dayMatch: 월 / 일 / 요일 조건 (시각 무관)
+func (s *Schedule) dayMatch(t time.Time) bool {
+	if s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+	domOK := s.dom&(1<<uint(t.Day())) != 0
+	dowOK := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domStar && s.dowStar:
+		return true
+	case s.domStar:
+		return dowOK
+	case s.dowStar:
+		return domOK
+	default:
+		return domOK || dowOK
+	}
+}
+
+// Prev: t 이전(포함) 가장 최근 발생 시각. within 범위 안에 없으면 false
+// 1분씩 거슬러 Match 하지 않고 날짜 → 시 → 분 비트셋에 설정된 값만 큰 값부터 확인
+// (/gateway 요청마다 점검 규칙별로 호출되므로 7일 범위에서도 날짜 수 × 설정 비트 수 만큼만 계산)
+func (s *Schedule) Prev(t time.Time, within time.Duration) (time.Time, bool) {
+	limit := t.Add(-within)
+	loc := t.Location()
+	y, mo, d := t.Date()
+	for day := 0; ; day++ {
+		// 달력 날짜는 UTC 정오로 계산 (DST 전환일에도 날짜가 밀리지 않음)
+		cal := time.Date(y, mo, d-day, 12, 0, 0, 0, time.UTC)
+		if next := time.Date(y, mo, d-day+1, 0, 0, 0, 0, loc); next.Add(time.Hour).Before(limit) {
+			return time.Time{}, false
+		}
+		if !s.dayMatch(cal) {
+			continue
+		}
+		hours := s.hour
+		if day == 0 {
+			hours &= 1<<uint(t.Hour()+1) - 1
+		}
+		for hours != 0 {
+			h := bits.Len64(hours) - 1
+			hours &^= 1 << uint(h)
+			// 가을 전환으로 같은 시(hour)가 두 번 있으면 분 내림차순이 실제 시각 순서와 달라 시 단위로 최댓값 선택
+			var best time.Time
+			past := false
+			for mins := s.minute; mins != 0; {
+				m := bits.Len64(mins) - 1
+				mins &^= 1 << uint(m)
+				at, ok := wallTime(cal, h, m, loc, t)
+				switch {
+				case !ok:
+				case at.Before(limit):
+					past = past || at.Add(time.Hour).Before(limit) // DST 로 최대 한 시간 어긋날 수 있어 여유
+				case at.After(best):
+					best = at
+				}
+			}
+			if !best.IsZero() {
+				return best, true
+			}
+			if past {
+				return time.Time{}, false
+			}
+		}
+	}
+}
+
+// wallTime: cal 날짜의 h:m (loc 벽시계) 중 t 이전인 가장 늦은 시각
+// DST 로 없는 시각(봄 전환)은 false, 두 번 있는 시각(가을 전환, 30분 / 1시간)은 나중 쪽 우선
+func wallTime(cal time.Time, h, m int, loc *time.Location, t time.Time) (time.Time, bool) {
+	y, mo, d := cal.Date()
+	at := time.Date(y, mo, d, h, m, 0, 0, loc)
+	for _, shift := range [...]time.Duration{time.Hour, 30 * time.Minute, 0, -30 * time.Minute, -time.Hour} {
+		c := at.Add(shift)
+		cy, cmo, cd := c.Date()
+		if c.After(t) || cy != y || cmo != mo || cd != d || c.Hour() != h || c.Minute() != m {
+			continue
+		}
+		return c, true
+	}
+	return time.Time{}, false
+}
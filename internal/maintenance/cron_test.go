@@ -0,0 +1,245 @@
+package maintenance
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func mustCron(t *testing.T, expr string) *Schedule {
+	t.Helper()
+	s, err := ParseCron(expr)
+	if err != nil {
+		t.Fatalf("ParseCron(%q): %v", expr, err)
+	}
+	return s
+}
+
+func mustLoc(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q): %v", name, err)
+	}
+	return loc
+}
+
+// bitsOf: 비트셋 → 설정된 값 목록
+func bitsOf(b uint64) []int {
+	var out []int
+	for v := 0; v < 64; v++ {
+		if b&(1<<uint(v)) != 0 {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseCronFields(t *testing.T) {
+	cases := []struct {
+		expr  string
+		field func(*Schedule) uint64
+		want  []int
+	}{
+		{"0 * * * *", func(s *Schedule) uint64 { return s.minute }, []int{0}},
+		{"0-3 * * * *", func(s *Schedule) uint64 { return s.minute }, []int{0, 1, 2, 3}},
+		{"*/15 * * * *", func(s *Schedule) uint64 { return s.minute }, []int{0, 15, 30, 45}},
+		{"10-30/10 * * * *", func(s *Schedule) uint64 { return s.minute }, []int{10, 20, 30}},
+		{"5/20 * * * *", func(s *Schedule) uint64 { return s.minute }, []int{5, 25, 45}},
+		{"1,2,59 * * * *", func(s *Schedule) uint64 { return s.minute }, []int{1, 2, 59}},
+		{"0 22-23,0-1 * * *", func(s *Schedule) uint64 { return s.hour }, []int{0, 1, 22, 23}},
+		{"0 0 1,15,31 * *", func(s *Schedule) uint64 { return s.dom }, []int{1, 15, 31}},
+		{"0 0 * */3 *", func(s *Schedule) uint64 { return s.month }, []int{1, 4, 7, 10}},
+		{"0 0 * * 1-5", func(s *Schedule) uint64 { return s.dow }, []int{1, 2, 3, 4, 5}},
+		{"0 0 * * 7", func(s *Schedule) uint64 { return s.dow }, []int{0}},
+		{"0 0 * * 0,7", func(s *Schedule) uint64 { return s.dow }, []int{0}},
+		{"0 0 * * 5-7", func(s *Schedule) uint64 { return s.dow }, []int{0, 5, 6}},
+	}
+	for _, c := range cases {
+		got := bitsOf(c.field(mustCron(t, c.expr)))
+		if !equalInts(got, c.want) {
+			t.Errorf("%q: got %v, want %v", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"a * * * *",
+		"1-x * * * *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q): expected error", expr)
+		}
+	}
+}
+
+func TestMatchDayOfMonthAndWeek(t *testing.T) {
+	// 2025-06-13 금요일, 2025-06-15 일요일, 2025-06-16 월요일
+	fri13 := time.Date(2025, 6, 13, 0, 0, 0, 0, time.UTC)
+	sun15 := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+	mon16 := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		expr string
+		at   time.Time
+		want bool
+	}{
+		{"0 0 * * *", fri13, true},
+		{"0 0 13 * *", fri13, true}, // 일만 지정
+		{"0 0 13 * *", sun15, false},
+		{"0 0 * * 0", sun15, true}, // 요일만 지정
+		{"0 0 * * 0", mon16, false},
+		{"0 0 1 * 5", fri13, true},  // 일 + 요일: OR (금요일)
+		{"0 0 15 * 1", sun15, true}, // OR (15일)
+		{"0 0 1 * 5", mon16, false}, // 둘 다 아님
+		{"0 0 * 7 *", fri13, false}, // 월 불일치
+		{"0 1 * * *", fri13, false}, // 시 불일치
+		{"1 0 * * *", fri13, false}, // 분 불일치
+	}
+	for _, c := range cases {
+		if got := mustCron(t, c.expr).Match(c.at); got != c.want {
+			t.Errorf("%q Match(%s) = %v, want %v", c.expr, c.at.Format(time.DateTime), got, c.want)
+		}
+	}
+}
+
+// prevByMinute: 1분씩 거슬러 올라가는 기준 구현 (Prev 결과 비교용)
+func prevByMinute(s *Schedule, t time.Time, within time.Duration) (time.Time, bool) {
+	limit := t.Add(-within)
+	for cur := t.Truncate(time.Minute); !cur.Before(limit); cur = cur.Add(-time.Minute) {
+		if s.Match(cur) {
+			return cur, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func TestPrev(t *testing.T) {
+	seoul := mustLoc(t, "Asia/Seoul")
+	cases := []struct {
+		expr   string
+		at     time.Time
+		within time.Duration
+		want   time.Time // zero 면 없음
+	}{
+		{"30 2 * * *", time.Date(2025, 6, 13, 2, 30, 59, 0, seoul), time.Hour, time.Date(2025, 6, 13, 2, 30, 0, 0, seoul)},
+		{"30 2 * * *", time.Date(2025, 6, 13, 3, 29, 0, 0, seoul), time.Hour, time.Date(2025, 6, 13, 2, 30, 0, 0, seoul)},
+		{"30 2 * * *", time.Date(2025, 6, 13, 3, 31, 0, 0, seoul), time.Hour, time.Time{}},
+		{"30 2 * * *", time.Date(2025, 6, 13, 2, 29, 0, 0, seoul), 24 * time.Hour, time.Date(2025, 6, 12, 2, 30, 0, 0, seoul)},
+		{"0 23 * * 0", time.Date(2025, 6, 16, 1, 0, 0, 0, seoul), 7 * 24 * time.Hour, time.Date(2025, 6, 15, 23, 0, 0, 0, seoul)},
+		{"0 0 1 * *", time.Date(2025, 3, 1, 0, 0, 0, 0, seoul), 0, time.Date(2025, 3, 1, 0, 0, 0, 0, seoul)},
+		{"0 0 31 * *", time.Date(2025, 3, 2, 0, 0, 0, 0, seoul), 7 * 24 * time.Hour, time.Time{}}, // 2월 31일 없음
+		{"59 23 31 12 *", time.Date(2026, 1, 1, 0, 0, 0, 0, seoul), time.Hour, time.Date(2025, 12, 31, 23, 59, 0, 0, seoul)},
+	}
+	for _, c := range cases {
+		got, ok := mustCron(t, c.expr).Prev(c.at, c.within)
+		if ok != !c.want.IsZero() || !got.Equal(c.want) {
+			t.Errorf("%q Prev(%s, %s) = %s %v, want %s", c.expr, c.at.Format(time.DateTime), c.within, got, ok, c.want)
+		}
+	}
+}
+
+func TestPrevDST(t *testing.T) {
+	ny := mustLoc(t, "America/New_York")
+
+	// 봄 전환 (2025-03-09 02:00 EST → 03:00 EDT): 02:30 은 없는 시각 → 건너뜀
+	s := mustCron(t, "30 2 * * *")
+	got, ok := s.Prev(time.Date(2025, 3, 9, 4, 0, 0, 0, ny), 30*time.Hour)
+	if want := time.Date(2025, 3, 8, 2, 30, 0, 0, ny); !ok || !got.Equal(want) {
+		t.Errorf("spring forward: got %s %v, want %s", got, ok, want)
+	}
+
+	// 가을 전환 (2025-11-02 02:00 EDT → 01:00 EST): 01:30 은 두 번 → t 이전인 나중 쪽
+	s = mustCron(t, "30 1 * * *")
+	second := time.Date(2025, 11, 2, 6, 30, 0, 0, time.UTC) // 01:30 EST
+	at := time.Date(2025, 11, 2, 6, 45, 0, 0, time.UTC)     // 01:45 EST
+	got, ok = s.Prev(at.In(ny), time.Hour)
+	if !ok || !got.Equal(second) {
+		t.Errorf("fall back (second): got %s %v, want %s", got, ok, second)
+	}
+	// 두 번째 01:30 이전 (01:10 EST) → 첫 번째 01:30 EDT
+	first := time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC)
+	got, ok = s.Prev(time.Date(2025, 11, 2, 6, 10, 0, 0, time.UTC).In(ny), time.Hour)
+	if !ok || !got.Equal(first) {
+		t.Errorf("fall back (first): got %s %v, want %s", got, ok, first)
+	}
+}
+
+// Prev 는 1분 단위 탐색과 같은 결과여야 함 (DST 전환일 포함)
+func TestPrevMatchesMinuteWalk(t *testing.T) {
+	exprs := []string{
+		"* * * * *",
+		"0 0 * * *",
+		"*/7 */5 * * *",
+		"15,45 1-3 * * *",
+		"30 1 * * *",
+		"30 2 * * *",
+		"0 0 1,15 * 1",
+		"59 23 * * 6",
+		"0 12 29 2 *",
+		"10-50/20 0,12 * 3,11 0-2",
+	}
+	durations := []time.Duration{0, time.Minute, 90 * time.Minute, 25 * time.Hour, 7 * 24 * time.Hour}
+	locs := []*time.Location{mustLoc(t, "Asia/Seoul"), mustLoc(t, "America/New_York"), mustLoc(t, "Europe/London"), mustLoc(t, "Australia/Lord_Howe")}
+	anchors := []time.Time{
+		time.Date(2025, 3, 9, 7, 0, 0, 0, time.UTC),   // 미국 봄 전환
+		time.Date(2025, 3, 30, 1, 0, 0, 0, time.UTC),  // 유럽 봄 전환
+		time.Date(2025, 4, 5, 15, 0, 0, 0, time.UTC),  // 로드하우 30분 전환
+		time.Date(2025, 10, 26, 1, 0, 0, 0, time.UTC), // 유럽 가을 전환
+		time.Date(2025, 11, 2, 6, 0, 0, 0, time.UTC),  // 미국 가을 전환
+		time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), // 윤일
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, expr := range exprs {
+		s := mustCron(t, expr)
+		for _, loc := range locs {
+			for _, anchor := range anchors {
+				for range 12 {
+					at := anchor.Add(time.Duration(rng.IntN(6*60*60)-3*60*60) * time.Second).In(loc)
+					for _, within := range durations {
+						want, wok := prevByMinute(s, at, within)
+						got, gok := s.Prev(at, within)
+						if wok != gok || !got.Equal(want) {
+							t.Fatalf("%q Prev(%s, %s) = %s %v, want %s %v", expr, at.Format(time.RFC3339), within, got, gok, want, wok)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkPrevWeekly(b *testing.B) {
+	s, _ := ParseCron("0 3 * * 0")
+	at := time.Date(2025, 6, 14, 2, 59, 0, 0, time.UTC) // 직전 발생 약 7일 전
+	for b.Loop() {
+		s.Prev(at, 7*24*time.Hour)
+	}
+}
@@ -0,0 +1,120 @@
+package maintenance
+
+import (
+	"fmt"
+	"service-gateway/internal/model"
+	"time"
+)
+
+const (
+	timLayout  = "20060102150405"
+	defaultTZ  = "Asia/Seoul"
+	maxRecurse = 7 * 24 * time.Hour // 반복 점검 1회 최대 길이
+)
+
+// window: DB 행을 평가 가능한 형태로 변환한 것
+type window struct {
+	src      model.MaintenanceWindow
+	loc      *time.Location
+	start    time.Time // zero 면 제한 없음
+	end      time.Time
+	schedule *Schedule
+	duration time.Duration
+}
+
+// Validate: 관리 API 저장 전 검증 (compile 과 동일 규칙)
+func Validate(w model.MaintenanceWindow) error {
+	_, err := compile(w)
+	return err
+}
+
+func compile(w model.MaintenanceWindow) (*window, error) {
+	tz := w.TimeZone
+	if tz == "" {
+		tz = defaultTZ
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("tz: %w", err)
+	}
+	if w.ControlCode == "" {
+		w.ControlCode = "08"
+	}
+	if _, ok := model.ErrorCodeMap[w.ControlCode]; !ok || w.ControlCode == "00" {
+		return nil, fmt.Errorf("clotCtlCd: invalid control code %q", w.ControlCode)
+	}
+
+	out := &window{src: w, loc: loc}
+	if w.StartTim != "" {
+		if out.start, err = time.ParseInLocation(timLayout, w.StartTim, loc); err != nil {
+			return nil, fmt.Errorf("staTim: must be yyyyMMddHHmmss")
+		}
+	}
+	if w.EndTim != "" {
+		if out.end, err = time.ParseInLocation(timLayout, w.EndTim, loc); err != nil {
+			return nil, fmt.Errorf("endTim: must be yyyyMMddHHmmss")
+		}
+	}
+	if !out.start.IsZero() && !out.end.IsZero() && !out.start.Before(out.end) {
+		return nil, fmt.Errorf("endTim: must be after staTim")
+	}
+
+	if w.Cron == "" {
+		if out.start.IsZero() || out.end.IsZero() {
+			return nil, fmt.Errorf("staTim/endTim: required for a one-off window")
+		}
+		return out, nil
+	}
+
+	if out.schedule, err = ParseCron(w.Cron); err != nil {
+		return nil, err
+	}
+	out.duration = time.Duration(w.DurationMin) * time.Minute
+	if out.duration <= 0 || out.duration > maxRecurse {
+		return nil, fmt.Errorf("durMin: must be 1-%d for a recurring window", int(maxRecurse/time.Minute))
+	}
+	return out, nil
+}
+
+// activeUntil: now 가 점검 시간대에 포함되면 종료 시각 반환
+func (w *window) activeUntil(now time.Time) (time.Time, bool) {
+	now = now.In(w.loc)
+	if !w.start.IsZero() && now.Before(w.start) {
+		return time.Time{}, false
+	}
+	if !w.end.IsZero() && !now.Before(w.end) {
+		return time.Time{}, false
+	}
+	if w.schedule == nil {
+		return w.end, true
+	}
+
+	fired, ok := w.schedule.Prev(now, w.duration)
+	if !ok {
+		return time.Time{}, false
+	}
+	until := fired.Add(w.duration)
+	if !now.Before(until) {
+		return time.Time{}, false
+	}
+	if !w.end.IsZero() && w.end.Before(until) {
+		until = w.end
+	}
+	return until, true
+}
+
+func (w *window) appliesTo(groupCd, apiCd string) bool {
+	return w.src.ApiGroupCode == groupCd && (w.src.ApiCode == "" || w.src.ApiCode == apiCd)
+}
+
+func (w *window) controlError(now, until time.Time) *model.ControlError {
+	retry := until.Sub(now)
+	if w.src.RetryAfterSec > 0 {
+		retry = time.Duration(w.src.RetryAfterSec) * time.Second
+	}
+	code := w.src.ControlCode
+	if code == "" {
+		code = "08"
+	}
+	return model.NewControlError(code, w.src.Message, retry)
+}
@@ -0,0 +1,154 @@
+package maintenance
+
+import (
+	"service-gateway/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustWindow(t *testing.T, w model.MaintenanceWindow) *window {
+	t.Helper()
+	out, err := compile(w)
+	if err != nil {
+		t.Fatalf("compile(%+v): %v", w, err)
+	}
+	return out
+}
+
+func TestCompileInvalid(t *testing.T) {
+	cases := []struct {
+		name string
+		w    model.MaintenanceWindow
+		want string
+	}{
+		{"bad tz", model.MaintenanceWindow{TimeZone: "Mars/Base", StartTim: "20250101000000", EndTim: "20250102000000"}, "tz"},
+		{"bad control code", model.MaintenanceWindow{ControlCode: "zz", StartTim: "20250101000000", EndTim: "20250102000000"}, "clotCtlCd"},
+		{"normal code", model.MaintenanceWindow{ControlCode: "00", StartTim: "20250101000000", EndTim: "20250102000000"}, "clotCtlCd"},
+		{"bad start", model.MaintenanceWindow{StartTim: "2025-01-01", EndTim: "20250102000000"}, "staTim"},
+		{"bad end", model.MaintenanceWindow{StartTim: "20250101000000", EndTim: "tomorrow"}, "endTim"},
+		{"end before start", model.MaintenanceWindow{StartTim: "20250102000000", EndTim: "20250101000000"}, "endTim"},
+		{"one-off without end", model.MaintenanceWindow{StartTim: "20250101000000"}, "staTim/endTim"},
+		{"bad cron", model.MaintenanceWindow{Cron: "0 25 * * *", DurationMin: 10}, "hour"},
+		{"zero duration", model.MaintenanceWindow{Cron: "0 2 * * *"}, "durMin"},
+		{"too long", model.MaintenanceWindow{Cron: "0 2 * * *", DurationMin: 7*24*60 + 1}, "durMin"},
+	}
+	for _, c := range cases {
+		err := Validate(c.w)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%s: got %v, want error containing %q", c.name, err, c.want)
+		}
+	}
+}
+
+func TestCompileDefaults(t *testing.T) {
+	w := mustWindow(t, model.MaintenanceWindow{StartTim: "20250101000000", EndTim: "20250102000000"})
+	if w.loc.String() != defaultTZ {
+		t.Errorf("loc = %s, want %s", w.loc, defaultTZ)
+	}
+	if w.src.ControlCode != "08" {
+		t.Errorf("control code = %q, want 08", w.src.ControlCode)
+	}
+	// 시각은 TimeZone 기준 (KST 자정 = 전날 15:00 UTC)
+	if want := time.Date(2024, 12, 31, 15, 0, 0, 0, time.UTC); !w.start.Equal(want) {
+		t.Errorf("start = %s, want %s", w.start, want)
+	}
+}
+
+func TestActiveUntilOneOff(t *testing.T) {
+	w := mustWindow(t, model.MaintenanceWindow{StartTim: "20250601220000", EndTim: "20250602020000"})
+	kst := w.loc
+	cases := []struct {
+		now    time.Time
+		active bool
+	}{
+		{time.Date(2025, 6, 1, 21, 59, 59, 0, kst), false},
+		{time.Date(2025, 6, 1, 22, 0, 0, 0, kst), true}, // 시작 포함
+		{time.Date(2025, 6, 2, 1, 59, 59, 0, kst), true},
+		{time.Date(2025, 6, 2, 2, 0, 0, 0, kst), false}, // 종료 미포함
+		{time.Date(2025, 6, 1, 14, 0, 0, 0, time.UTC), true},
+	}
+	for _, c := range cases {
+		until, ok := w.activeUntil(c.now)
+		if ok != c.active {
+			t.Errorf("activeUntil(%s) = %v, want %v", c.now, ok, c.active)
+			continue
+		}
+		if ok && !until.Equal(w.end) {
+			t.Errorf("activeUntil(%s) until = %s, want %s", c.now, until, w.end)
+		}
+	}
+}
+
+func TestActiveUntilRecurring(t *testing.T) {
+	// 매일 23:30 부터 60분
+	w := mustWindow(t, model.MaintenanceWindow{Cron: "30 23 * * *", DurationMin: 60})
+	kst := w.loc
+	cases := []struct {
+		now   time.Time
+		until time.Time // zero 면 비활성
+	}{
+		{time.Date(2025, 6, 1, 23, 29, 0, 0, kst), time.Time{}},
+		{time.Date(2025, 6, 1, 23, 30, 0, 0, kst), time.Date(2025, 6, 2, 0, 30, 0, 0, kst)},
+		{time.Date(2025, 6, 2, 0, 29, 59, 0, kst), time.Date(2025, 6, 2, 0, 30, 0, 0, kst)}, // 자정 넘김
+		{time.Date(2025, 6, 2, 0, 30, 0, 0, kst), time.Time{}},
+		{time.Date(2025, 6, 1, 14, 45, 0, 0, time.UTC), time.Date(2025, 6, 2, 0, 30, 0, 0, kst)}, // 요청 시각 zone 무관
+	}
+	for _, c := range cases {
+		until, ok := w.activeUntil(c.now)
+		if ok != !c.until.IsZero() || !until.Equal(c.until) {
+			t.Errorf("activeUntil(%s) = %s %v, want %s", c.now, until, ok, c.until)
+		}
+	}
+}
+
+func TestActiveUntilRecurringClamp(t *testing.T) {
+	// 6/2 ~ 6/4 사이에만 매일 23:30 부터 60분: 회차와 [staTim, endTim) 의 교집합만 점검
+	w := mustWindow(t, model.MaintenanceWindow{
+		Cron: "30 23 * * *", DurationMin: 60,
+		StartTim: "20250602000000", EndTim: "20250604000000",
+	})
+	kst := w.loc
+	cases := []struct {
+		now   time.Time
+		until time.Time
+	}{
+		{time.Date(2025, 6, 1, 23, 45, 0, 0, kst), time.Time{}},                             // 시작 전 회차
+		{time.Date(2025, 6, 2, 0, 15, 0, 0, kst), time.Date(2025, 6, 2, 0, 30, 0, 0, kst)},  // 시작 전에 발생한 회차의 시작 이후 부분
+		{time.Date(2025, 6, 2, 23, 45, 0, 0, kst), time.Date(2025, 6, 3, 0, 30, 0, 0, kst)}, // 범위 안
+		{time.Date(2025, 6, 3, 23, 45, 0, 0, kst), time.Date(2025, 6, 4, 0, 0, 0, 0, kst)},  // 종료 시각으로 절단
+		{time.Date(2025, 6, 4, 0, 15, 0, 0, kst), time.Time{}},                              // 종료 이후
+	}
+	for _, c := range cases {
+		until, ok := w.activeUntil(c.now)
+		if ok != !c.until.IsZero() || !until.Equal(c.until) {
+			t.Errorf("activeUntil(%s) = %s %v, want %s", c.now, until, ok, c.until)
+		}
+	}
+}
+
+func TestControlError(t *testing.T) {
+	w := mustWindow(t, model.MaintenanceWindow{StartTim: "20250601220000", EndTim: "20250602020000", Message: "정기 점검"})
+	now := time.Date(2025, 6, 1, 23, 0, 0, 0, w.loc)
+
+	ce := w.controlError(now, w.end)
+	if ce.Code != "08" || ce.Message != "정기 점검" || ce.RetryAfter != 3*time.Hour {
+		t.Errorf("controlError = %+v", ce)
+	}
+
+	w.src.RetryAfterSec = 60
+	if ce := w.controlError(now, w.end); ce.RetryAfter != time.Minute {
+		t.Errorf("retry-after override = %s, want 1m", ce.RetryAfter)
+	}
+}
+
+func TestAppliesTo(t *testing.T) {
+	group := mustWindow(t, model.MaintenanceWindow{ApiGroupCode: "G1", StartTim: "20250101000000", EndTim: "20250102000000"})
+	api := mustWindow(t, model.MaintenanceWindow{ApiGroupCode: "G1", ApiCode: "A1", StartTim: "20250101000000", EndTim: "20250102000000"})
+	if !group.appliesTo("G1", "A9") || group.appliesTo("G2", "A1") {
+		t.Error("group window scope")
+	}
+	if !api.appliesTo("G1", "A1") || api.appliesTo("G1", "A2") {
+		t.Error("api window scope")
+	}
+}
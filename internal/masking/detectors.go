@@ -0,0 +1,108 @@
+package masking
+
+import (
+	"regexp"
+	"strings"
+)
+
+// detector: 정규식 + 형식 유지 마스킹 함수
+type detector struct {
+	name string
+	re   *regexp.Regexp
+	mask func(string) string
+}
+
+// 적용 순서: 주민등록번호를 카드번호보다 먼저 (13자리 숫자가 카드 패턴에도 걸리므로)
+var detectorNames = []string{"rrn", "card", "phone", "email"}
+
+var detectors = map[string]detector{
+	// 주민/외국인등록번호: YYMMDD-[1-8]XXXXXX → 뒤 6자리 마스킹 (생년월일/성별 유지)
+	"rrn": {
+		name: "rrn",
+		re:   regexp.MustCompile(`\b\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])-?[1-8]\d{6}\b`),
+		mask: func(s string) string { return keepDigits(s, 7, 0) },
+	},
+	// 카드번호: 13~19자리 (공백/하이픈 구분 허용), Luhn 통과 시에만 → 앞 6 + 뒤 4 유지
+	"card": {
+		name: "card",
+		re:   regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		mask: func(s string) string {
+			if !luhn(s) {
+				return s
+			}
+			return keepDigits(s, 6, 4)
+		},
+	},
+	// 전화번호: 휴대폰(01X) / 지역번호(0XX) / 대표번호 → 가운데 자리 마스킹
+	"phone": {
+		name: "phone",
+		re:   regexp.MustCompile(`\b0(?:1[016789]|2|[3-6][1-5]|70)[ -]?\d{3,4}[ -]?\d{4}\b`),
+		mask: maskPhone,
+	},
+	// 이메일: 로컬파트 첫 글자만 유지
+	"email": {
+		name: "email",
+		re:   regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		mask: func(s string) string {
+			at := strings.LastIndexByte(s, '@')
+			return s[:1] + strings.Repeat("*", max(at-1, 3)) + s[at:]
+		},
+	},
+}
+
+func detectorByName(name string) (detector, bool) {
+	d, ok := detectors[strings.ToLower(strings.TrimSpace(name))]
+	return d, ok
+}
+
+// keepDigits: 앞 head / 뒤 tail 자리 숫자만 남기고 '*' (구분자 유지)
+func keepDigits(s string, head, tail int) string {
+	total := 0
+	for i := 0; i < len(s); i++ {
+		if isDigit(s[i]) {
+			total++
+		}
+	}
+	b := []byte(s)
+	n := 0
+	for i := range b {
+		if !isDigit(b[i]) {
+			continue
+		}
+		if n >= head && n < total-tail {
+			b[i] = '*'
+		}
+		n++
+	}
+	return string(b)
+}
+
+// maskPhone: 010-1234-5678 → 010-****-5678, 02-123-4567 → 02-***-4567
+func maskPhone(s string) string {
+	prefix := 3
+	if strings.HasPrefix(s, "02") {
+		prefix = 2
+	}
+	return keepDigits(s, prefix, 4)
+}
+
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if !isDigit(s[i]) {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
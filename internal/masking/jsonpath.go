@@ -0,0 +1,144 @@
+package masking
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONPath 부분집합: $ 루트, .key, ['key'], .*, [n], [*], ..key (재귀 탐색)
+type segKind int
+
+const (
+	segKey      segKind = iota // .key
+	segAnyKey                  // .* (객체 전체 값 / 배열 전체 원소)
+	segIndex                   // [n]
+	segAnyIndex                // [*]
+	segDeep                    // ..key
+)
+
+type segment struct {
+	kind segKind
+	key  string
+	idx  int
+}
+
+type jsonPath struct {
+	segs []segment
+}
+
+func compilePath(p string) (*jsonPath, error) {
+	s := strings.TrimSpace(p)
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("masking: json path %q must start with $", p)
+	}
+	s = s[1:]
+	jp := &jsonPath{}
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			name, rest := readName(s[2:])
+			if name == "" {
+				return nil, fmt.Errorf("masking: json path %q: name required after ..", p)
+			}
+			jp.segs = append(jp.segs, segment{kind: segDeep, key: name})
+			s = rest
+		case strings.HasPrefix(s, ".*"):
+			jp.segs = append(jp.segs, segment{kind: segAnyKey})
+			s = s[2:]
+		case s[0] == '.':
+			name, rest := readName(s[1:])
+			if name == "" {
+				return nil, fmt.Errorf("masking: json path %q: empty key", p)
+			}
+			jp.segs = append(jp.segs, segment{kind: segKey, key: name})
+			s = rest
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("masking: json path %q: missing ]", p)
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case inner == "*":
+				jp.segs = append(jp.segs, segment{kind: segAnyIndex})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				jp.segs = append(jp.segs, segment{kind: segKey, key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("masking: json path %q: bad index %q", p, inner)
+				}
+				jp.segs = append(jp.segs, segment{kind: segIndex, idx: n})
+			}
+		default:
+			return nil, fmt.Errorf("masking: json path %q: unexpected %q", p, s[:1])
+		}
+	}
+	if len(jp.segs) == 0 {
+		return nil, fmt.Errorf("masking: json path %q: root cannot be masked", p)
+	}
+	return jp, nil
+}
+
+func readName(s string) (name, rest string) {
+	i := strings.IndexAny(s, ".[")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+// replace: 경로에 해당하는 값을 repl 로 치환 (맵/슬라이스는 제자리 수정)
+func (p *jsonPath) replace(v any, repl string) any {
+	return apply(v, p.segs, repl)
+}
+
+func apply(v any, segs []segment, repl string) any {
+	if len(segs) == 0 {
+		return repl
+	}
+	s, rest := segs[0], segs[1:]
+	switch s.kind {
+	case segKey:
+		if m, ok := v.(map[string]any); ok {
+			if c, ok := m[s.key]; ok {
+				m[s.key] = apply(c, rest, repl)
+			}
+		}
+	case segAnyKey, segAnyIndex:
+		switch t := v.(type) {
+		case map[string]any:
+			if s.kind == segAnyKey {
+				for k, c := range t {
+					t[k] = apply(c, rest, repl)
+				}
+			}
+		case []any:
+			for i, c := range t {
+				t[i] = apply(c, rest, repl)
+			}
+		}
+	case segIndex:
+		if a, ok := v.([]any); ok && s.idx < len(a) {
+			a[s.idx] = apply(a[s.idx], rest, repl)
+		}
+	case segDeep:
+		switch t := v.(type) {
+		case map[string]any:
+			for k, c := range t {
+				if k == s.key {
+					t[k] = apply(c, rest, repl)
+				} else {
+					t[k] = apply(c, segs, repl)
+				}
+			}
+		case []any:
+			for i, c := range t {
+				t[i] = apply(c, segs, repl)
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package masking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompilePath(t *testing.T) {
+	cases := []struct {
+		path string
+		want []segment
+	}{
+		{"$.a.b", []segment{{kind: segKey, key: "a"}, {kind: segKey, key: "b"}}},
+		{"$['a.b'][\"c\"]", []segment{{kind: segKey, key: "a.b"}, {kind: segKey, key: "c"}}},
+		{"$.items[*].card", []segment{{kind: segKey, key: "items"}, {kind: segAnyIndex}, {kind: segKey, key: "card"}}},
+		{"$.items[2]", []segment{{kind: segKey, key: "items"}, {kind: segIndex, idx: 2}}},
+		{"$.a.*", []segment{{kind: segKey, key: "a"}, {kind: segAnyKey}}},
+		{"$..password", []segment{{kind: segDeep, key: "password"}}},
+		{" $..card.no ", []segment{{kind: segDeep, key: "card"}, {kind: segKey, key: "no"}}},
+	}
+	for _, c := range cases {
+		jp, err := compilePath(c.path)
+		if err != nil {
+			t.Errorf("%q: %v", c.path, err)
+			continue
+		}
+		if len(jp.segs) != len(c.want) {
+			t.Errorf("%q: segs = %+v, want %+v", c.path, jp.segs, c.want)
+			continue
+		}
+		for i := range c.want {
+			if jp.segs[i] != c.want[i] {
+				t.Errorf("%q [%d]: %+v, want %+v", c.path, i, jp.segs[i], c.want[i])
+			}
+		}
+	}
+}
+
+func TestCompilePathErrors(t *testing.T) {
+	for _, p := range []string{
+		"a.b",     // $ 없음
+		"$",       // 루트 전체
+		"$..",     // 재귀 이름 없음
+		"$.",      // 빈 키
+		"$.a[",    // ] 없음
+		"$.a[-1]", // 음수 인덱스
+		"$.a[x]",  // 숫자 아님
+		"$.a['b]", // 따옴표 불일치
+		"$a",      // . / [ 없이 이름
+	} {
+		if _, err := compilePath(p); err == nil {
+			t.Errorf("%q: expected error", p)
+		}
+	}
+}
+
+func replaced(t *testing.T, path, doc string) string {
+	t.Helper()
+	jp, err := compilePath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v any
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(jp.replace(v, "X"))
+	return string(b)
+}
+
+func TestJSONPathReplace(t *testing.T) {
+	cases := []struct {
+		path, doc, want string
+	}{
+		{"$.a.b", `{"a":{"b":1,"c":2}}`, `{"a":{"b":"X","c":2}}`},
+		{"$.a.b", `{"a":[1]}`, `{"a":[1]}`}, // 형태가 다르면 무시
+		{"$.missing", `{"a":1}`, `{"a":1}`}, // 없는 키는 추가하지 않음
+		{"$.items[*].card", `{"items":[{"card":"1"},{"card":"2","n":1}]}`, `{"items":[{"card":"X"},{"card":"X","n":1}]}`},
+		{"$.items[1]", `{"items":[1,2,3]}`, `{"items":[1,"X",3]}`},
+		{"$.items[5]", `{"items":[1]}`, `{"items":[1]}`},
+		{"$.a.*", `{"a":{"x":1,"y":[2]}}`, `{"a":{"x":"X","y":"X"}}`},
+		{"$.a[*]", `{"a":{"x":1}}`, `{"a":{"x":1}}`}, // [*] 는 배열만
+		// ..key: 모든 깊이 (배열 안 포함), 매칭된 값 아래는 더 내려가지 않음
+		{"$..password", `{"password":"p","user":{"password":"q","list":[{"password":"r"}]},"x":"password"}`,
+			`{"password":"X","user":{"list":[{"password":"X"}],"password":"X"},"x":"password"}`},
+		{"$..card.no", `{"card":{"no":"1","exp":"12"},"order":{"card":{"no":"2"}}}`,
+			`{"card":{"exp":"12","no":"X"},"order":{"card":{"no":"X"}}}`},
+		{"$..password", `[{"password":"p"},["x",{"password":"q"}]]`, `[{"password":"X"},["x",{"password":"X"}]]`},
+	}
+	for _, c := range cases {
+		if got := replaced(t, c.path, c.doc); got != c.want {
+			t.Errorf("%s on %s:\n got %s\nwant %s", c.path, c.doc, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package masking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+민감정보 마스킹
+
+WHY:
+- 감사 로그(Kafka/파일/웹훅)에 요청/응답 바디가 4KiB 까지, 표준출력에는 업스트림 바디 전체가,
+  ReverseProxy 디버그 덤프(httputil.DumpRequestOut)에는 Authorization 헤더까지 그대로 찍히고 있었음.
+- 로그로 나가는 모든 경로(audit.Emitter, 디버그 덤프, log.Printf)는 이 패키지를 거친 값만 기록.
+
+규칙:
+- json_paths : $.a.b / $.items[*].card / $..password (재귀) / $.a.* → 값 전체를 replacement 로 치환
+- headers    : 헤더 이름(대소문자 무시) → 값 전체 치환
+- fw_fields  : X-Fw-Header 의 K=V 필드(FwAuthorization 등) → 그 값만 치환 (TCID 등 추적 필드는 유지)
+- detectors  : card(Luhn 검증) | rrn(주민등록번호) | phone(휴대폰/유선) | email → 형식 유지 부분 마스킹
+- API 별 추가/해제 규칙은 SID_API_EST_MNG (registry.go)
+*/
+
+// Config: gateway.yaml masking 블록
+type Config struct {
+	Enabled     bool
+	Replacement string   // 기본 "****"
+	JSONPaths   []string // JSONPath 부분집합
+	Headers     []string
+	FwFields    []string // X-Fw-Header 필드 (비어 있으면 DefaultFwFields)
+	Detectors   []string // 비어 있으면 전체 (card, rrn, phone, email)
+}
+
+// DefaultHeaders: 설정이 없을 때 가리는 헤더
+var DefaultHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Fw-Session-Id"}
+
+// DefaultFwFields: 설정이 없을 때 가리는 X-Fw-Header 필드 (헤더 전체를 가리면 TCID 추적이 안 됨)
+var DefaultFwFields = []string{"FwAuthorization"}
+
+const fwHeader = "X-Fw-Header"
+
+// Masker: 불변 (규칙 추가 시 With 로 새 Masker 생성) → 여러 goroutine 에서 공유
+type Masker struct {
+	off         bool
+	replacement string
+	paths       []*jsonPath
+	headers     map[string]bool
+	fwFields    map[string]bool // 소문자
+	detectors   []detector
+}
+
+// New: 규칙 컴파일 (Enabled=false 면 아무것도 가리지 않는 Masker)
+func New(cfg Config) (*Masker, error) {
+	if !cfg.Enabled {
+		return &Masker{off: true}, nil
+	}
+	m := &Masker{replacement: cfg.Replacement, headers: map[string]bool{}, fwFields: map[string]bool{}}
+	if m.replacement == "" {
+		m.replacement = "****"
+	}
+	headers := cfg.Headers
+	if len(headers) == 0 {
+		headers = DefaultHeaders
+	}
+	for _, h := range headers {
+		m.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	fields := cfg.FwFields
+	if len(fields) == 0 {
+		fields = DefaultFwFields
+	}
+	for _, f := range fields {
+		m.fwFields[strings.ToLower(f)] = true
+	}
+	for _, p := range cfg.JSONPaths {
+		jp, err := compilePath(p)
+		if err != nil {
+			return nil, err
+		}
+		m.paths = append(m.paths, jp)
+	}
+	names := cfg.Detectors
+	if len(names) == 0 {
+		names = detectorNames
+	}
+	for _, n := range names {
+		d, ok := detectorByName(n)
+		if !ok {
+			return nil, fmt.Errorf("masking: unknown detector %q", n)
+		}
+		m.detectors = append(m.detectors, d)
+	}
+	return m, nil
+}
+
+var std atomic.Pointer[Masker]
+
+func init() {
+	m, _ := New(Config{Enabled: true})
+	std.Store(m)
+}
+
+// Configure: 기동 시 1회 (기본값: 전체 탐지기 + DefaultHeaders)
+func Configure(cfg Config) error {
+	m, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	std.Store(m)
+	return nil
+}
+
+// Default: 전역 Masker (API 정보가 없는 경로: 라우트 프록시 덤프 등)
+func Default() *Masker { return std.Load() }
+
+// Body: JSON 이면 경로 규칙 + 문자열 값 탐지, 그 외는 텍스트 전체에 탐지기 적용
+func (m *Masker) Body(b []byte) []byte {
+	if m == nil || m.off || len(b) == 0 {
+		return b
+	}
+	trimmed := bytes.TrimSpace(b)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err == nil && !dec.More() {
+			for _, p := range m.paths {
+				v = p.replace(v, m.replacement)
+			}
+			v = m.walk(v)
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(v); err == nil {
+				return bytes.TrimSuffix(buf.Bytes(), []byte{'\n'})
+			}
+		}
+	}
+	return []byte(m.String(string(b)))
+}
+
+// walk: JSON 값 중 문자열/숫자에 탐지기 적용
+func (m *Masker) walk(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, c := range t {
+			t[k] = m.walk(c)
+		}
+	case []any:
+		for i, c := range t {
+			t[i] = m.walk(c)
+		}
+	case string:
+		return m.String(t)
+	case json.Number:
+		if s := m.String(t.String()); s != t.String() {
+			return s
+		}
+	}
+	return v
+}
+
+// String: 탐지기만 적용 (로그 문자열, 비 JSON 바디)
+func (m *Masker) String(s string) string {
+	if m == nil || m.off {
+		return s
+	}
+	for _, d := range m.detectors {
+		s = d.re.ReplaceAllStringFunc(s, d.mask)
+	}
+	return s
+}
+
+// Header: 규칙 헤더 값을 치환한 복사본
+func (m *Masker) Header(h http.Header) http.Header {
+	out := h.Clone()
+	if m == nil || m.off {
+		return out
+	}
+	for k, vv := range out {
+		switch ck := http.CanonicalHeaderKey(k); {
+		case m.headers[ck]:
+			for i := range vv {
+				vv[i] = m.replacement
+			}
+		case ck == fwHeader:
+			for i := range vv {
+				vv[i] = m.fwHeader(vv[i])
+			}
+		}
+	}
+	return out
+}
+
+// fwHeader: "K=V;K2=V2" 중 fw_fields 값만 치환 (구분자 / 나머지 필드 유지)
+func (m *Masker) fwHeader(v string) string {
+	parts := strings.Split(v, ";")
+	for i, p := range parts {
+		k, _, ok := strings.Cut(p, "=")
+		if ok && m.fwFields[strings.ToLower(strings.TrimSpace(k))] {
+			parts[i] = k + "=" + m.replacement
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// Dump: httputil.DumpRequestOut / DumpResponse 결과 (헤더 줄 + 빈 줄 + 바디)
+func (m *Masker) Dump(dump []byte) []byte {
+	if m == nil || m.off {
+		return dump
+	}
+	head, body, found := bytes.Cut(dump, []byte("\r\n\r\n"))
+	lines := strings.Split(string(head), "\r\n")
+	for i, line := range lines {
+		if i == 0 {
+			continue // 요청/상태 줄
+		}
+		name, value, ok := strings.Cut(line, ":")
+		ck := http.CanonicalHeaderKey(strings.TrimSpace(name))
+		switch {
+		case ok && m.headers[ck]:
+			lines[i] = name + ": " + m.replacement
+		case ok && ck == fwHeader:
+			lines[i] = m.String(name + ":" + m.fwHeader(value))
+		default:
+			lines[i] = m.String(line)
+		}
+	}
+	out := []byte(strings.Join(lines, "\r\n"))
+	if found {
+		out = append(out, "\r\n\r\n"...)
+		out = append(out, m.Body(body)...)
+	}
+	return out
+}
+
+// Rules: API 별 규칙 (SID_API_EST_MNG VALUE 파싱 결과)
+type Rules struct {
+	JSONPaths []string
+	Headers   []string
+	Off       []string // 해제할 탐지기
+}
+
+// With: 기존 규칙 + API 별 규칙으로 새 Masker
+func (m *Masker) With(r Rules) (*Masker, error) {
+	if m == nil || m.off {
+		return m, nil
+	}
+	n := &Masker{
+		replacement: m.replacement,
+		paths:       slices.Clone(m.paths),
+		headers:     make(map[string]bool, len(m.headers)+len(r.Headers)),
+		fwFields:    m.fwFields, // 불변 (API 별 규칙 없음)
+	}
+	for h := range m.headers {
+		n.headers[h] = true
+	}
+	for _, h := range r.Headers {
+		n.headers[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, p := range r.JSONPaths {
+		jp, err := compilePath(p)
+		if err != nil {
+			return nil, err
+		}
+		n.paths = append(n.paths, jp)
+	}
+	for _, off := range r.Off {
+		if _, ok := detectorByName(off); !ok {
+			return nil, fmt.Errorf("masking: unknown detector %q", off)
+		}
+	}
+	for _, d := range m.detectors {
+		if !slices.Contains(r.Off, d.name) {
+			n.detectors = append(n.detectors, d)
+		}
+	}
+	return n, nil
+}
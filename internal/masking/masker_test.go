@@ -0,0 +1,162 @@
+package masking
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newMasker(t *testing.T, cfg Config) *Masker {
+	t.Helper()
+	cfg.Enabled = true
+	m, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDetectors(t *testing.T) {
+	m := newMasker(t, Config{})
+	cases := []struct {
+		in, want string
+	}{
+		{"card 4111-1111-1111-1111 end", "card 4111-11**-****-1111 end"},
+		{"card 4111111111111111", "card 411111******1111"},
+		{"card 4111 1111 1111 1111", "card 4111 11** **** 1111"},
+		{"order 4111111111111112", "order 4111111111111112"}, // Luhn 실패 → 카드 아님
+		{"rrn 900101-1234567", "rrn 900101-1******"},
+		{"rrn 9001011234567", "rrn 9001011******"},
+		{"rrn 901301-1234567", "rrn 901301-1234567"}, // 13월 → 주민번호 아님
+		{"tel 010-1234-5678", "tel 010-****-5678"},
+		{"tel 02-123-4567", "tel 02-***-4567"},
+		{"tel 01012345678", "tel 010****5678"},
+		{"mail hong@example.com", "mail h***@example.com"},
+		{"mail a@b.io", "mail a***@b.io"},
+	}
+	for _, c := range cases {
+		if got := m.String(c.in); got != c.want {
+			t.Errorf("%q: got %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+// 주민번호는 카드 패턴(13자리)에도 걸리므로 먼저 적용: Luhn 을 통과하는 주민번호도 주민번호 형식으로 마스킹
+func TestRRNBeforeCard(t *testing.T) {
+	const rrn = "9001011000006" // Luhn 통과
+	if !luhn(rrn) {
+		t.Fatal("fixture must pass Luhn")
+	}
+	m := newMasker(t, Config{})
+	if got := m.String(rrn); got != "9001011******" {
+		t.Errorf("got %q, want rrn masking", got)
+	}
+	card := newMasker(t, Config{Detectors: []string{"card"}})
+	if got := card.String(rrn); got != "900101***0006" {
+		t.Errorf("card only: got %q", got)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	if _, err := New(Config{Enabled: true, Detectors: []string{"iban"}}); err == nil {
+		t.Error("unknown detector: expected error")
+	}
+	if _, err := New(Config{Enabled: true, JSONPaths: []string{"a.b"}}); err == nil {
+		t.Error("bad json path: expected error")
+	}
+	off, err := New(Config{})
+	if err != nil || off.String("4111111111111111") != "4111111111111111" {
+		t.Errorf("disabled masker changed input: %v", err)
+	}
+}
+
+func TestBody(t *testing.T) {
+	m := newMasker(t, Config{JSONPaths: []string{"$..password"}})
+	got := string(m.Body([]byte(`{"user":{"password":"secret","tel":"010-1234-5678","amount":12345678901234567890}}`)))
+	want := `{"user":{"amount":12345678901234567890,"password":"****","tel":"010-****-5678"}}`
+	if got != want {
+		t.Errorf("json body:\n got %s\nwant %s", got, want)
+	}
+	if got := string(m.Body([]byte("password=secret&tel=010-1234-5678"))); got != "password=secret&tel=010-****-5678" {
+		t.Errorf("text body: %s", got)
+	}
+	if got := string(m.Body([]byte(`{"a":1} trailing`))); got != `{"a":1} trailing` {
+		t.Errorf("invalid json: %s", got)
+	}
+}
+
+func TestHeaderFwAuthorization(t *testing.T) {
+	m := newMasker(t, Config{})
+	h := http.Header{}
+	h.Set("Authorization", "Bearer abc")
+	h.Set("X-Fw-Header", "TCID=T1;FwAuthorization=k1.payload.sig;BizSrvcCd=SMP")
+	h.Set("X-Trace", "t")
+
+	out := m.Header(h)
+	if got := out.Get("X-Fw-Header"); got != "TCID=T1;FwAuthorization=****;BizSrvcCd=SMP" {
+		t.Errorf("X-Fw-Header = %q", got)
+	}
+	if out.Get("Authorization") != "****" || out.Get("X-Trace") != "t" {
+		t.Errorf("headers = %v", out)
+	}
+	if h.Get("Authorization") != "Bearer abc" {
+		t.Error("original header modified")
+	}
+
+	// 필드 이름 대소문자 / 공백 무시, 설정으로 필드 추가
+	custom := newMasker(t, Config{FwFields: []string{"FwAuthorization", "Guid"}})
+	h.Set("X-Fw-Header", " fwauthorization = tok ;Guid=g-1;TCID=T1")
+	if got := custom.Header(h).Get("X-Fw-Header"); got != " fwauthorization =****;Guid=****;TCID=T1" {
+		t.Errorf("custom fields: %q", got)
+	}
+}
+
+func TestDump(t *testing.T) {
+	m := newMasker(t, Config{JSONPaths: []string{"$.pin"}})
+	dump := strings.Join([]string{
+		"POST /v1/pay HTTP/1.1",
+		"Host: upstream",
+		"Authorization: Bearer abc",
+		"X-Fw-Header: TCID=T1;FwAuthorization=k1.payload.sig;BizSrvcCd=SMP",
+		"X-Contact: hong@example.com",
+		"",
+		`{"pin":"1234","card":"4111111111111111"}`,
+	}, "\r\n")
+
+	got := string(m.Dump([]byte(dump)))
+	for _, want := range []string{
+		"POST /v1/pay HTTP/1.1\r\n",
+		"Authorization: ****\r\n",
+		"X-Fw-Header: TCID=T1;FwAuthorization=****;BizSrvcCd=SMP\r\n",
+		"X-Contact: h***@example.com\r\n",
+		`{"card":"411111******1111","pin":"****"}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("dump missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "k1.payload.sig") {
+		t.Errorf("FwAuthorization leaked:\n%s", got)
+	}
+}
+
+func TestWith(t *testing.T) {
+	base := newMasker(t, Config{})
+	m, err := base.With(Rules{JSONPaths: []string{"$.no"}, Headers: []string{"X-Auth-Token"}, Off: []string{"phone"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.String("010-1234-5678"); got != "010-1234-5678" {
+		t.Errorf("phone off: %q", got)
+	}
+	if got := base.String("010-1234-5678"); got != "010-****-5678" {
+		t.Errorf("base changed: %q", got)
+	}
+	h := http.Header{"X-Auth-Token": {"t"}, "X-Fw-Header": {"FwAuthorization=x"}}
+	if out := m.Header(h); out.Get("X-Auth-Token") != "****" || out.Get("X-Fw-Header") != "FwAuthorization=****" {
+		t.Errorf("headers = %v", out)
+	}
+	if _, err := base.With(Rules{Off: []string{"iban"}}); err == nil {
+		t.Error("unknown detector: expected error")
+	}
+}
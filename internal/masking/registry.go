@@ -0,0 +1,182 @@
+package masking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"service-gateway/internal/model"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+API 별 마스킹 규칙 (SID_API_EST_MNG)
+
+- API_EST_KEY = 'MASK'           : 그룹 전체
+- API_EST_KEY = 'MASK.<API_CD>'  : 해당 API (그룹 규칙에 추가)
+- VALUE (한 행에 규칙 하나)
+    json:$.card.no      JSON 경로 추가
+    header:X-Auth-Token 헤더 추가
+    off:phone           탐지기 해제 (주문번호가 전화번호로 오인되는 API 등)
+- ttl 동안 메모리 캐시, 관리 API 변경 시 Invalidate() 로 즉시 재적재, 적재 실패 시 이전 규칙 유지
+*/
+
+// Source: 마스킹 규칙 조회 (store.Repository 가 구현)
+type Source interface {
+	ListMaskSettings(ctx context.Context) ([]model.ApiSetting, error)
+}
+
+const settingKey = "MASK"
+
+type Registry struct {
+	src Source
+	ttl time.Duration
+
+	mu       sync.RWMutex
+	maskers  map[string]*Masker // "<group>" / "<group>:<api>"
+	base     *Masker            // 적재 시점의 Default()
+	loadedAt time.Time
+}
+
+func NewRegistry(src Source, ttl time.Duration) *Registry {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &Registry{src: src, ttl: ttl}
+}
+
+// For: API 규칙 > 그룹 규칙 > 전역 규칙 (nil Registry 는 전역)
+func (r *Registry) For(ctx context.Context, groupCd, apiCd string) *Masker {
+	if r == nil {
+		return Default()
+	}
+	ms := r.load(ctx)
+	if m, ok := ms[groupCd+":"+apiCd]; ok {
+		return m
+	}
+	if m, ok := ms[groupCd]; ok {
+		return m
+	}
+	return Default()
+}
+
+// Invalidate: 다음 For 에서 DB 재조회 (admin.Invalidator)
+func (r *Registry) Invalidate() {
+	r.mu.Lock()
+	r.loadedAt = time.Time{}
+	r.mu.Unlock()
+}
+
+func (r *Registry) load(ctx context.Context) map[string]*Masker {
+	base := Default()
+	r.mu.RLock()
+	if time.Since(r.loadedAt) < r.ttl && r.base == base {
+		ms := r.maskers
+		r.mu.RUnlock()
+		return ms
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.loadedAt) < r.ttl && r.base == base { // 다른 goroutine 이 먼저 적재
+		return r.maskers
+	}
+
+	rows, err := r.src.ListMaskSettings(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "load rules failed, keep previous", "component", "masking", "err", err)
+		r.loadedAt = time.Now()
+		return r.maskers
+	}
+	r.maskers = build(base, rows)
+	r.base = base
+	r.loadedAt = time.Now()
+	return r.maskers
+}
+
+// build: 그룹 규칙 → 그룹 Masker, API 규칙 → 그룹 규칙 + API 규칙 Masker
+func build(base *Masker, rows []model.ApiSetting) map[string]*Masker {
+	groups := map[string]*Rules{}
+	apis := map[string]*Rules{}
+	for _, s := range rows {
+		target := groups
+		id := s.ApiGroupCode
+		if api, ok := strings.CutPrefix(s.Key, settingKey+"."); ok {
+			target, id = apis, s.ApiGroupCode+":"+api
+		} else if s.Key != settingKey {
+			continue
+		}
+		rules := target[id]
+		if rules == nil {
+			rules = &Rules{}
+			target[id] = rules
+		}
+		if err := rules.add(s.Value); err != nil {
+			slog.Warn("skip rule", "component", "masking", "api_group_cd", s.ApiGroupCode, "key", s.Key, "value", s.Value, "err", err)
+		}
+	}
+
+	out := make(map[string]*Masker, len(groups)+len(apis))
+	compile := func(id string, r Rules) {
+		m, err := base.With(r)
+		if err != nil {
+			slog.Warn("skip rules", "component", "masking", "target", id, "err", err)
+			return
+		}
+		out[id] = m
+	}
+	for g, r := range groups {
+		compile(g, *r)
+	}
+	for id, r := range apis {
+		merged := *r
+		if g := groups[strings.SplitN(id, ":", 2)[0]]; g != nil {
+			merged = Rules{
+				JSONPaths: append(append([]string{}, g.JSONPaths...), r.JSONPaths...),
+				Headers:   append(append([]string{}, g.Headers...), r.Headers...),
+				Off:       append(append([]string{}, g.Off...), r.Off...),
+			}
+		}
+		compile(id, merged)
+	}
+	return out
+}
+
+// IsSettingKey: 마스킹 규칙 키인지 (관리 API 검증용)
+func IsSettingKey(key string) bool {
+	return key == settingKey || strings.HasPrefix(key, settingKey+".")
+}
+
+// ValidateRule: VALUE 문법 검사 (관리 API 등록 시)
+func ValidateRule(value string) error {
+	return new(Rules).add(value)
+}
+
+// add: VALUE 한 줄 파싱 (json:<path> | header:<name> | off:<detector>)
+func (r *Rules) add(value string) error {
+	kind, arg, ok := strings.Cut(strings.TrimSpace(value), ":")
+	arg = strings.TrimSpace(arg)
+	if !ok || arg == "" {
+		return fmt.Errorf("want json:<path> | header:<name> | off:<detector>")
+	}
+	switch strings.ToLower(kind) {
+	case "json":
+		if _, err := compilePath(arg); err != nil {
+			return err
+		}
+		r.JSONPaths = append(r.JSONPaths, arg)
+	case "header":
+		r.Headers = append(r.Headers, arg)
+	case "off":
+		arg = strings.ToLower(arg)
+		if _, ok := detectorByName(arg); !ok {
+			return fmt.Errorf("unknown detector %q", arg)
+		}
+		r.Off = append(r.Off, arg)
+	default:
+		return fmt.Errorf("unknown rule kind %q", kind)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package masking
+
+import (
+	"context"
+	"errors"
+	"service-gateway/internal/model"
+	"testing"
+	"time"
+)
+
+type settings struct {
+	rows  []model.ApiSetting
+	err   error
+	calls int
+}
+
+func (s *settings) ListMaskSettings(context.Context) ([]model.ApiSetting, error) {
+	s.calls++
+	return s.rows, s.err
+}
+
+func TestBuildMergesGroupAndAPI(t *testing.T) {
+	base := newMasker(t, Config{})
+	ms := build(base, []model.ApiSetting{
+		{ApiGroupCode: "009", Key: "MASK", Value: "json:$.acct"},
+		{ApiGroupCode: "009", Key: "MASK", Value: "off:email"},
+		{ApiGroupCode: "009", Key: "MASK.S01", Value: "json:$.pin"},
+		{ApiGroupCode: "009", Key: "MASK.S01", Value: "off:phone"},
+		{ApiGroupCode: "009", Key: "MASK.S02", Value: "bogus"},    // 문법 오류 → 규칙만 건너뜀
+		{ApiGroupCode: "010", Key: "MASK.S01", Value: "json:$.x"}, // 그룹 규칙 없는 API
+		{ApiGroupCode: "009", Key: "LOG", Value: "json:$.acct"},   // 마스킹 키 아님
+	})
+
+	body := `{"acct":"123","pin":"0000","x":"1","tel":"010-1234-5678","mail":"hong@example.com"}`
+	cases := []struct {
+		id   string
+		want string
+	}{
+		{"009", `{"acct":"****","mail":"hong@example.com","pin":"0000","tel":"010-****-5678","x":"1"}`},
+		{"009:S01", `{"acct":"****","mail":"hong@example.com","pin":"****","tel":"010-1234-5678","x":"1"}`},
+		{"009:S02", `{"acct":"****","mail":"hong@example.com","pin":"0000","tel":"010-****-5678","x":"1"}`},
+		{"010:S01", `{"acct":"123","mail":"h***@example.com","pin":"0000","tel":"010-****-5678","x":"****"}`},
+	}
+	for _, c := range cases {
+		m, ok := ms[c.id]
+		if !ok {
+			t.Errorf("%s: no masker", c.id)
+			continue
+		}
+		if got := string(m.Body([]byte(body))); got != c.want {
+			t.Errorf("%s:\n got %s\nwant %s", c.id, got, c.want)
+		}
+	}
+	if _, ok := ms["010"]; ok {
+		t.Error("group masker built without group rules")
+	}
+}
+
+func TestRegistryFor(t *testing.T) {
+	src := &settings{rows: []model.ApiSetting{
+		{ApiGroupCode: "009", Key: "MASK", Value: "json:$.acct"},
+		{ApiGroupCode: "009", Key: "MASK.S01", Value: "json:$.pin"},
+	}}
+	r := NewRegistry(src, time.Minute)
+	ctx := context.Background()
+	body := []byte(`{"acct":"1","pin":"2"}`)
+
+	if got := string(r.For(ctx, "009", "S01").Body(body)); got != `{"acct":"****","pin":"****"}` {
+		t.Errorf("api rules: %s", got)
+	}
+	if got := string(r.For(ctx, "009", "S99").Body(body)); got != `{"acct":"****","pin":"2"}` {
+		t.Errorf("group rules: %s", got)
+	}
+	if r.For(ctx, "777", "S01") != Default() {
+		t.Error("unknown group should use global masker")
+	}
+	if src.calls != 1 {
+		t.Errorf("source calls = %d, want 1 (cached)", src.calls)
+	}
+
+	// 적재 실패 → 이전 규칙 유지
+	src.err = errors.New("db down")
+	r.Invalidate()
+	if got := string(r.For(ctx, "009", "S01").Body(body)); got != `{"acct":"****","pin":"****"}` {
+		t.Errorf("after failed reload: %s", got)
+	}
+	if src.calls != 2 {
+		t.Errorf("source calls = %d, want 2", src.calls)
+	}
+
+	var nilReg *Registry
+	if nilReg.For(ctx, "009", "S01") != Default() {
+		t.Error("nil registry should use global masker")
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	for _, ok := range []string{"json:$.card.no", "header:X-Auth-Token", "off:phone", "OFF:Email"} {
+		if err := ValidateRule(ok); err != nil {
+			t.Errorf("%q: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "json:", "json:card", "off:iban", "mask:$.a", "header"} {
+		if err := ValidateRule(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"net/http"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/model"
 	"strconv"
 )
 
@@ -44,8 +46,8 @@ func BodyLimit(next http.Handler, maxBytes int64) http.Handler {
 		//      커넥션 / 네트워크 / 메모리 낭비를 최소화.
 		if cl := r.Header.Get("Content-Length"); cl != "" { // 헤더가 제공된 경우에만 검사
 			if n, err := strconv.ParseInt(cl, 10, 64); err == nil && n > maxBytes { // 숫자 파싱 성공 && 한도 초과
-				http.Error(w, "request entity too large", http.StatusRequestEntityTooLarge) // 413 응답
-				return                                                                      // 조기 종료(본문 읽지 않음)
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeBodyTooLarge, nil)) // 413 응답 (코드 12)
+				return                                                            // 조기 종료(본문 읽지 않음)
 			}
 			// 파싱 실패(err != nil) 또는 n <= maxBytes 인 경우에는 계속 진행 (실제 바디 검증은 아래 MaxBytesReader에서 2차 처리)
 		}
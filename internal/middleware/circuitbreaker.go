@@ -1,9 +1,11 @@
 package middleware
 
 import (
-	"net/http" // HTTP 미들웨어 체인에 편입하기 위해 사용
-	"sync"     // 동시성 안전한 상태 전이를 위해 뮤텍스 사용
-	"time"     // 실패 시점 기록 및 타임아웃 계산
+	"net/http"                       // HTTP 미들웨어 체인에 편입하기 위해 사용
+	"service-gateway/internal/httpx" // 공통 에러 응답 (Open 시 코드 22)
+	"service-gateway/internal/model"
+	"sync" // 동시성 안전한 상태 전이를 위해 뮤텍스 사용
+	"time" // 실패 시점 기록 및 타임아웃 계산
 )
 
 /*
@@ -88,7 +90,7 @@ func (cb *CircuitBreaker) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// (1) 현재 상태가 요청을 허용하는지 검사 (Open 이면 즉시 503)
 		if !cb.allow() {
-			http.Error(w, "Service temporarily unavailable", http.StatusServiceUnavailable)
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeUnavailable, nil))
 			return
 		}
 
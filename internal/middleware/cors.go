@@ -0,0 +1,296 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+CORS 미들웨어
+
+WHY:
+- 브라우저 프론트엔드가 게이트웨이를 직접 호출하는데, preflight(OPTIONS) 가 MatchRoute 404 /
+  /gateway 핸들러 405 로 끝나 실제 요청이 전송되지 않았음.
+- preflight 는 업스트림을 호출하지 않고 게이트웨이가 바로 응답 (JWT / 멱등키 / 본문 제한보다 바깥).
+
+정책 (전역 cors, 라우트별 routes[].options.cors 가 있으면 그 라우트는 라우트 정책으로 대체):
+- allowed_origins: "*" | "https://app.example.com" | "https://*.example.com"(하위 도메인, 스킴 생략 시 모든 스킴)
+  정확 일치는 포트까지 비교, 와일드카드는 포트를 적지 않으면 모든 포트 허용 ("https://*.example.com:8443" 은 그 포트만)
+- allowed_methods / allowed_headers("*" 면 요청 헤더 반영) / exposed_headers / allow_credentials / max_age
+- credentials 허용 시 Allow-Origin 은 허용된 요청 Origin 을 그대로 응답 (브라우저는 credentials 에 "*" 를 거부)
+- "*" + allow_credentials 는 기동 실패: 모든 Origin 을 반영하면 임의 사이트가 사용자 쿠키/인증으로 호출 가능
+- 허용되지 않은 preflight 는 403 (CORS 헤더 없음), 일반 요청은 CORS 헤더 없이 그대로 처리 (브라우저가 차단)
+- 업스트림이 보낸 Access-Control-* 는 게이트웨이 정책으로 덮어씀
+*/
+
+// CORSPolicy: 전역 / 라우트 정책
+type CORSPolicy struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string // 비우면 GET, HEAD, POST
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+type originPattern struct {
+	any    bool
+	scheme string // 빈 값이면 모든 스킴
+	host   string // 정확 일치 (포트 포함)
+	suffix string // 와일드카드: ".example.com" (포트를 적으면 ".example.com:8443")
+}
+
+type corsPolicy struct {
+	CORSPolicy
+	origins []originPattern
+	methods []string
+	headers []string // 소문자
+	anyHdr  bool
+}
+
+type CORS struct {
+	global *corsPolicy
+	routes map[string]*corsPolicy
+	route  func(r *http.Request) string // 요청 → 라우트 이름 (preflight 는 Access-Control-Request-Method 로 매칭)
+}
+
+// NewCORS: global 이 nil 이면 라우트 정책만 적용, origin 패턴 오류는 에러
+func NewCORS(global *CORSPolicy, routes map[string]*CORSPolicy, route func(r *http.Request) string) (*CORS, error) {
+	c := &CORS{routes: map[string]*corsPolicy{}, route: route}
+	var err error
+	if global != nil {
+		if c.global, err = compileCORS(*global); err != nil {
+			return nil, fmt.Errorf("cors: %w", err)
+		}
+	}
+	for name, p := range routes {
+		if c.routes[name], err = compileCORS(*p); err != nil {
+			return nil, fmt.Errorf("routes[%s].options.cors: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+func compileCORS(p CORSPolicy) (*corsPolicy, error) {
+	cp := &corsPolicy{CORSPolicy: p}
+	for _, o := range p.AllowedOrigins {
+		op, err := parseOrigin(o)
+		if err != nil {
+			return nil, err
+		}
+		if op.any && p.AllowCredentials {
+			return nil, fmt.Errorf(`allowed_origins "*" cannot be combined with allow_credentials`)
+		}
+		cp.origins = append(cp.origins, op)
+	}
+	cp.methods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	if len(p.AllowedMethods) > 0 {
+		cp.methods = cp.methods[:0]
+		for _, m := range p.AllowedMethods {
+			cp.methods = append(cp.methods, strings.ToUpper(m))
+		}
+	}
+	for _, h := range p.AllowedHeaders {
+		if h == "*" {
+			cp.anyHdr = true
+			continue
+		}
+		cp.headers = append(cp.headers, strings.ToLower(h))
+	}
+	return cp, nil
+}
+
+func parseOrigin(s string) (originPattern, error) {
+	if s == "*" {
+		return originPattern{any: true}, nil
+	}
+	var op originPattern
+	rest := strings.ToLower(strings.TrimSuffix(s, "/"))
+	if scheme, host, ok := strings.Cut(rest, "://"); ok {
+		op.scheme, rest = scheme, host
+	}
+	if after, ok := strings.CutPrefix(rest, "*."); ok {
+		if after == "" || strings.Contains(after, "*") {
+			return op, fmt.Errorf("invalid origin pattern %q", s)
+		}
+		op.suffix = "." + after
+		return op, nil
+	}
+	if rest == "" || strings.ContainsAny(rest, "*/") {
+		return op, fmt.Errorf("invalid origin pattern %q", s)
+	}
+	op.host = rest
+	return op, nil
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	u, err := url.Parse(strings.ToLower(origin))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	for _, op := range p.origins {
+		switch {
+		case op.any:
+			return true
+		case op.scheme != "" && op.scheme != u.Scheme:
+			continue
+		case op.host != "" && op.host == u.Host:
+			return true
+		case op.suffix != "" && op.matchSuffix(u):
+			return true
+		}
+	}
+	return false
+}
+
+// matchSuffix: 패턴에 포트가 없으면 호스트명만 비교, apex("example.com") 자체는 불일치
+func (op originPattern) matchSuffix(u *url.URL) bool {
+	host := u.Hostname()
+	if strings.Contains(op.suffix, ":") {
+		host = u.Host
+	}
+	return strings.HasSuffix(host, op.suffix) && len(host) > len(op.suffix)
+}
+
+func (p *corsPolicy) anyOrigin() bool {
+	return slices.ContainsFunc(p.origins, func(o originPattern) bool { return o.any })
+}
+
+// policyFor: 라우트 정책 우선, 없으면 전역
+func (c *CORS) policyFor(r *http.Request) *corsPolicy {
+	if c.route != nil && len(c.routes) > 0 {
+		if p, ok := c.routes[c.route(r)]; ok {
+			return p
+		}
+	}
+	return c.global
+}
+
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reqMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && reqMethod != "" {
+			// preflight: 실제 메서드로 라우트 매칭
+			probe := r.Clone(r.Context())
+			probe.Method = strings.ToUpper(reqMethod)
+			c.preflight(w, r, c.policyFor(probe), probe.Method)
+			return
+		}
+
+		p := c.policyFor(r)
+		if p == nil || !p.allowOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&corsWriter{ResponseWriter: w, apply: func(h http.Header) { p.actualHeaders(h, origin) }}, r)
+	})
+}
+
+func (c *CORS) preflight(w http.ResponseWriter, r *http.Request, p *corsPolicy, method string) {
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	origin := r.Header.Get("Origin")
+	if p == nil || !p.allowOrigin(origin) || !slices.Contains(p.methods, method) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	var reqHeaders []string
+	for _, v := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				reqHeaders = append(reqHeaders, s)
+			}
+		}
+	}
+	if !p.anyHdr {
+		for _, rh := range reqHeaders {
+			if !slices.Contains(p.headers, strings.ToLower(rh)) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+		}
+	}
+
+	p.originHeaders(h, origin)
+	h.Set("Access-Control-Allow-Methods", strings.Join(p.methods, ", "))
+	if len(reqHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(reqHeaders, ", "))
+	}
+	if p.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge/time.Second)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *corsPolicy) originHeaders(h http.Header, origin string) {
+	if p.anyOrigin() { // "*" 는 credentials 와 함께 설정 불가 (compileCORS)
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// actualHeaders: 일반 요청 응답 (업스트림 Access-Control-* 제거 후 정책 적용)
+func (p *corsPolicy) actualHeaders(h http.Header, origin string) {
+	for k := range h {
+		if strings.HasPrefix(k, "Access-Control-") {
+			h.Del(k)
+		}
+	}
+	p.originHeaders(h, origin)
+	if len(p.ExposedHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+	}
+	if !slices.Contains(h.Values("Vary"), "Origin") {
+		h.Add("Vary", "Origin")
+	}
+}
+
+// corsWriter: 헤더 확정 직전(WriteHeader) 에 CORS 헤더 적용
+type corsWriter struct {
+	http.ResponseWriter
+	apply   func(http.Header)
+	applied bool
+}
+
+func (cw *corsWriter) WriteHeader(code int) {
+	if !cw.applied {
+		cw.applied = true
+		cw.apply(cw.Header())
+	}
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *corsWriter) Write(b []byte) (int, error) {
+	if !cw.applied {
+		cw.WriteHeader(http.StatusOK)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+func (cw *corsWriter) Flush() {
+	if !cw.applied {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (cw *corsWriter) Unwrap() http.ResponseWriter { return cw.ResponseWriter }
@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func mustCORS(t *testing.T, global *CORSPolicy, routes map[string]*CORSPolicy, route func(*http.Request) string) *CORS {
+	t.Helper()
+	c, err := NewCORS(global, routes, route)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestAllowOrigin(t *testing.T) {
+	cases := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"*", "https://anything.test", true},
+		{"https://app.example.com", "https://app.example.com", true},
+		{"https://app.example.com", "HTTPS://APP.EXAMPLE.COM", true},
+		{"https://app.example.com/", "https://app.example.com", true},
+		{"https://app.example.com", "http://app.example.com", false},
+		{"https://app.example.com", "https://app.example.com:8443", false}, // 정확 일치는 포트 포함
+		{"https://app.example.com:8443", "https://app.example.com:8443", true},
+		{"https://*.example.com", "https://a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://a.example.com:8443", true}, // 포트 없는 와일드카드는 모든 포트
+		{"https://*.example.com", "https://example.com", false},       // apex 불일치
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "https://a.example.com.evil.test", false},
+		{"https://*.example.com", "http://a.example.com", false},
+		{"https://*.example.com:8443", "https://a.example.com:8443", true},
+		{"https://*.example.com:8443", "https://a.example.com", false},
+		{"https://*.example.com:8443", "https://a.example.com:9443", false},
+		{"*.example.com", "http://a.example.com", true}, // 스킴 생략 → 모든 스킴
+		{"*.example.com", "https://a.example.com", true},
+		{"app.example.com", "http://app.example.com", true},
+		{"app.example.com", "https://app.example.com", true},
+		{"https://app.example.com", "null", false},
+		{"https://app.example.com", "app.example.com", false},
+	}
+	for _, c := range cases {
+		p, err := compileCORS(CORSPolicy{AllowedOrigins: []string{c.pattern}})
+		if err != nil {
+			t.Fatalf("%s: %v", c.pattern, err)
+		}
+		if got := p.allowOrigin(c.origin); got != c.want {
+			t.Errorf("pattern %q, origin %q: got %v, want %v", c.pattern, c.origin, got, c.want)
+		}
+	}
+}
+
+func TestParseOriginInvalid(t *testing.T) {
+	for _, s := range []string{"", "https://", "*.", "https://*.", "https://*.*.example.com", "https://a*.example.com", "https://app.example.com/path"} {
+		if _, err := NewCORS(&CORSPolicy{AllowedOrigins: []string{s}}, nil, nil); err == nil {
+			t.Errorf("pattern %q: expected error", s)
+		}
+	}
+}
+
+func okHandler(h http.Header) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for k, v := range h {
+			w.Header()[k] = v
+		}
+		w.Write([]byte("ok"))
+	})
+}
+
+func TestCORSActual(t *testing.T) {
+	c := mustCORS(t, &CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		ExposedHeaders: []string{"X-Fw-Tcid"},
+	}, nil, nil)
+	upstream := http.Header{"Access-Control-Allow-Origin": {"https://upstream.test"}, "Access-Control-Max-Age": {"999"}}
+
+	r := httptest.NewRequest(http.MethodPost, "/gateway", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	c.Middleware(okHandler(upstream)).ServeHTTP(w, r)
+
+	h := w.Header()
+	if got := h.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Allow-Origin = %q, want *", got)
+	}
+	if h.Get("Access-Control-Max-Age") != "" || h.Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("upstream Access-Control-* not replaced: %v", h)
+	}
+	if got := h.Get("Access-Control-Expose-Headers"); got != "X-Fw-Tcid" {
+		t.Errorf("Expose-Headers = %q", got)
+	}
+	if got := h.Values("Vary"); len(got) != 1 || got[0] != "Origin" {
+		t.Errorf("Vary = %q", got)
+	}
+
+	// Origin 없는 요청은 손대지 않음
+	r = httptest.NewRequest(http.MethodPost, "/gateway", nil)
+	w = httptest.NewRecorder()
+	c.Middleware(okHandler(upstream)).ServeHTTP(w, r)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://upstream.test" {
+		t.Errorf("no Origin: Allow-Origin = %q", got)
+	}
+}
+
+func TestCORSCredentialsEchoOrigin(t *testing.T) {
+	c := mustCORS(t, &CORSPolicy{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true}, nil, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/gateway", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w := httptest.NewRecorder()
+	c.Middleware(okHandler(nil)).ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Allow-Origin = %q, want request Origin", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Allow-Credentials = %q", got)
+	}
+}
+
+func TestCORSWildcardWithCredentialsRejected(t *testing.T) {
+	p := &CORSPolicy{AllowedOrigins: []string{"https://app.example.com", "*"}, AllowCredentials: true}
+	if _, err := NewCORS(p, nil, nil); err == nil {
+		t.Error("global: expected error")
+	}
+	if _, err := NewCORS(nil, map[string]*CORSPolicy{"r": p}, nil); err == nil {
+		t.Error("route: expected error")
+	}
+	p.AllowCredentials = false
+	if _, err := NewCORS(p, nil, nil); err != nil {
+		t.Errorf("without credentials: %v", err)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	c := mustCORS(t, &CORSPolicy{AllowedOrigins: []string{"https://*.example.com"}}, nil, nil)
+
+	r := httptest.NewRequest(http.MethodGet, "/gateway", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := httptest.NewRecorder()
+	c.Middleware(okHandler(nil)).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Errorf("actual request must pass through: %d %q", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Allow-Origin = %q, want none", got)
+	}
+}
+
+func preflightReq(origin, method, headers string) *http.Request {
+	r := httptest.NewRequest(http.MethodOptions, "/gateway", nil)
+	r.Header.Set("Origin", origin)
+	r.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		r.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return r
+}
+
+func TestCORSPreflight(t *testing.T) {
+	c := mustCORS(t, &CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"get", "post"},
+		AllowedHeaders: []string{"Content-Type", "X-Fw-Tcid"},
+		MaxAge:         90500 * time.Millisecond,
+	}, nil, nil)
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { t.Error("preflight reached next handler") })
+
+	cases := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+		want    int
+	}{
+		{"allowed", "https://app.example.com", "POST", "content-type, x-fw-tcid", http.StatusNoContent},
+		{"disallowed origin", "https://other.example.com", "POST", "", http.StatusForbidden},
+		{"disallowed method", "https://app.example.com", "DELETE", "", http.StatusForbidden},
+		{"disallowed header", "https://app.example.com", "POST", "Content-Type, X-Secret", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c.Middleware(next).ServeHTTP(w, preflightReq(tc.origin, tc.method, tc.headers))
+		if w.Code != tc.want {
+			t.Errorf("%s: status %d, want %d", tc.name, w.Code, tc.want)
+			continue
+		}
+		h := w.Header()
+		if len(h.Values("Vary")) != 3 {
+			t.Errorf("%s: Vary = %q", tc.name, h.Values("Vary"))
+		}
+		if tc.want == http.StatusForbidden {
+			if h.Get("Access-Control-Allow-Origin") != "" {
+				t.Errorf("%s: CORS headers on 403", tc.name)
+			}
+			continue
+		}
+		want := map[string]string{
+			"Access-Control-Allow-Origin":  "https://app.example.com",
+			"Access-Control-Allow-Methods": "GET, POST",
+			"Access-Control-Allow-Headers": "content-type, x-fw-tcid",
+			"Access-Control-Max-Age":       "90",
+		}
+		for k, v := range want {
+			if got := h.Get(k); got != v {
+				t.Errorf("%s: %s = %q, want %q", tc.name, k, got, v)
+			}
+		}
+	}
+}
+
+func TestCORSPreflightAnyHeader(t *testing.T) {
+	c := mustCORS(t, &CORSPolicy{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}}, nil, nil)
+	w := httptest.NewRecorder()
+	c.Middleware(okHandler(nil)).ServeHTTP(w, preflightReq("https://app.example.com", "GET", "X-A, X-B"))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "X-A, X-B" {
+		t.Errorf("Allow-Headers = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "" {
+		t.Errorf("Max-Age = %q, want none", got)
+	}
+}
+
+func TestCORSRoutePolicy(t *testing.T) {
+	// 라우트 판별: PUT 은 "items" 라우트, 나머지는 전역
+	route := func(r *http.Request) string {
+		if r.Method == http.MethodPut {
+			return "items"
+		}
+		return ""
+	}
+	c := mustCORS(t,
+		&CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}},
+		map[string]*CORSPolicy{"items": {AllowedOrigins: []string{"https://my.example.com"}, AllowedMethods: []string{"PUT"}}},
+		route)
+
+	cases := []struct {
+		origin, method string
+		want           int
+	}{
+		{"https://my.example.com", "PUT", http.StatusNoContent},
+		{"https://app.example.com", "PUT", http.StatusForbidden}, // 라우트 정책이 전역을 대체
+		{"https://app.example.com", "POST", http.StatusNoContent},
+		{"https://my.example.com", "POST", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c.Middleware(okHandler(nil)).ServeHTTP(w, preflightReq(tc.origin, tc.method, ""))
+		if w.Code != tc.want {
+			t.Errorf("%s %s: status %d, want %d", tc.method, tc.origin, w.Code, tc.want)
+		}
+	}
+
+	// 전역 정책이 없으면 라우트 외 preflight 는 403
+	c = mustCORS(t, nil, map[string]*CORSPolicy{"items": {AllowedOrigins: []string{"*"}, AllowedMethods: []string{"PUT"}}}, route)
+	w := httptest.NewRecorder()
+	c.Middleware(okHandler(nil)).ServeHTTP(w, preflightReq("https://app.example.com", "GET", ""))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("no global policy: status %d", w.Code)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http" // WHY: HTTP 미들웨어 체인 구현을 위해 표준 net/http 사용
 
 	"service-gateway/internal/header" // WHY: X-Fw-Header(TCID 등) 파싱/직렬화/증분 유틸 재사용
+	"service-gateway/internal/logx"   // WHY: 확정된 TCID 를 요청 로그 컨텍스트에 기록
 )
 
 /*
@@ -47,6 +48,8 @@ func FwHeaderTrace(bizCode string, next http.Handler) http.Handler {
 
 		// (3) 요청 헤더 갱신: 이후 핸들러/프록시 호출 시 동일 값 전파
 		r.Header.Set("X-Fw-Header", enhanced)
+		// (3-1) 로그 컨텍스트 TCID 갱신 (logx.Middleware 가 바깥에 있으면 access log 에도 반영)
+		r = r.WithContext(logx.WithTCID(r.Context(), header.Parse(enhanced)["TCID"]))
 
 		// (4) 응답 시 SRNO 증가 처리를 위해 커스텀 writer 준비
 		sw := &fwHeaderWriter{
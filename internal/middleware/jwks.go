@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+JWKS (JSON Web Key Set) 키 저장소
+
+- 로컬 파일(jwks_file) 또는 URL(jwks_url) 에서 적재, refresh 주기로 재적재
+- 적재 실패 시 이전 키 유지 (인증 서버 일시 장애로 전체 401 방지)
+- 모르는 kid 는 키 교체 직후일 수 있으므로 즉시 재적재 (minRefetch 간격으로 제한, 동시 요청은 1회로 합침)
+- 지원: RSA(RS256), EC P-256(ES256), OKP Ed25519(EdDSA)
+*/
+
+const jwksMinRefetch = 30 * time.Second
+
+// JWKS: kid → 공개키
+type JWKS struct {
+	file   string
+	url    string
+	client *http.Client
+	log    *slog.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	refetch   sync.Mutex // 모르는 kid 재적재 직렬화 (동시 요청마다 인증 서버 호출 방지)
+
+	stop chan struct{}
+}
+
+// NewJWKS: 최초 적재는 실패 시 에러 (기동 시점 설정 오류 조기 발견)
+func NewJWKS(file, url string, refresh time.Duration, client *http.Client, logger *slog.Logger) (*JWKS, error) {
+	if (file == "") == (url == "") {
+		return nil, errors.New("jwt: exactly one of jwks_file or jwks_url required")
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	j := &JWKS{file: file, url: url, client: client, log: logger.With("component", "jwks"), stop: make(chan struct{})}
+	if err := j.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+	if refresh > 0 {
+		go j.loop(refresh)
+	}
+	return j, nil
+}
+
+// StaticJWKS: 고정 키 (테스트 / 로컬 생성 키)
+func StaticJWKS(keys map[string]crypto.PublicKey) *JWKS {
+	return &JWKS{keys: keys, fetchedAt: time.Now(), stop: make(chan struct{})}
+}
+
+func (j *JWKS) loop(every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-j.stop:
+			return
+		case <-t.C:
+			if err := j.Refresh(context.Background()); err != nil {
+				j.log.Warn("refresh failed, keep previous keys", "err", err)
+			}
+		}
+	}
+}
+
+// Close: 주기 재적재 중지
+func (j *JWKS) Close() {
+	select {
+	case <-j.stop:
+	default:
+		close(j.stop)
+	}
+}
+
+// Key: kid 조회, 없으면 (마지막 적재 후 minRefetch 경과 시) 1회 재적재 — 동시 요청은 먼저 온 재적재 결과를 공유
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, bool) {
+	j.mu.RLock()
+	k, ok := j.keys[kid]
+	seen := j.fetchedAt
+	j.mu.RUnlock()
+	if ok || time.Since(seen) < jwksMinRefetch || (j.file == "" && j.url == "") {
+		return k, ok
+	}
+	j.refetch.Lock()
+	j.mu.RLock()
+	k, ok = j.keys[kid]
+	done := ok || !j.fetchedAt.Equal(seen) // 대기 중 다른 요청이 이미 재적재
+	j.mu.RUnlock()
+	if done {
+		j.refetch.Unlock()
+		return k, ok
+	}
+	if err := j.Refresh(ctx); err != nil {
+		j.log.WarnContext(ctx, "refetch for unknown kid failed", "kid", kid, "err", err)
+	}
+	j.refetch.Unlock()
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+	k, ok = j.keys[kid]
+	return k, ok
+}
+
+// Refresh: 파일/URL 재적재 (실패 시 이전 키 유지)
+func (j *JWKS) Refresh(ctx context.Context) error {
+	raw, err := j.read(ctx)
+	if err == nil {
+		var keys map[string]crypto.PublicKey
+		if keys, err = parseJWKS(raw); err == nil {
+			j.mu.Lock()
+			j.keys = keys
+			j.fetchedAt = time.Now()
+			j.mu.Unlock()
+			return nil
+		}
+	}
+	j.mu.Lock()
+	j.fetchedAt = time.Now() // 실패도 기록 → 모르는 kid 폭주 시 재조회 폭주 방지
+	j.mu.Unlock()
+	return err
+}
+
+func (j *JWKS) read(ctx context.Context) ([]byte, error) {
+	if j.file != "" {
+		return os.ReadFile(j.file)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks: GET %s: %s", j.url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS: 서명용(use 미지정 또는 sig) 키만, 지원하지 않는 키는 건너뜀
+func parseJWKS(raw []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+	out := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		out[k.Kid] = pub
+	}
+	if len(out) == 0 {
+		return nil, errors.New("jwks: no usable signing keys")
+	}
+	return out, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err1 := b64big(k.N)
+		e, err2 := b64big(k.E)
+		if err1 != nil || err2 != nil || !e.IsInt64() {
+			return nil, errors.New("jwks: bad RSA key")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("jwks: unsupported curve %q", k.Crv)
+		}
+		x, err1 := b64big(k.X)
+		y, err2 := b64big(k.Y)
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("jwks: bad EC key")
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !pub.Curve.IsOnCurve(x, y) {
+			return nil, errors.New("jwks: EC point not on curve")
+		}
+		return pub, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("jwks: unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwks: bad Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("jwks: unsupported kty %q", k.Kty)
+}
+
+func b64big(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("bad base64url integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
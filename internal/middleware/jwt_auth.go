@@ -0,0 +1,338 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/model"
+	"service-gateway/internal/router"
+	"slices"
+	"strings"
+	"time"
+)
+
+/*
+JWTAuth 미들웨어 (Authorization: Bearer <JWT>)
+
+WHY:
+- 외부 IdP 가 발급한 액세스 토큰을 게이트웨이에서 검증해 업무 서비스마다 중복 구현하지 않도록 함.
+- 검증된 클레임 일부(sub 등)를 업스트림 헤더로 전달 → 업무 서비스는 헤더만 신뢰.
+
+검증:
+- alg: RS256 / ES256 / EdDSA (none, HS* 거부 → 공개키로 HMAC 위조 차단), kid 로 JWKS 키 선택
+- iss 일치, aud 중 하나 일치 (설정 시), exp 필수, nbf (leeway 허용)
+- 토큰 없음: required=false 면 통과 (라우트에 scopes/claims 가 있으면 AuthorizeRoute 에서 401)
+
+라우트 권한 (router.RouteOptions):
+- Scopes: scope(공백 구분 문자열) 또는 scp(배열/문자열) 에 모두 포함 → 부족 시 403(15)
+- Claims: 클레임 값 일치 (배열 클레임은 포함)
+- 라우트 매칭 후 AuthorizeRoute(ctx, rt.Options) 호출 (IPFilter 도 함께 판정 → 403(26))
+
+전달 헤더:
+- forward_claims 의 헤더는 클라이언트가 보낸 값을 먼저 삭제 (위조 방지)
+*/
+
+var (
+	errNoToken      = errors.New("jwt: bearer token required")
+	errTokenFormat  = errors.New("jwt: malformed token")
+	errTokenAlg     = errors.New("jwt: unsupported alg")
+	errTokenKey     = errors.New("jwt: unknown key")
+	errTokenSig     = errors.New("jwt: invalid signature")
+	errTokenExpired = errors.New("jwt: token expired")
+	errTokenNbf     = errors.New("jwt: token not yet valid")
+	errTokenIss     = errors.New("jwt: issuer mismatch")
+	errTokenAud     = errors.New("jwt: audience mismatch")
+)
+
+// Claims: 검증된 JWT 페이로드
+type Claims map[string]any
+
+// JWTConfig: gateway.yaml jwt 블록
+type JWTConfig struct {
+	Keys          *JWKS
+	Issuer        string
+	Audience      []string
+	Leeway        time.Duration
+	Required      bool              // true 면 토큰 없는 요청 401
+	ForwardClaims map[string]string // 클레임 → 업스트림 헤더 (예: sub → X-Auth-Subject)
+	Logger        *slog.Logger
+}
+
+type JWTAuth struct {
+	cfg JWTConfig
+	log *slog.Logger
+	now func() time.Time
+}
+
+func NewJWTAuth(cfg JWTConfig) (*JWTAuth, error) {
+	if cfg.Keys == nil {
+		return nil, errors.New("jwt: key set required")
+	}
+	lg := cfg.Logger
+	if lg == nil {
+		lg = slog.Default()
+	}
+	return &JWTAuth{cfg: cfg, log: lg.With("component", "jwt"), now: time.Now}, nil
+}
+
+type claimsKey struct{}
+type forwardedKey struct{}
+
+// ClaimsFrom: Middleware 가 검증한 클레임 (토큰 없으면 nil)
+func ClaimsFrom(ctx context.Context) Claims {
+	c, _ := ctx.Value(claimsKey{}).(Claims)
+	return c
+}
+
+// ForwardedClaimHeaders: 업스트림으로 전달할 클레임 헤더 (헤더를 선별 복사하는 핸들러용)
+func ForwardedClaimHeaders(ctx context.Context) http.Header {
+	h, _ := ctx.Value(forwardedKey{}).(http.Header)
+	return h
+}
+
+func (a *JWTAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 클라이언트가 보낸 전달 헤더는 항상 제거
+		for _, h := range a.cfg.ForwardClaims {
+			r.Header.Del(h)
+		}
+
+		tok, ok := BearerToken(r)
+		if !ok {
+			if a.cfg.Required {
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeUnauthorized, errNoToken))
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := a.Verify(r.Context(), tok)
+		if err != nil {
+			a.log.InfoContext(r.Context(), "token rejected", "err", err)
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeUnauthorized, err))
+			return
+		}
+
+		fwd := http.Header{}
+		for claim, h := range a.cfg.ForwardClaims {
+			if v, ok := claimString(claims[claim]); ok {
+				r.Header.Set(h, v)
+				fwd.Set(h, v)
+			}
+		}
+		ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+		ctx = context.WithValue(ctx, forwardedKey{}, fwd)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Verify: 서명 + 표준 클레임 검증
+func (a *JWTAuth) Verify(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errTokenFormat
+	}
+	var hdr struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &hdr); err != nil {
+		return nil, errTokenFormat
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errTokenFormat
+	}
+	key, ok := a.cfg.Keys.Key(ctx, hdr.Kid)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errTokenKey, hdr.Kid)
+	}
+	if err := verifySignature(hdr.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, errTokenFormat
+	}
+	if err := a.checkStandard(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (a *JWTAuth) checkStandard(c Claims) error {
+	now := a.now()
+	exp, ok := numericDate(c["exp"])
+	if !ok {
+		return errTokenFormat
+	}
+	if now.After(exp.Add(a.cfg.Leeway)) {
+		return errTokenExpired
+	}
+	if nbf, ok := numericDate(c["nbf"]); ok && now.Add(a.cfg.Leeway).Before(nbf) {
+		return errTokenNbf
+	}
+	if a.cfg.Issuer != "" {
+		if iss, _ := c["iss"].(string); iss != a.cfg.Issuer {
+			return errTokenIss
+		}
+	}
+	if len(a.cfg.Audience) > 0 {
+		aud := claimList(c["aud"])
+		if !slices.ContainsFunc(a.cfg.Audience, func(s string) bool { return slices.Contains(aud, s) }) {
+			return errTokenAud
+		}
+	}
+	return nil
+}
+
+// AuthorizeRoute: 라우트별 IP 허용/차단, scope / 클레임 요구사항 (요구사항 없으면 nil)
+func AuthorizeRoute(ctx context.Context, opts router.RouteOptions) *httpx.Error {
+	if !opts.IPFilter.Allowed(ClientIPFrom(ctx)) {
+		return httpx.Err(model.ErrCodeIPForbidden, fmt.Errorf("ipfilter: %s not allowed", ClientIPFrom(ctx)))
+	}
+	if len(opts.Scopes) == 0 && len(opts.Claims) == 0 {
+		return nil
+	}
+	c := ClaimsFrom(ctx)
+	if c == nil {
+		return httpx.Err(model.ErrCodeUnauthorized, errNoToken)
+	}
+	if len(opts.Scopes) > 0 {
+		granted := append(strings.Fields(stringOr(c["scope"])), claimList(c["scp"])...)
+		for _, s := range opts.Scopes {
+			if !slices.Contains(granted, s) {
+				return httpx.Err(model.ErrCodeApiForbidden, fmt.Errorf("jwt: missing scope %q", s))
+			}
+		}
+	}
+	for name, want := range opts.Claims {
+		if !slices.Contains(claimList(c[name]), want) {
+			return httpx.Err(model.ErrCodeApiForbidden, fmt.Errorf("jwt: claim %q mismatch", name))
+		}
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signing string, sig []byte) error {
+	digest := sha256.Sum256([]byte(signing))
+	switch alg {
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errTokenAlg
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) != nil {
+			return errTokenSig
+		}
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errTokenAlg
+		}
+		if len(sig) != 64 {
+			return errTokenSig
+		}
+		rr, ss := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest[:], rr, ss) {
+			return errTokenSig
+		}
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return errTokenAlg
+		}
+		if !ed25519.Verify(pub, []byte(signing), sig) {
+			return errTokenSig
+		}
+	default:
+		return fmt.Errorf("%w %q", errTokenAlg, alg)
+	}
+	return nil
+}
+
+// BearerToken: Authorization: Bearer <토큰> (스킴 대소문자 무시, gRPC authorization 메타데이터도 같은 규칙)
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, tok, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	tok = strings.TrimSpace(tok)
+	return tok, tok != ""
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func numericDate(v any) (time.Time, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// claimList: 문자열 / 배열 클레임 → 문자열 목록
+func claimList(v any) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			if s, ok := claimString(e); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	if s, ok := claimString(v); ok {
+		return []string{s}
+	}
+	return nil
+}
+
+// claimString: 헤더로 보낼 수 있는 단일 값 (배열은 공백 구분)
+func claimString(v any) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case json.Number:
+		return t.String(), true
+	case bool:
+		return fmt.Sprint(t), true
+	case []any:
+		return strings.Join(claimList(t), " "), len(t) > 0
+	}
+	return "", false
+}
+
+func stringOr(v any) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,465 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/model"
+	"service-gateway/internal/router"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var jwtNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+	ed  ed25519.PrivateKey
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rk, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, dk, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKeys{rsa: rk, ec: ek, ed: dk}
+}
+
+func (k testKeys) jwks() *JWKS {
+	return StaticJWKS(map[string]crypto.PublicKey{
+		"rsa": &k.rsa.PublicKey,
+		"ec":  &k.ec.PublicKey,
+		"ed":  k.ed.Public(),
+	})
+}
+
+func b64json(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signToken: alg 에 맞는 방식으로 서명 (key 는 개인키, HS256 은 []byte 비밀값)
+func signToken(t *testing.T, alg, kid string, key any, claims map[string]any) string {
+	t.Helper()
+	signing := b64json(t, map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + b64json(t, claims)
+	digest := sha256.Sum256([]byte(signing))
+	var sig []byte
+	var err error
+	switch alg {
+	case "RS256":
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, digest[:])
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, key.(*ecdsa.PrivateKey), digest[:])
+		if err == nil {
+			sig = make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+		}
+	case "EdDSA":
+		sig = ed25519.Sign(key.(ed25519.PrivateKey), []byte(signing))
+	case "HS256":
+		m := hmac.New(sha256.New, key.([]byte))
+		m.Write([]byte(signing))
+		sig = m.Sum(nil)
+	case "none":
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func validClaims() map[string]any {
+	return map[string]any{
+		"iss":   "https://idp.example.com",
+		"aud":   []string{"other", "gateway"},
+		"sub":   "user-1",
+		"exp":   jwtNow.Add(time.Minute).Unix(),
+		"scope": "read write",
+	}
+}
+
+func newTestAuth(t *testing.T, keys *JWKS, mutate func(*JWTConfig)) *JWTAuth {
+	t.Helper()
+	cfg := JWTConfig{
+		Keys:     keys,
+		Issuer:   "https://idp.example.com",
+		Audience: []string{"gateway"},
+		Leeway:   30 * time.Second,
+	}
+	if mutate != nil {
+		mutate(&cfg)
+	}
+	a, err := NewJWTAuth(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.now = func() time.Time { return jwtNow }
+	return a
+}
+
+func TestVerifyAlgorithms(t *testing.T) {
+	k := newTestKeys(t)
+	a := newTestAuth(t, k.jwks(), nil)
+	for _, c := range []struct {
+		alg, kid string
+		key      any
+	}{
+		{"RS256", "rsa", k.rsa},
+		{"ES256", "ec", k.ec},
+		{"EdDSA", "ed", k.ed},
+	} {
+		claims, err := a.Verify(context.Background(), signToken(t, c.alg, c.kid, c.key, validClaims()))
+		if err != nil {
+			t.Errorf("%s: %v", c.alg, err)
+			continue
+		}
+		if claims["sub"] != "user-1" {
+			t.Errorf("%s: claims = %v", c.alg, claims)
+		}
+	}
+}
+
+func TestVerifyRejected(t *testing.T) {
+	k := newTestKeys(t)
+	a := newTestAuth(t, k.jwks(), nil)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaDER, _ := x509.MarshalPKIXPublicKey(&k.rsa.PublicKey)
+
+	valid := signToken(t, "RS256", "rsa", k.rsa, validClaims())
+	tampered := valid[:len(valid)-4] + "AAAA"
+
+	cases := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"alg none", signToken(t, "none", "rsa", nil, validClaims()), errTokenAlg},
+		{"HS256 with public key as secret", signToken(t, "HS256", "rsa", rsaDER, validClaims()), errTokenAlg},
+		{"RS256 header on EC key", signToken(t, "RS256", "ec", k.rsa, validClaims()), errTokenAlg},
+		{"ES256 header on Ed25519 key", signToken(t, "ES256", "ed", k.ec, validClaims()), errTokenAlg},
+		{"EdDSA header on RSA key", signToken(t, "EdDSA", "rsa", k.ed, validClaims()), errTokenAlg},
+		{"signed by other key", signToken(t, "ES256", "ec", other, validClaims()), errTokenSig},
+		{"tampered signature", tampered, errTokenSig},
+		{"unknown kid", signToken(t, "RS256", "missing", k.rsa, validClaims()), errTokenKey},
+		{"two segments", "a.b", errTokenFormat},
+		{"bad header", "!!.e30.AA", errTokenFormat},
+	}
+	for _, c := range cases {
+		if _, err := a.Verify(context.Background(), c.token); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestVerifyStandardClaims(t *testing.T) {
+	k := newTestKeys(t)
+	a := newTestAuth(t, k.jwks(), nil)
+
+	cases := []struct {
+		name   string
+		mutate func(map[string]any)
+		want   error
+	}{
+		{"valid", func(map[string]any) {}, nil},
+		{"exp within leeway", func(c map[string]any) { c["exp"] = jwtNow.Add(-20 * time.Second).Unix() }, nil},
+		{"exp past leeway", func(c map[string]any) { c["exp"] = jwtNow.Add(-40 * time.Second).Unix() }, errTokenExpired},
+		{"exp missing", func(c map[string]any) { delete(c, "exp") }, errTokenFormat},
+		{"exp string", func(c map[string]any) { c["exp"] = "tomorrow" }, errTokenFormat},
+		{"nbf within leeway", func(c map[string]any) { c["nbf"] = jwtNow.Add(20 * time.Second).Unix() }, nil},
+		{"nbf past leeway", func(c map[string]any) { c["nbf"] = jwtNow.Add(40 * time.Second).Unix() }, errTokenNbf},
+		{"iss mismatch", func(c map[string]any) { c["iss"] = "https://evil.example.com" }, errTokenIss},
+		{"iss missing", func(c map[string]any) { delete(c, "iss") }, errTokenIss},
+		{"aud string", func(c map[string]any) { c["aud"] = "gateway" }, nil},
+		{"aud mismatch", func(c map[string]any) { c["aud"] = []string{"other"} }, errTokenAud},
+		{"aud missing", func(c map[string]any) { delete(c, "aud") }, errTokenAud},
+	}
+	for _, c := range cases {
+		claims := validClaims()
+		c.mutate(claims)
+		_, err := a.Verify(context.Background(), signToken(t, "EdDSA", "ed", k.ed, claims))
+		if !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	// issuer / audience 미설정이면 검사하지 않음
+	open := newTestAuth(t, k.jwks(), func(c *JWTConfig) { c.Issuer, c.Audience = "", nil })
+	claims := validClaims()
+	delete(claims, "iss")
+	delete(claims, "aud")
+	if _, err := open.Verify(context.Background(), signToken(t, "EdDSA", "ed", k.ed, claims)); err != nil {
+		t.Errorf("no iss/aud config: %v", err)
+	}
+}
+
+func writeJWKS(t *testing.T, path, kid string, pub ed25519.PublicKey) {
+	t.Helper()
+	set := map[string]any{"keys": []map[string]string{
+		{"kty": "OKP", "crv": "Ed25519", "kid": kid, "use": "sig", "x": base64.RawURLEncoding.EncodeToString(pub)},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}, // 암호화용 키는 무시
+	}}
+	b, _ := json.Marshal(set)
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWKSUnknownKidRefetch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	pub1, priv1, _ := ed25519.GenerateKey(rand.Reader)
+	pub2, priv2, _ := ed25519.GenerateKey(rand.Reader)
+	writeJWKS(t, path, "k1", pub1)
+
+	keys, err := NewJWKS(path, "", 0, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keys.Close()
+	if _, ok := keys.Key(context.Background(), "enc"); ok {
+		t.Error("use=enc key loaded")
+	}
+	a := newTestAuth(t, keys, nil)
+	if _, err := a.Verify(context.Background(), signToken(t, "EdDSA", "k1", priv1, validClaims())); err != nil {
+		t.Fatal(err)
+	}
+
+	// 키 교체: 직전 적재 후 minRefetch 이내면 재적재하지 않음
+	writeJWKS(t, path, "k2", pub2)
+	rotated := signToken(t, "EdDSA", "k2", priv2, validClaims())
+	if _, err := a.Verify(context.Background(), rotated); !errors.Is(err, errTokenKey) {
+		t.Fatalf("within minRefetch: err = %v, want unknown key", err)
+	}
+
+	// minRefetch 경과 후 모르는 kid → 1회 재적재
+	keys.mu.Lock()
+	keys.fetchedAt = time.Now().Add(-jwksMinRefetch)
+	keys.mu.Unlock()
+	if _, err := a.Verify(context.Background(), rotated); err != nil {
+		t.Fatalf("after refetch: %v", err)
+	}
+	if _, ok := keys.Key(context.Background(), "k1"); ok {
+		t.Error("old key still present after rotation")
+	}
+
+	// 재적재 실패 시 이전 키 유지
+	if err := os.WriteFile(path, []byte("{broken"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.Refresh(context.Background()); err == nil {
+		t.Error("broken jwks accepted")
+	}
+	if _, err := a.Verify(context.Background(), rotated); err != nil {
+		t.Errorf("previous keys dropped on failed refresh: %v", err)
+	}
+}
+
+// 모르는 kid 가 동시에 몰려도 인증 서버 재조회는 1회
+func TestJWKSConcurrentRefetch(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, "k1", pub)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) > 1 {
+			time.Sleep(50 * time.Millisecond) // 재조회 중 나머지 요청이 도착하도록
+		}
+		w.Write(raw)
+	}))
+	defer srv.Close()
+
+	keys, err := NewJWKS("", srv.URL, 0, srv.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer keys.Close()
+	keys.mu.Lock()
+	keys.fetchedAt = time.Now().Add(-jwksMinRefetch)
+	keys.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := keys.Key(context.Background(), "k2"); ok {
+				t.Error("unknown kid resolved")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := hits.Load(); got != 2 {
+		t.Errorf("jwks fetches = %d, want 2 (initial + one refetch)", got)
+	}
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	k := newTestKeys(t)
+	a := newTestAuth(t, k.jwks(), func(c *JWTConfig) {
+		c.ForwardClaims = map[string]string{"sub": "X-Auth-Subject", "scope": "X-Auth-Scope", "missing": "X-Auth-Missing"}
+	})
+
+	var seen *http.Request
+	h := a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { seen = r }))
+	call := func(auth string) *httptest.ResponseRecorder {
+		seen = nil
+		r := httptest.NewRequest(http.MethodPost, "/gateway", nil)
+		r.Header.Set("X-Auth-Subject", "forged")
+		r.Header.Set("X-Auth-Missing", "forged")
+		if auth != "" {
+			r.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// 유효 토큰: 클레임 헤더 덮어쓰기, 토큰에 없는 클레임 헤더는 삭제
+	w := call("bearer " + signToken(t, "RS256", "rsa", k.rsa, validClaims()))
+	if seen == nil {
+		t.Fatalf("valid token rejected: %d %s", w.Code, w.Body)
+	}
+	if got := seen.Header.Get("X-Auth-Subject"); got != "user-1" {
+		t.Errorf("X-Auth-Subject = %q", got)
+	}
+	if got := seen.Header.Get("X-Auth-Scope"); got != "read write" {
+		t.Errorf("X-Auth-Scope = %q", got)
+	}
+	if _, ok := seen.Header["X-Auth-Missing"]; ok {
+		t.Error("client-sent X-Auth-Missing not stripped")
+	}
+	if got := ForwardedClaimHeaders(seen.Context()).Get("X-Auth-Subject"); got != "user-1" {
+		t.Errorf("ForwardedClaimHeaders = %q", got)
+	}
+	if ClaimsFrom(seen.Context())["sub"] != "user-1" {
+		t.Error("claims not in context")
+	}
+
+	// 토큰 없음 (required=false): 통과하되 위조 헤더는 삭제
+	call("")
+	if seen == nil {
+		t.Fatal("anonymous request rejected")
+	}
+	if seen.Header.Get("X-Auth-Subject") != "" || ClaimsFrom(seen.Context()) != nil {
+		t.Error("anonymous request kept forged header or claims")
+	}
+
+	// Bearer 가 아닌 스킴은 토큰 없음과 같음
+	if call("Basic dXNlcjpwYXNz"); seen == nil {
+		t.Error("non-bearer Authorization rejected")
+	}
+
+	// 잘못된 토큰 → 401
+	if w := call("Bearer " + signToken(t, "none", "rsa", nil, validClaims())); w.Code != http.StatusUnauthorized || seen != nil {
+		t.Errorf("alg none: status %d", w.Code)
+	}
+
+	// required=true 면 토큰 없음 401
+	a.cfg.Required = true
+	if w := call(""); w.Code != http.StatusUnauthorized || seen != nil {
+		t.Errorf("required: status %d", w.Code)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	cases := map[string]string{
+		"Bearer abc":    "abc",
+		"bearer abc":    "abc",
+		"BEARER  abc  ": "abc",
+		"Bearer ":       "",
+		"Bearer":        "",
+		"Basic abc":     "",
+		"":              "",
+	}
+	for in, want := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", in)
+		got, ok := BearerToken(r)
+		if got != want || ok != (want != "") {
+			t.Errorf("BearerToken(%q) = %q %v, want %q", in, got, ok, want)
+		}
+	}
+}
+
+func TestAuthorizeRoute(t *testing.T) {
+	withClaims := func(c Claims) context.Context {
+		return context.WithValue(context.Background(), claimsKey{}, c)
+	}
+	deny, err := ipfilter.Parse(nil, []string{"203.0.113.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := Claims{
+		"scope": "read write",
+		"scp":   []any{"admin"},
+		"roles": []any{"ops", "dev"},
+		"tier":  "gold",
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		opts router.RouteOptions
+		want string // 에러 코드, 빈 값이면 허용
+	}{
+		{"no requirements, no token", context.Background(), router.RouteOptions{}, ""},
+		{"scopes without token", context.Background(), router.RouteOptions{Scopes: []string{"read"}}, model.ErrCodeUnauthorized},
+		{"scope from scope + scp", withClaims(claims), router.RouteOptions{Scopes: []string{"read", "admin"}}, ""},
+		{"missing scope", withClaims(claims), router.RouteOptions{Scopes: []string{"read", "delete"}}, model.ErrCodeApiForbidden},
+		{"array claim contains", withClaims(claims), router.RouteOptions{Claims: map[string]string{"roles": "ops"}}, ""},
+		{"string claim equals", withClaims(claims), router.RouteOptions{Claims: map[string]string{"tier": "gold"}}, ""},
+		{"claim mismatch", withClaims(claims), router.RouteOptions{Claims: map[string]string{"tier": "silver"}}, model.ErrCodeApiForbidden},
+		{"claim missing", withClaims(claims), router.RouteOptions{Claims: map[string]string{"dept": "x"}}, model.ErrCodeApiForbidden},
+		{"ip denied before claims", context.WithValue(withClaims(claims), clientIPKey{}, netip.MustParseAddr("203.0.113.9")),
+			router.RouteOptions{IPFilter: deny, Scopes: []string{"read"}}, model.ErrCodeIPForbidden},
+		{"ip allowed", context.WithValue(withClaims(claims), clientIPKey{}, netip.MustParseAddr("198.51.100.9")),
+			router.RouteOptions{IPFilter: deny, Scopes: []string{"read"}}, ""},
+	}
+	for _, c := range cases {
+		err := AuthorizeRoute(c.ctx, c.opts)
+		switch {
+		case c.want == "" && err != nil:
+			t.Errorf("%s: unexpected %s", c.name, err.Code)
+		case c.want != "" && (err == nil || err.Code != c.want):
+			t.Errorf("%s: got %v, want code %s", c.name, err, c.want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"net"
 	"net/http"
+	"net/netip"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/logx"
 	"strings"
 )
 
@@ -15,57 +19,158 @@ WHY (왜 필요한가):
 2. 프로토콜/호스트 정보 전달: TLS 종료 혹은 중간 프록시 영향으로 r.TLS가 nil일 수 있어
    X-Forwarded-Proto, X-Forwarded-Host 를 명시적으로 보강.
 3. 표준화: 이후 로그/추적 로직이 별도 파싱 없이 헤더만 신뢰하도록 통일.
+4. 위조 방지: 이전에는 클라이언트가 보낸 X-Forwarded-* 를 그대로 믿어 IP 차단 정책을 우회할 수 있었음
+   → 직전 홉(RemoteAddr)이 trusted 프록시일 때만 전달 헤더를 신뢰.
 
 정책:
-- X-Forwarded-For:
-  - 기존 값 존재 시 "기존, 새IP" 형태 append (중복 방지 위해 간단히 추가; 추가 필터가 필요하면 후속 단계에서 정규화 가능)
-  - RemoteAddr 파싱 실패/빈 값이면 미추가
-- X-Forwarded-Proto:
-  - 없을 때만 세팅 (이미 상위 프록시가 넣은 값 우선)
-  - TLS 존재 여부로 http / https
-- X-Forwarded-Host:
-  - 없고 r.Host 있으면 세팅
-
-보안 주의:
-- 외부(신뢰 안 되는) 직접 트래픽이 이 레이어까지 들어온다면,
-  클라이언트가 임의로 조작한 X-Forwarded-* 헤더가 이미 존재할 수 있음.
-  → “신뢰 경계” 앞단에서 초기화/삭제 후 전달하는 정책 권장.
+- 실제 클라이언트 IP:
+  - RemoteAddr 가 trusted 가 아니면 RemoteAddr 자체
+  - trusted 면 Forwarded(RFC 7239, 있으면 우선) 또는 X-Forwarded-For 를 오른쪽부터 훑어
+    trusted 가 아닌 첫 주소 (모두 trusted 면 가장 왼쪽)
+  - ClientIPFrom(ctx) / logx(access log client_ip) 로 제공, IP 허용/차단 판정 기준
+- 신뢰하지 않는 홉이 보낸 X-Forwarded-* / Forwarded 는 삭제 후 새로 작성
+- X-Forwarded-For / Forwarded: 기존 값(신뢰 시) 뒤에 RemoteAddr 추가
+- X-Forwarded-Proto / Host: 신뢰 시 상위 값 유지, 없으면 TLS 여부 / r.Host 로 보강
 */
 
-// ProxyHeaders: 위 설명대로 X-Forwarded-* 헤더 보강
-func ProxyHeaders(next http.Handler) http.Handler {
+type clientIPKey struct{}
+
+// ClientIPFrom: ProxyHeaders 가 결정한 실제 클라이언트 IP (없으면 zero Addr)
+func ClientIPFrom(ctx context.Context) netip.Addr {
+	ip, _ := ctx.Value(clientIPKey{}).(netip.Addr)
+	return ip
+}
+
+// ProxyHeaders: 위 설명대로 X-Forwarded-* 헤더 보강 (trusted: 신뢰할 프록시 CIDR, 비우면 전달 헤더 불신)
+func ProxyHeaders(next http.Handler, trusted []netip.Prefix) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-		// (1) 클라이언트 IP 추출 (host:port → host)
-		ip := clientIP(r.RemoteAddr)
-		if ip != "" {
-			// (2) 기존 XFF 존재 시 append, 없으면 신규 세팅
+		// (1) 직전 홉 IP 추출 (host:port → host)
+		peer := clientIP(r.RemoteAddr)
+		peerAddr, _ := netip.ParseAddr(peer)
+		peerAddr = peerAddr.Unmap()
+		fromProxy := peerAddr.IsValid() && ipfilter.Contains(trusted, peerAddr)
+
+		// (2) 신뢰하지 않는 홉의 전달 헤더는 버림 (위조 방지)
+		if !fromProxy {
+			for _, h := range []string{"X-Forwarded-For", "X-Forwarded-Proto", "X-Forwarded-Host", "Forwarded"} {
+				r.Header.Del(h)
+			}
+		}
+
+		// (3) 실제 클라이언트 IP 결정 (헤더 추가 전의 체인 기준)
+		client := peerAddr
+		if fromProxy {
+			client = resolveClientIP(r.Header, peerAddr, trusted)
+		}
+
+		proto := "http"
+		if r.TLS != nil {
+			proto = "https"
+		}
+
+		if peer != "" {
+			// (4) 기존 XFF 존재 시 append, 없으면 신규 세팅
 			if prior := r.Header.Get("X-Forwarded-For"); prior != "" {
-				r.Header.Set("X-Forwarded-For", prior+", "+ip)
+				r.Header.Set("X-Forwarded-For", prior+", "+peer)
+			} else {
+				r.Header.Set("X-Forwarded-For", peer)
+			}
+			// (5) RFC 7239 Forwarded 요소 추가
+			elem := "for=" + forwardedNode(peerAddr, peer) + ";proto=" + proto
+			if r.Host != "" {
+				elem += ";host=" + quoteIfNeeded(r.Host)
+			}
+			if prior := r.Header.Get("Forwarded"); prior != "" {
+				r.Header.Set("Forwarded", prior+", "+elem)
 			} else {
-				r.Header.Set("X-Forwarded-For", ip)
+				r.Header.Set("Forwarded", elem)
 			}
 		}
 
-		// (3) 프로토콜 보강 (이미 있으면 상위 값을 존중)
+		// (6) 프로토콜 보강 (신뢰하는 상위 값 존중)
 		if r.Header.Get("X-Forwarded-Proto") == "" {
-			if r.TLS != nil {
-				r.Header.Set("X-Forwarded-Proto", "https")
-			} else {
-				r.Header.Set("X-Forwarded-Proto", "http")
-			}
+			r.Header.Set("X-Forwarded-Proto", proto)
 		}
 
-		// (4) 호스트 보강 (이미 있으면 상위 프록시 값 유지)
+		// (7) 호스트 보강 (신뢰하는 상위 프록시 값 유지)
 		if r.Header.Get("X-Forwarded-Host") == "" && r.Host != "" {
 			r.Header.Set("X-Forwarded-Host", r.Host)
 		}
 
-		// (5) 다음 핸들러로 위임
+		// (8) 다음 핸들러로 위임
+		if client.IsValid() {
+			logx.SetClientIP(r.Context(), client.String())
+			r = r.WithContext(context.WithValue(r.Context(), clientIPKey{}, client))
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
+// resolveClientIP: 전달 체인을 오른쪽부터 훑어 trusted 가 아닌 첫 주소
+func resolveClientIP(h http.Header, peer netip.Addr, trusted []netip.Prefix) netip.Addr {
+	chain := forwardedFor(h.Values("Forwarded"))
+	if len(chain) == 0 {
+		for _, v := range h.Values("X-Forwarded-For") {
+			for _, s := range strings.Split(v, ",") {
+				chain = append(chain, strings.TrimSpace(s))
+			}
+		}
+	}
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		a, err := netip.ParseAddr(chain[i])
+		if err != nil {
+			break // 형식 오류 / unknown / 난독화 식별자: 그 앞은 신뢰 불가
+		}
+		client = a.Unmap()
+		if !ipfilter.Contains(trusted, client) {
+			break
+		}
+	}
+	return client
+}
+
+// forwardedFor: Forwarded 헤더의 for= 값 목록 (포트/대괄호/따옴표 제거)
+func forwardedFor(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, elem := range strings.Split(v, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				k, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(k, "for") {
+					continue
+				}
+				val = strings.Trim(val, `"`)
+				if strings.HasPrefix(val, "[") { // "[2001:db8::1]:4711"
+					if end := strings.Index(val, "]"); end > 0 {
+						val = val[1:end]
+					}
+				} else if host, _, err := net.SplitHostPort(val); err == nil {
+					val = host
+				}
+				out = append(out, val)
+			}
+		}
+	}
+	return out
+}
+
+// forwardedNode: RFC 7239 node (IPv6 는 "[addr]" 따옴표 필요)
+func forwardedNode(a netip.Addr, raw string) string {
+	if a.IsValid() && a.Is6() {
+		return `"[` + a.String() + `]"`
+	}
+	return quoteIfNeeded(raw)
+}
+
+func quoteIfNeeded(s string) string {
+	if strings.ContainsAny(s, ":[]\" ,;") {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	return s
+}
+
 // clientIP: "IP:Port" 형태 RemoteAddr → IP 부분만 추출
 func clientIP(remoteAddr string) string {
 	if remoteAddr == "" {
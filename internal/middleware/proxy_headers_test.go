@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"service-gateway/internal/ipfilter"
+	"testing"
+)
+
+func mustPrefixes(t *testing.T, items ...string) []netip.Prefix {
+	t.Helper()
+	p, err := ipfilter.ParsePrefixes(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := mustPrefixes(t, "10.0.0.0/8", "fd00::/8")
+	peer := netip.MustParseAddr("10.0.0.2")
+
+	cases := []struct {
+		name      string
+		xff       []string
+		forwarded []string
+		want      string
+	}{
+		{"no headers", nil, nil, "10.0.0.2"},
+		{"single hop", []string{"198.51.100.7"}, nil, "198.51.100.7"},
+		{"trusted chain", []string{"198.51.100.7, 10.0.0.3"}, nil, "198.51.100.7"},
+		{"forged leftmost ignored", []string{"6.6.6.6, 198.51.100.7, 10.0.0.3"}, nil, "198.51.100.7"},
+		{"all trusted → leftmost", []string{"10.0.0.9, 10.0.0.3"}, nil, "10.0.0.9"},
+		{"multiple XFF lines", []string{"198.51.100.7", "10.0.0.3"}, nil, "198.51.100.7"},
+		{"IPv4-mapped", []string{"::ffff:198.51.100.7"}, nil, "198.51.100.7"},
+		{"garbage stops walk", []string{"198.51.100.7, not-an-ip, 10.0.0.3"}, nil, "10.0.0.3"},
+		{"garbage at edge", []string{"198.51.100.7, not-an-ip"}, nil, "10.0.0.2"},
+
+		{"forwarded IPv4 with port", nil, []string{`for="198.51.100.7:4711";proto=https`}, "198.51.100.7"},
+		{"forwarded IPv6", nil, []string{`for="[2001:db8::1]:4711"`}, "2001:db8::1"},
+		{"forwarded IPv6 no port", nil, []string{`for="[2001:db8::1]"`}, "2001:db8::1"},
+		{"forwarded trusted IPv6 hop", nil, []string{`for="[2001:db8::1]", for="[fd00::5]"`}, "2001:db8::1"},
+		{"forwarded case-insensitive key", nil, []string{`proto=https;For=198.51.100.7`}, "198.51.100.7"},
+		{"forwarded unknown before client", nil, []string{`for=unknown, for=198.51.100.7`}, "198.51.100.7"},
+		{"forwarded unknown at edge", nil, []string{`for=198.51.100.7, for=unknown`}, "10.0.0.2"},
+		{"forwarded obfuscated", nil, []string{`for=198.51.100.7, for=_hidden, for=10.0.0.3`}, "10.0.0.3"},
+		{"forwarded multiple lines", nil, []string{`for=198.51.100.7`, `for=10.0.0.3`}, "198.51.100.7"},
+
+		{"forwarded wins over XFF", []string{"6.6.6.6"}, []string{`for=198.51.100.7`}, "198.51.100.7"},
+		{"forwarded without for= → XFF", []string{"198.51.100.7"}, []string{`proto=https;host=example.com`}, "198.51.100.7"},
+	}
+	for _, c := range cases {
+		h := http.Header{}
+		for _, v := range c.xff {
+			h.Add("X-Forwarded-For", v)
+		}
+		for _, v := range c.forwarded {
+			h.Add("Forwarded", v)
+		}
+		if got := resolveClientIP(h, peer, trusted); got.String() != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestForwardedFor(t *testing.T) {
+	got := forwardedFor([]string{
+		`for=192.0.2.43, for="[2001:db8:cafe::17]:4711"`,
+		`proto=http;for="198.51.100.17:80";by=203.0.113.60`,
+		`for=unknown;host=example.com, for="_gazonk"`,
+		`by=10.0.0.1`,
+	})
+	want := []string{"192.0.2.43", "2001:db8:cafe::17", "198.51.100.17", "unknown", "_gazonk"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+// serve: ProxyHeaders 통과 후 다음 핸들러가 본 요청
+func serve(t *testing.T, trusted []netip.Prefix, r *http.Request) *http.Request {
+	t.Helper()
+	var seen *http.Request
+	ProxyHeaders(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) { seen = r }), trusted).
+		ServeHTTP(httptest.NewRecorder(), r)
+	return seen
+}
+
+func TestProxyHeadersUntrustedPeer(t *testing.T) {
+	trusted := mustPrefixes(t, "10.0.0.0/8")
+	r := httptest.NewRequest(http.MethodGet, "http://gw.example.com/x", nil)
+	r.RemoteAddr = "203.0.113.5:51000"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	r.Header.Set("Forwarded", "for=1.2.3.4;proto=https")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "evil.example.com")
+
+	seen := serve(t, trusted, r)
+	if got := ClientIPFrom(seen.Context()); got.String() != "203.0.113.5" {
+		t.Errorf("client = %s, want peer", got)
+	}
+	want := map[string]string{
+		"X-Forwarded-For":   "203.0.113.5",
+		"Forwarded":         "for=203.0.113.5;proto=http;host=gw.example.com",
+		"X-Forwarded-Proto": "http",
+		"X-Forwarded-Host":  "gw.example.com",
+	}
+	for k, v := range want {
+		if got := seen.Header.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestProxyHeadersNoTrustedProxies(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://gw.example.com/x", nil)
+	r.RemoteAddr = "10.0.0.2:51000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+
+	seen := serve(t, nil, r)
+	if got := ClientIPFrom(seen.Context()); got.String() != "10.0.0.2" {
+		t.Errorf("client = %s, want peer (trusted_proxies empty)", got)
+	}
+	if got := seen.Header.Get("X-Forwarded-For"); got != "10.0.0.2" {
+		t.Errorf("XFF = %q", got)
+	}
+}
+
+func TestProxyHeadersTrustedChain(t *testing.T) {
+	trusted := mustPrefixes(t, "10.0.0.0/8")
+	r := httptest.NewRequest(http.MethodGet, "http://gw.example.com/x", nil)
+	r.RemoteAddr = "10.0.0.2:51000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7, 10.0.0.3")
+	r.Header.Set("Forwarded", `for=198.51.100.7, for=10.0.0.3`)
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "api.example.com")
+
+	seen := serve(t, trusted, r)
+	if got := ClientIPFrom(seen.Context()); got.String() != "198.51.100.7" {
+		t.Errorf("client = %s", got)
+	}
+	want := map[string]string{
+		"X-Forwarded-For":   "198.51.100.7, 10.0.0.3, 10.0.0.2",
+		"Forwarded":         "for=198.51.100.7, for=10.0.0.3, for=10.0.0.2;proto=http;host=gw.example.com",
+		"X-Forwarded-Proto": "https",
+		"X-Forwarded-Host":  "api.example.com",
+	}
+	for k, v := range want {
+		if got := seen.Header.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestProxyHeadersIPv6Peer(t *testing.T) {
+	trusted := mustPrefixes(t, "::1/128")
+	r := httptest.NewRequest(http.MethodGet, "http://gw.example.com/x", nil)
+	r.RemoteAddr = "[::1]:51000"
+	r.Header.Set("Forwarded", `for="[2001:db8::7]:4711"`)
+
+	seen := serve(t, trusted, r)
+	if got := ClientIPFrom(seen.Context()); got.String() != "2001:db8::7" {
+		t.Errorf("client = %s", got)
+	}
+	if got, want := seen.Header.Get("Forwarded"), `for="[2001:db8::7]:4711", for="[::1]";proto=http;host=gw.example.com`; got != want {
+		t.Errorf("Forwarded = %q, want %q", got, want)
+	}
+	if got := seen.Header.Get("X-Forwarded-For"); got != "::1" {
+		t.Errorf("XFF = %q", got)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	cases := map[string]string{
+		"10.0.0.1:80":       "10.0.0.1",
+		"[::1]:80":          "::1",
+		"10.0.0.1":          "10.0.0.1",
+		"":                  "",
+		"::1":               "", // 포트 없는 IPv6 는 모호 → 빈 값
+		"[fe80::1%eth0]:80": "fe80::1%eth0",
+	}
+	for in, want := range cases {
+		if got := clientIP(in); got != want {
+			t.Errorf("clientIP(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/model"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -66,8 +68,8 @@ func (r *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		// (2) Allow() → 즉시 토큰 확인 (대기 X)
 		if !r.limiter.Allow() {
-			w.Header().Set("Retry-After", "1") // WHY: 간단한 재시도 지연 힌트(초 단위)
-			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			// WHY: Retry-After 1초 = 간단한 재시도 지연 힌트
+			httpx.WriteError(w, req, &httpx.Error{Code: model.ErrCodeRateLimited, RetryAfter: time.Second})
 			return
 		}
 		// (3) 토큰 소비 성공 → 다음 핸들러 실행
@@ -75,6 +77,14 @@ func (r *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// Allow: 토큰 1개 즉시 소비 (HTTP 외 경로 - gRPC 인터셉터 - 에서 같은 버킷 공유)
+func (r *RateLimiter) Allow() bool {
+	if r == nil || !r.enabled || r.limiter == nil {
+		return true
+	}
+	return r.limiter.Allow()
+}
+
 // WaitMiddleware: 대기 허용 버전
 // - 토큰이 없으면 maxWait 기간 내에서 토큰이 채워질 때까지 기다림
 // - maxWait 초과 시 429
@@ -91,8 +101,7 @@ func (r *RateLimiter) WaitMiddleware(next http.Handler, maxWait time.Duration) h
 		// (3) Wait: 토큰 사용 가능한 시점까지 블록(또는 ctx timeout)
 		if err := r.limiter.Wait(ctx); err != nil {
 			// timeout 또는 context cancellation → 429
-			w.Header().Set("Retry-After", "1")
-			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			httpx.WriteError(w, req, &httpx.Error{Code: model.ErrCodeRateLimited, RetryAfter: time.Second})
 			return
 		}
 
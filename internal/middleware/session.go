@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/logx"
+	"service-gateway/internal/masking"
+	"service-gateway/internal/model"
+	"service-gateway/internal/router"
+	"service-gateway/internal/session"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+세션 미들웨어 (routes[].options.require_session / generate_if_missing)
+
+WHY:
+- 세션 처리가 ReverseProxy.Proxy 에 라우트 이름(save-user, find-user-info)으로 하드코딩되어 있었음
+  → 라우트 옵션 기반으로 일반화, 라우트 추가 시 코드 수정 불필요.
+
+정책 (X-Fw-Session-Id):
+- 두 옵션 모두 false 인 라우트 / 매칭 안 된 요청은 그대로 통과
+- 라우트 권한(AuthorizeRoute: IP 허용/차단, scope / 클레임) 을 먼저 판정 → 거부될 요청으로 세션을 만들지 않음
+- 헤더 있음 : 세션 서비스에서 존재 확인, 없거나 만료면 generate_if_missing 은 새로 발급,
+              require_session 은 401(27), 둘 다 아니면 헤더 제거 후 통과
+- 헤더 없음 : generate_if_missing 이면 UUID 발급 후 세션 서비스에 적재, require_session 이면 401(27)
+- 확정된 세션 ID 는 업스트림 요청 헤더와 응답 헤더에 설정
+- 세션 서비스 호출 실패는 502(19)
+
+배치: 멱등 가드 안쪽 (재응답은 세션 서비스 미호출, 저장된 응답의 세션 헤더가 그대로 나감)
+*/
+
+const sessionHeader = "X-Fw-Session-Id"
+
+var errNoSession = errors.New("session: missing or expired")
+
+type SessionConfig struct {
+	Store  session.Store
+	TTL    time.Duration // 신규 세션 유지 시간 (기본 30m)
+	Logger *slog.Logger
+}
+
+type Session struct {
+	cfg   SessionConfig
+	route func(r *http.Request) *router.RouteOptions // 요청 → 라우트 옵션 (nil 이면 미매칭)
+}
+
+func NewSession(cfg SessionConfig, route func(r *http.Request) *router.RouteOptions) *Session {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 30 * time.Minute
+	}
+	return &Session{cfg: cfg, route: route}
+}
+
+func (s *Session) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		opts := s.route(r)
+		if opts == nil || (!opts.RequireSession && !opts.GenerateIfMissing) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+		lg := logx.Or(s.cfg.Logger)
+		if ge := AuthorizeRoute(ctx, *opts); ge != nil {
+			httpx.WriteError(w, r, ge)
+			return
+		}
+
+		id := r.Header.Get(sessionHeader)
+		if id != "" {
+			ok, err := s.cfg.Store.Exists(ctx, id)
+			if err != nil {
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeUpstreamFailed, err))
+				return
+			}
+			if !ok {
+				lg.DebugContext(ctx, "unknown session", "session_id", masking.Default().String(id))
+				id = ""
+			}
+		}
+
+		if id == "" && opts.GenerateIfMissing {
+			id = uuid.NewString()
+			if err := s.cfg.Store.Create(ctx, id, s.cfg.TTL); err != nil {
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeUpstreamFailed, err))
+				return
+			}
+			lg.DebugContext(ctx, "session created", "session_id", masking.Default().String(id))
+		}
+
+		if id == "" {
+			if opts.RequireSession {
+				httpx.WriteError(w, r, httpx.Err(model.ErrCodeSessionRequired, errNoSession))
+				return
+			}
+			r.Header.Del(sessionHeader)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		r.Header.Set(sessionHeader, id)
+		w.Header().Set(sessionHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
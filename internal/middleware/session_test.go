@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"service-gateway/internal/idempotency"
+	"service-gateway/internal/ipfilter"
+	"service-gateway/internal/router"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStore: 호출 수 집계 메모리 세션 저장소
+type fakeStore struct {
+	mu      sync.Mutex
+	data    map[string]bool
+	exists  int
+	creates int
+	err     error
+}
+
+func newFakeStore(ids ...string) *fakeStore {
+	f := &fakeStore{data: map[string]bool{}}
+	for _, id := range ids {
+		f.data[id] = true
+	}
+	return f
+}
+
+func (f *fakeStore) Exists(_ context.Context, id string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exists++
+	return f.data[id], f.err
+}
+
+func (f *fakeStore) Create(_ context.Context, id string, _ time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.creates++
+	if f.err != nil {
+		return f.err
+	}
+	f.data[id] = true
+	return nil
+}
+
+func sessionHandler(st *fakeStore, opts *router.RouteOptions, seen *string) http.Handler {
+	s := NewSession(SessionConfig{Store: st}, func(*http.Request) *router.RouteOptions { return opts })
+	return s.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = r.Header.Get(sessionHeader)
+		w.Write([]byte("ok"))
+	}))
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	cases := []struct {
+		name        string
+		opts        *router.RouteOptions
+		header      string
+		storeErr    error
+		want        int
+		wantSession string // "new" 면 새 UUID
+		wantCreates int
+	}{
+		{"unmatched route", nil, "", nil, http.StatusOK, "", 0},
+		{"no session options", &router.RouteOptions{}, "", nil, http.StatusOK, "", 0},
+		{"existing session", &router.RouteOptions{RequireSession: true}, "sess-1", nil, http.StatusOK, "sess-1", 0},
+		{"require, missing", &router.RouteOptions{RequireSession: true}, "", nil, http.StatusUnauthorized, "", 0},
+		{"require, unknown", &router.RouteOptions{RequireSession: true}, "stale", nil, http.StatusUnauthorized, "", 0},
+		{"generate, missing", &router.RouteOptions{GenerateIfMissing: true}, "", nil, http.StatusOK, "new", 1},
+		{"generate, unknown", &router.RouteOptions{GenerateIfMissing: true}, "stale", nil, http.StatusOK, "new", 1},
+		{"generate, existing", &router.RouteOptions{GenerateIfMissing: true}, "sess-1", nil, http.StatusOK, "sess-1", 0},
+		{"store failure", &router.RouteOptions{RequireSession: true}, "sess-1", errors.New("down"), http.StatusBadGateway, "", 0},
+	}
+	for _, c := range cases {
+		st := newFakeStore("sess-1")
+		st.err = c.storeErr
+		var seen string
+		r := httptest.NewRequest(http.MethodPost, "/save/user", nil)
+		if c.header != "" {
+			r.Header.Set(sessionHeader, c.header)
+		}
+		w := httptest.NewRecorder()
+		sessionHandler(st, c.opts, &seen).ServeHTTP(w, r)
+
+		if w.Code != c.want {
+			t.Errorf("%s: status %d, want %d", c.name, w.Code, c.want)
+			continue
+		}
+		if st.creates != c.wantCreates {
+			t.Errorf("%s: creates = %d, want %d", c.name, st.creates, c.wantCreates)
+		}
+		if c.want != http.StatusOK {
+			continue
+		}
+		switch c.wantSession {
+		case "new":
+			if seen == "" || seen == c.header || w.Header().Get(sessionHeader) != seen {
+				t.Errorf("%s: upstream %q, response %q", c.name, seen, w.Header().Get(sessionHeader))
+			}
+		default:
+			if seen != c.wantSession || w.Header().Get(sessionHeader) != c.wantSession {
+				t.Errorf("%s: upstream %q, response %q, want %q", c.name, seen, w.Header().Get(sessionHeader), c.wantSession)
+			}
+		}
+	}
+}
+
+// 라우트 권한에서 거부될 요청은 세션 서비스를 호출하지 않음
+func TestSessionAuthorizeFirst(t *testing.T) {
+	deny, err := ipfilter.Parse(nil, []string{"203.0.113.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name string
+		opts *router.RouteOptions
+		ip   string
+		want int
+	}{
+		{"ip denied", &router.RouteOptions{GenerateIfMissing: true, IPFilter: deny}, "203.0.113.9", http.StatusForbidden},
+		{"scope without token", &router.RouteOptions{GenerateIfMissing: true, Scopes: []string{"read"}}, "198.51.100.9", http.StatusUnauthorized},
+		{"allowed", &router.RouteOptions{GenerateIfMissing: true, IPFilter: deny}, "198.51.100.9", http.StatusOK},
+	}
+	for _, c := range cases {
+		st := newFakeStore()
+		var seen string
+		r := httptest.NewRequest(http.MethodPost, "/save/user", nil)
+		r = r.WithContext(context.WithValue(r.Context(), clientIPKey{}, netip.MustParseAddr(c.ip)))
+		w := httptest.NewRecorder()
+		sessionHandler(st, c.opts, &seen).ServeHTTP(w, r)
+
+		if w.Code != c.want {
+			t.Errorf("%s: status %d, want %d", c.name, w.Code, c.want)
+		}
+		wantCalls := 0
+		if c.want == http.StatusOK {
+			wantCalls = 1
+		}
+		if st.creates != wantCalls || st.exists != 0 {
+			t.Errorf("%s: exists %d / creates %d, want 0 / %d", c.name, st.exists, st.creates, wantCalls)
+		}
+	}
+}
+
+// main 과 같은 순서 (멱등 가드 → 세션): 재응답은 세션을 새로 만들지 않고 첫 응답의 세션 ID 를 돌려줌
+func TestSessionIdempotentReplay(t *testing.T) {
+	st := newFakeStore()
+	var seen string
+	h := idempotency.New(idempotency.NewMemoryStore(), idempotency.Config{}).
+		Middleware(sessionHandler(st, &router.RouteOptions{GenerateIfMissing: true}, &seen))
+
+	call := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/save/user", nil)
+		r.Header.Set("Idempotency-Key", "k-1")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+	first, second := call(), call()
+	if st.creates != 1 {
+		t.Errorf("creates = %d, want 1", st.creates)
+	}
+	if second.Header().Get(idempotency.ReplayedHeader) != "true" {
+		t.Fatal("second call not replayed")
+	}
+	if id := first.Header().Get(sessionHeader); id == "" || second.Header().Get(sessionHeader) != id {
+		t.Errorf("session ids: first %q, replay %q", id, second.Header().Get(sessionHeader))
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import "time"
+
+// SID_API_GRP_MNG 한 행
+type ApiGroup struct {
+	ApiGroupCode   string `json:"apiGroupCd"`
+	ApiGroupName   string `json:"apiGroupNm,omitempty"`
+	UseYn          string `json:"usgYn"`
+	ClotCtlCode    string `json:"clotCtlCd"`
+	ClotUablStaTim string `json:"clotUablStaTim,omitempty"`
+	ClotUablEndTim string `json:"clotUablEndTim,omitempty"`
+}
+
+// SID_API_DTL_MNG 한 행
+type ApiDetail struct {
+	ApiGroupCode   string `json:"apiGroupCd"`
+	ApiCode        string `json:"apiCd"`
+	ApiName        string `json:"apiNm,omitempty"`
+	ApiPath        string `json:"apiPath"`
+	ApiTypeCode    string `json:"apiTypCd"`
+	TargetURI      string `json:"targetUri,omitempty"`
+	UseYn          string `json:"usgYn"`
+	ClotCtlCode    string `json:"clotCtlCd"`
+	ClotUablStaTim string `json:"clotUablStaTim,omitempty"`
+	ClotUablEndTim string `json:"clotUablEndTim,omitempty"`
+}
+
+// SID_BIZ_SRVC_API_RLP 한 행
+type BizSrvcPermission struct {
+	BizServiceCode string `json:"bizSrvcCd"`
+	ApiGroupCode   string `json:"apiGroupCd"`
+	ApiCode        string `json:"apiCd"`
+	UseYn          string `json:"usgYn"`
+}
+
+// SID_API_EST_MNG 한 행
+type ApiSetting struct {
+	ApiGroupCode string `json:"apiGroupCd"`
+	Key          string `json:"apiEstKey"`
+	Value        string `json:"value"`
+	UseYn        string `json:"usgYn"`
+}
+
+// SID_BIZ_SRVC_API_KEY 한 행 (원문 키는 저장하지 않음)
+type ApiKey struct {
+	KeyID          string     `json:"keyId"`
+	BizServiceCode string     `json:"bizSrvcCd"`
+	Hash           string     `json:"-"` // SHA-256(전체 키) hex
+	Name           string     `json:"keyNm,omitempty"`
+	ExpiresAt      *time.Time `json:"expDtm,omitempty"` // nil 이면 무기한
+	UseYn          string     `json:"usgYn"`            // N = 폐기
+	CreatedAt      time.Time  `json:"regDtm"`
+}
+
+// SID_BIZ_SRVC_IP_ACL 한 행
+type IPAcl struct {
+	ID             int64  `json:"aclId"`
+	BizServiceCode string `json:"bizSrvcCd"`
+	CIDR           string `json:"cidr"`   // 10.0.0.1 | 10.0.0.0/8
+	Type           string `json:"aclTyp"` // A = 허용, D = 차단
+	Remark         string `json:"rmk,omitempty"`
+	UseYn          string `json:"usgYn"`
+}
+
+// 관리 API 변경 이력 (SID_ADM_AUDIT_HIS)
+type AuditRecord struct {
+	Seq        int64     `json:"seq"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"` // CREATE | UPDATE | DELETE
+	TargetType string    `json:"targetType"`
+	TargetKey  string    `json:"targetKey"`
+	Before     string    `json:"before,omitempty"`
+	After      string    `json:"after,omitempty"`
+	ClientIP   string    `json:"clientIp,omitempty"`
+	Tcid       string    `json:"tcId,omitempty"`
+	CreatedAt  time.Time `json:"regDtm"`
+}
+
+// SID_API_MNT_WIN 한 행: 점검(거래불가) 시간대
+// Cron 이 비어 있으면 StartTim~EndTim 1회성, 있으면 매 발생 시각부터 DurationMin 동안 (StartTim/EndTim 은 유효기간)
+type MaintenanceWindow struct {
+	ID            int64  `json:"winId"`
+	ApiGroupCode  string `json:"apiGroupCd"`
+	ApiCode       string `json:"apiCd,omitempty"` // 빈 값이면 그룹 전체
+	ControlCode   string `json:"clotCtlCd"`
+	StartTim      string `json:"staTim,omitempty"` // yyyyMMddHHmmss (TimeZone 기준)
+	EndTim        string `json:"endTim,omitempty"`
+	Cron          string `json:"cron,omitempty"` // "분 시 일 월 요일"
+	DurationMin   int    `json:"durMin,omitempty"`
+	TimeZone      string `json:"tz"`
+	Message       string `json:"msg,omitempty"`
+	RetryAfterSec int    `json:"retryAfterSec,omitempty"`
+	UseYn         string `json:"usgYn"`
+}
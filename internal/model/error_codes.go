@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // ErrorCodeMap은 에러 코드와 메시지를 매핑합니다.
 var ErrorCodeMap = map[string]string{
 	"00": "정상",
@@ -13,3 +15,90 @@ var ErrorCodeMap = map[string]string{
 	"08": "지정기간거래불가", // 기간
 	// 필요에 따라 추가
 }
+
+// ErrorDef: 게이트웨이 에러 카탈로그 항목 (코드는 외부 계약이므로 변경 금지, 추가만)
+type ErrorDef struct {
+	Status int    // HTTP 상태코드
+	Ko     string // 기본(한국어) 메시지
+	En     string // 영문 메시지
+}
+
+// 게이트웨이 에러코드: 00~09 는 거래통제(CLOT) 코드, 10 이후는 게이트웨이 처리 오류
+const (
+	ErrCodeBadRequest      = "10" // 요청 형식 오류 (JSON/본문 읽기)
+	ErrCodeValidation      = "11" // 요청 값 검증 실패
+	ErrCodeBodyTooLarge    = "12" // 요청 본문 크기 초과
+	ErrCodeMethodNotAllow  = "13" // 허용되지 않은 메서드
+	ErrCodeApiNotFound     = "14" // 등록되지 않은 API / 라우트
+	ErrCodeApiForbidden    = "15" // 업무서비스에 허용되지 않은 API
+	ErrCodeGroupForbidden  = "16" // 사용 불가 API 그룹
+	ErrCodeUnauthorized    = "17" // 인증 실패
+	ErrCodeRateLimited     = "18" // 요청 한도 초과
+	ErrCodeUpstreamFailed  = "19" // 업스트림 호출 실패
+	ErrCodeUpstreamTimeout = "20" // 업스트림 응답 시간 초과
+	ErrCodeHostNotFound    = "21" // 업스트림 호스트 미설정
+	ErrCodeUnavailable     = "22" // 일시적 서비스 불가 (서킷 브레이커)
+	ErrCodeConflict        = "23" // 중복 / 충돌
+	ErrCodeNotFound        = "24" // 관리 대상 없음
+	ErrCodeIdemMismatch    = "25" // 같은 멱등키로 다른 요청
+	ErrCodeIPForbidden     = "26" // 허용되지 않은 클라이언트 IP
+	ErrCodeSessionRequired = "27" // 세션 없음 / 만료 (require_session 라우트)
+	ErrCodeCatalog         = "98" // API 카탈로그(DB) 조회 오류
+	ErrCodeInternal        = "99" // 내부 오류
+)
+
+// ErrorCatalog: ErrorCodeMap(거래통제) + 게이트웨이 처리 오류
+var ErrorCatalog = map[string]ErrorDef{
+	"01": {503, ErrorCodeMap["01"], "System failure"},
+	"02": {503, ErrorCodeMap["02"], "Transaction overload"},
+	"03": {503, ErrorCodeMap["03"], "System maintenance"},
+	"04": {503, ErrorCodeMap["04"], "Interface failure"},
+	"05": {503, ErrorCodeMap["05"], "Response time failure"},
+	"06": {503, ErrorCodeMap["06"], "Accumulated errors"},
+	"07": {503, ErrorCodeMap["07"], "Business maintenance"},
+	"08": {503, ErrorCodeMap["08"], "Transactions unavailable during the scheduled period"},
+
+	ErrCodeBadRequest:      {400, "잘못된 요청", "Malformed request"},
+	ErrCodeValidation:      {400, "요청 값 검증 실패", "Request validation failed"},
+	ErrCodeBodyTooLarge:    {413, "요청 본문 크기 초과", "Request entity too large"},
+	ErrCodeMethodNotAllow:  {405, "허용되지 않은 메서드", "Method not allowed"},
+	ErrCodeApiNotFound:     {404, "등록되지 않은 API", "API not found"},
+	ErrCodeApiForbidden:    {403, "사용 권한이 없는 API", "Access not allowed by use API policy"},
+	ErrCodeGroupForbidden:  {403, "사용 불가 API 그룹", "API group not allowed"},
+	ErrCodeUnauthorized:    {401, "인증 실패", "Authentication failed"},
+	ErrCodeRateLimited:     {429, "요청 한도 초과", "Rate limit exceeded"},
+	ErrCodeUpstreamFailed:  {502, "업무 서비스 호출 실패", "Upstream request failed"},
+	ErrCodeUpstreamTimeout: {504, "업무 서비스 응답 시간 초과", "Upstream timed out"},
+	ErrCodeHostNotFound:    {500, "업무 서비스 호스트 미설정", "Upstream host not configured"},
+	ErrCodeUnavailable:     {503, "일시적 서비스 불가", "Service temporarily unavailable"},
+	ErrCodeConflict:        {409, "중복 요청", "Conflict"},
+	ErrCodeNotFound:        {404, "대상을 찾을 수 없음", "Resource not found"},
+	ErrCodeIdemMismatch:    {422, "멱등키 재사용 요청 불일치", "Idempotency key reused with a different request"},
+	ErrCodeIPForbidden:     {403, "허용되지 않은 IP", "Client IP not allowed"},
+	ErrCodeSessionRequired: {401, "세션 없음 또는 만료", "Session missing or expired"},
+	ErrCodeCatalog:         {500, "API 정보 조회 오류", "API catalog lookup failed"},
+	ErrCodeInternal:        {500, "내부 오류", "Internal error"},
+}
+
+// ControlError: 거래통제(CLOT) 코드 또는 점검 시간대로 차단된 요청
+// 핸들러는 503 + Code 로 응답 (RetryAfter > 0 이면 Retry-After 헤더)
+type ControlError struct {
+	Code       string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *ControlError) Error() string {
+	return e.Message
+}
+
+// NewControlError: 메시지가 비어 있으면 ErrorCodeMap 기본 문구 사용
+func NewControlError(code, msg string, retryAfter time.Duration) *ControlError {
+	if msg == "" {
+		msg = ErrorCodeMap[code]
+	}
+	if msg == "" {
+		msg = "알 수 없는 에러"
+	}
+	return &ControlError{Code: code, Message: msg, RetryAfter: retryAfter}
+}
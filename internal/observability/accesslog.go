@@ -0,0 +1,301 @@
+package observability
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"service-gateway/internal/logx"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+/*
+Access log 미들웨어
+
+WHY:
+- 기존 Logging 은 method/path/status/duration 만 남겨 응답 크기, 클라이언트 IP, 업스트림, 라우트, TCID 로
+  요청을 추적할 수 없었음.
+- 운영 로그(slog)와 분리된 스트림으로 common / combined(Apache 호환) / json / 사용자 템플릿 중 선택.
+- 트래픽이 많은 정상(2xx) 응답은 sample_rate 비율만 기록, 4xx/5xx 와 slow_ms 초과 요청은 항상 기록.
+
+배치:
+- logx.Middleware / ProxyHeaders 안쪽, FwHeaderTrace 바깥 (요청 처리 후 확정된 TCID / route / upstream / 실제 client_ip 를 읽음)
+- 응답 Writer 는 상태/바이트 수만 가로채고 Flush / Hijack 은 원본에 위임 (SSE, WebSocket 업그레이드)
+*/
+
+// AccessLogConfig: gateway.yaml access_log 블록
+type AccessLogConfig struct {
+	Format     string        // common | combined | json(기본) | template
+	Template   string        // format=template 일 때 text/template (필드: AccessEntry)
+	Output     string        // stdout(기본) | stderr | 파일 경로(append)
+	SampleRate float64       // 2xx 기록 비율 0~1 (기본 1)
+	Slow       time.Duration // 이 시간 이상 걸린 요청은 항상 기록 (0 이면 미사용)
+}
+
+// AccessEntry: 요청 1건 (템플릿에서 {{.Status}} 등으로 사용)
+type AccessEntry struct {
+	Time      time.Time
+	Method    string
+	URI       string
+	Proto     string
+	Host      string
+	Status    int
+	Bytes     int64
+	Duration  time.Duration
+	ClientIP  string
+	UserAgent string
+	Referer   string
+	TCID      string
+	Route     string
+	Upstream  string
+}
+
+type AccessLog struct {
+	cfg    AccessLogConfig
+	tmpl   *template.Template
+	out    io.Writer
+	closer io.Closer
+	mu     sync.Mutex // 줄 단위 쓰기 보장
+	sample func() float64
+}
+
+// NewAccessLog: 설정 검증 + 출력 대상 열기
+func NewAccessLog(cfg AccessLogConfig) (*AccessLog, error) {
+	a := &AccessLog{cfg: cfg, sample: rand.Float64}
+	if a.cfg.SampleRate <= 0 || a.cfg.SampleRate > 1 {
+		a.cfg.SampleRate = 1
+	}
+
+	a.cfg.Format = strings.ToLower(strings.TrimSpace(a.cfg.Format))
+	switch a.cfg.Format {
+	case "":
+		a.cfg.Format = "json"
+	case "json", "common", "combined":
+	case "template":
+		if strings.TrimSpace(cfg.Template) == "" {
+			return nil, errors.New("access_log.template: required when format=template")
+		}
+		t, err := template.New("access").Parse(cfg.Template)
+		if err != nil {
+			return nil, fmt.Errorf("access_log.template: %w", err)
+		}
+		a.tmpl = t
+	default:
+		return nil, fmt.Errorf("access_log.format: unknown %q (common|combined|json|template)", cfg.Format)
+	}
+
+	switch cfg.Output {
+	case "", "stdout":
+		a.out = os.Stdout
+	case "stderr":
+		a.out = os.Stderr
+	default:
+		f, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("access_log.output: %w", err)
+		}
+		a.out, a.closer = f, f
+	}
+	return a, nil
+}
+
+// Close: 파일 출력일 때만 닫음
+func (a *AccessLog) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+// Middleware: 응답 완료 후 기록 여부 판단
+func (a *AccessLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &accessWriter{ResponseWriter: w}
+		next.ServeHTTP(rw, r)
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK // 본문 없이 반환된 핸들러
+		}
+		elapsed := time.Since(start)
+		if !a.keep(status, elapsed) {
+			return
+		}
+
+		v := logx.FromContext(r.Context())
+		ip := v.ClientIP
+		if ip == "" {
+			ip = remoteIP(r.RemoteAddr)
+		}
+		a.write(AccessEntry{
+			Time:      start,
+			Method:    r.Method,
+			URI:       r.RequestURI,
+			Proto:     r.Proto,
+			Host:      r.Host,
+			Status:    status,
+			Bytes:     rw.bytes,
+			Duration:  elapsed,
+			ClientIP:  ip,
+			UserAgent: r.UserAgent(),
+			Referer:   r.Referer(),
+			TCID:      v.TCID,
+			Route:     v.Route,
+			Upstream:  v.Upstream,
+		})
+	})
+}
+
+// keep: 에러/지연은 항상, 2xx 는 샘플링
+func (a *AccessLog) keep(status int, elapsed time.Duration) bool {
+	if status >= 400 || (a.cfg.Slow > 0 && elapsed >= a.cfg.Slow) {
+		return true
+	}
+	if status >= 200 && status < 300 && a.cfg.SampleRate < 1 {
+		return a.sample() < a.cfg.SampleRate
+	}
+	return true
+}
+
+func (a *AccessLog) write(e AccessEntry) {
+	var buf bytes.Buffer
+	switch a.cfg.Format {
+	case "common", "combined":
+		fmt.Fprintf(&buf, "%s - - [%s] \"%s %s %s\" %d %s",
+			dash(e.ClientIP), e.Time.Format("02/Jan/2006:15:04:05 -0700"),
+			e.Method, e.URI, e.Proto, e.Status, clfBytes(e.Bytes))
+		if a.cfg.Format == "combined" {
+			fmt.Fprintf(&buf, " %s %s", strconv.Quote(dash(e.Referer)), strconv.Quote(dash(e.UserAgent)))
+		}
+		buf.WriteByte('\n')
+	case "template":
+		if err := a.tmpl.Execute(&buf, e); err != nil {
+			buf.Reset()
+			fmt.Fprintf(&buf, "access_log template error: %v", err)
+		}
+		if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	default:
+		_ = json.NewEncoder(&buf).Encode(accessJSON{
+			Time:       e.Time.Format(time.RFC3339Nano),
+			Method:     e.Method,
+			URI:        e.URI,
+			Proto:      e.Proto,
+			Host:       e.Host,
+			Status:     e.Status,
+			Bytes:      e.Bytes,
+			DurationMs: float64(e.Duration.Microseconds()) / 1000,
+			ClientIP:   e.ClientIP,
+			UserAgent:  e.UserAgent,
+			Referer:    e.Referer,
+			TCID:       e.TCID,
+			Route:      e.Route,
+			Upstream:   e.Upstream,
+		})
+	}
+
+	a.mu.Lock()
+	_, _ = a.out.Write(buf.Bytes())
+	a.mu.Unlock()
+}
+
+type accessJSON struct {
+	Time       string  `json:"time"`
+	Method     string  `json:"method"`
+	URI        string  `json:"uri"`
+	Proto      string  `json:"proto"`
+	Host       string  `json:"host"`
+	Status     int     `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	DurationMs float64 `json:"duration_ms"`
+	ClientIP   string  `json:"client_ip"`
+	UserAgent  string  `json:"user_agent,omitempty"`
+	Referer    string  `json:"referer,omitempty"`
+	TCID       string  `json:"tcid,omitempty"`
+	Route      string  `json:"route,omitempty"`
+	Upstream   string  `json:"upstream,omitempty"`
+}
+
+// accessWriter: 상태코드 / 응답 바이트 수 기록
+type accessWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush: 스트리밍 응답 (원본이 지원하지 않으면 무시)
+func (w *accessWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack: WebSocket 등 연결 인수 (이후 바이트는 집계하지 않음)
+func (w *accessWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, brw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, brw, err
+}
+
+// Unwrap: http.ResponseController 가 원본 Writer 에 접근
+func (w *accessWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func remoteIP(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func clfBytes(n int64) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,82 @@
+package httpadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+업스트림 요청 본문 매핑 (routes[].backend.body)
+
+WHY:
+- find-user-info 는 GET /get/user/{field} 를 POST /v1/session/find/field {"key": 세션ID, "field": 경로값}
+  으로 바꿔 보내야 하는데, 라우트 이름 하드코딩을 걷어낸 뒤로 본문 없이 전달되어 동작하지 않았음.
+  → 라우트 설정으로 JSON 본문을 구성 (라우트 추가 시 코드 수정 불필요).
+
+값 (JSON 키 → 출처):
+- "param:<이름>"  : 경로 변수 (path_pattern 의 {이름})
+- "header:<이름>" : 요청 헤더 (세션 미들웨어가 확정한 X-Fw-Session-Id 등)
+- "const:<값>"    : 고정 문자열
+설정되면 클라이언트 JSON 객체 본문에 매핑 결과를 덮어써서 보냄 (본문이 없으면 매핑 결과만).
+- save-user 처럼 세션 미들웨어가 확정한 세션 ID 를 본문 key 로 넣어야 저장 / 조회 키가 일치함
+  (클라이언트가 보낸 key 는 신뢰하지 않음 → 매핑 값이 우선)
+- 클라이언트 본문이 JSON 객체가 아니거나 출처 값이 비어 있으면 400(10)
+*/
+
+type bodySource struct {
+	kind string // param | header | const
+	name string
+}
+
+// BodyMap: JSON 키 → 출처
+type BodyMap map[string]bodySource
+
+// ParseBodyMap: 설정 검증 (알 수 없는 출처는 에러), 비어 있으면 nil
+func ParseBodyMap(m map[string]string) (BodyMap, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	out := make(BodyMap, len(m))
+	for key, src := range m {
+		kind, name, ok := strings.Cut(src, ":")
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("body.%s: %q needs a param:/header:/const: prefix", key, src)
+		case kind != "param" && kind != "header" && kind != "const":
+			return nil, fmt.Errorf("body.%s: unknown source %q (param|header|const)", key, kind)
+		case name == "" && kind != "const":
+			return nil, fmt.Errorf("body.%s: %s name required", key, kind)
+		}
+		out[key] = bodySource{kind: kind, name: name}
+	}
+	return out, nil
+}
+
+// build: 클라이언트 본문(JSON 객체, 비어 있으면 {}) 에 요청 헤더 / 경로 변수 값을 덮어씀
+func (b BodyMap) build(h http.Header, params map[string]string, client []byte) ([]byte, error) {
+	obj := make(map[string]json.RawMessage, len(b)) // 클라이언트 필드는 원문 유지 (숫자 정밀도 등)
+	if len(bytes.TrimSpace(client)) > 0 {
+		if err := json.Unmarshal(client, &obj); err != nil || obj == nil {
+			return nil, fmt.Errorf("body: client body must be a JSON object")
+		}
+	}
+	for key, src := range b {
+		var v string
+		switch src.kind {
+		case "param":
+			v = params[src.name]
+		case "header":
+			v = h.Get(src.name)
+		case "const":
+			v = src.name
+		}
+		if v == "" && src.kind != "const" {
+			return nil, fmt.Errorf("body.%s: %s %q missing", key, src.kind, src.name)
+		}
+		obj[key], _ = json.Marshal(v)
+	}
+	return json.Marshal(obj)
+}
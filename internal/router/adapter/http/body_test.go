@@ -0,0 +1,178 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"service-gateway/internal/middleware"
+	"service-gateway/internal/router"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseBodyMap(t *testing.T) {
+	if bm, err := ParseBodyMap(nil); bm != nil || err != nil {
+		t.Errorf("empty: %v %v", bm, err)
+	}
+	for _, bad := range []string{"field", "query:x", "param:", "header:"} {
+		if _, err := ParseBodyMap(map[string]string{"k": bad}); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
+
+func TestProxyBodyMap(t *testing.T) {
+	var gotMethod, gotType string
+	var got map[string]string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotType = r.Method, r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &got)
+	}))
+	defer up.Close()
+
+	bm, err := ParseBodyMap(map[string]string{"key": "header:X-Fw-Session-Id", "field": "param:field", "v": "const:1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &ReverseProxy{Client: up.Client(), Bodies: map[string]BodyMap{"find-user-info": bm}}
+	host := strings.TrimPrefix(up.URL, "http://")
+
+	call := func(session string) *httptest.ResponseRecorder {
+		got = nil
+		r := httptest.NewRequest(http.MethodGet, "/get/user/name", nil)
+		if session != "" {
+			r.Header.Set("X-Fw-Session-Id", session)
+		}
+		w := httptest.NewRecorder()
+		p.Proxy(context.Background(), w, r, "find-user-info", "http", host, "/v1/session/find/field", http.MethodPost, map[string]string{"field": "name"})
+		return w
+	}
+
+	if w := call("sess-1"); w.Code != http.StatusOK {
+		t.Fatalf("status %d %s", w.Code, w.Body)
+	}
+	if gotMethod != http.MethodPost || gotType != "application/json" {
+		t.Errorf("upstream %s %q", gotMethod, gotType)
+	}
+	if got["key"] != "sess-1" || got["field"] != "name" || got["v"] != "1" || len(got) != 3 {
+		t.Errorf("body = %v", got)
+	}
+
+	// 세션 헤더 없음 → 업스트림 미호출, 400
+	if w := call(""); w.Code != http.StatusBadRequest || got != nil {
+		t.Errorf("missing header: status %d, upstream body %v", w.Code, got)
+	}
+}
+
+func TestBodyMapMerge(t *testing.T) {
+	bm, err := ParseBodyMap(map[string]string{"key": "header:X-Fw-Session-Id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := http.Header{"X-Fw-Session-Id": {"sess-1"}}
+
+	b, err := bm.build(h, nil, []byte(`{"key":"client-key","name":"kim","amount":12345678901234567890}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"amount":12345678901234567890,"key":"sess-1","name":"kim"}`; string(b) != want {
+		t.Errorf("merged = %s, want %s", b, want)
+	}
+	if b, err := bm.build(h, nil, []byte(" \n")); err != nil || string(b) != `{"key":"sess-1"}` {
+		t.Errorf("empty client body: %s %v", b, err)
+	}
+	for _, bad := range []string{`[1,2]`, `"x"`, `null`, `{broken`} {
+		if _, err := bm.build(h, nil, []byte(bad)); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
+
+// memSessions: 세션 서비스 대역 (session.Store)
+type memSessions struct {
+	mu  sync.Mutex
+	ids map[string]bool
+}
+
+func (m *memSessions) Exists(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ids[id], nil
+}
+
+func (m *memSessions) Create(_ context.Context, id string, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ids[id] = true
+	return nil
+}
+
+// save-user 로 저장한 값을 find-user-info 로 다시 읽음 (헤더 없이 시작 → 발급된 세션 ID 로 조회)
+func TestSaveFindRoundTrip(t *testing.T) {
+	var mu sync.Mutex
+	saved := map[string]map[string]string{}
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/session/save":
+			saved[req["key"]] = req
+		case "/v1/session/find/field":
+			v, ok := saved[req["key"]][req["field"]]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = io.WriteString(w, v)
+		}
+	}))
+	defer up.Close()
+	host := strings.TrimPrefix(up.URL, "http://")
+
+	saveBody, _ := ParseBodyMap(map[string]string{"key": "header:X-Fw-Session-Id"})
+	findBody, _ := ParseBodyMap(map[string]string{"key": "header:X-Fw-Session-Id", "field": "param:field"})
+	p := &ReverseProxy{Client: up.Client(), Bodies: map[string]BodyMap{"save-user": saveBody, "find-user-info": findBody}}
+
+	routes := map[string]*router.RouteOptions{
+		"/save/user":     {RequireSession: true, GenerateIfMissing: true},
+		"/get/user/name": {RequireSession: true},
+	}
+	sess := middleware.NewSession(middleware.SessionConfig{Store: &memSessions{ids: map[string]bool{}}},
+		func(r *http.Request) *router.RouteOptions { return routes[r.URL.Path] })
+	h := sess.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/save/user" {
+			p.Proxy(r.Context(), w, r, "save-user", "http", host, "/v1/session/save", http.MethodPost, nil)
+			return
+		}
+		p.Proxy(r.Context(), w, r, "find-user-info", "http", host, "/v1/session/find/field", http.MethodPost, map[string]string{"field": "name"})
+	}))
+
+	// 클라이언트가 본문에 임의 key 를 보내도 발급된 세션 ID 로 저장
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/save/user", strings.NewReader(`{"key":"client-key","name":"kim"}`)))
+	id := w.Header().Get("X-Fw-Session-Id")
+	if w.Code != http.StatusOK || id == "" {
+		t.Fatalf("save: status %d, session %q, body %s", w.Code, id, w.Body)
+	}
+	if _, ok := saved["client-key"]; ok {
+		t.Error("saved under client-supplied key")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/get/user/name", nil)
+	r.Header.Set("X-Fw-Session-Id", id)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "kim" {
+		t.Errorf("find: status %d, body %q", w.Code, w.Body)
+	}
+}
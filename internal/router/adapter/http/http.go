@@ -3,41 +3,37 @@ package httpadapter
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
-	"net"
+	"log/slog"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"service-gateway/internal/header"
+	"service-gateway/internal/httpx"
+	"service-gateway/internal/logx"
+	"service-gateway/internal/masking"
+	"service-gateway/internal/model"
 	"service-gateway/internal/router"
+	"service-gateway/internal/signing"
 	"strings"
 	"time"
 )
 
-// 아웃바운드 HTTP 클라이언트(커넥션 풀/타임아웃 포함)
-func NewClient(readTO, writeTO, idleTO time.Duration) *http.Client {
-	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   3 * time.Second,
-			KeepAlive: 60 * time.Second,
-		}).DialContext,
-		MaxIdleConns:          100,
-		MaxIdleConnsPerHost:   32,
-		TLSHandshakeTimeout:   3 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
+// 아웃바운드 HTTP 클라이언트 (커넥션 풀은 공유 Transport)
+func NewClient(rt http.RoundTripper, readTO, writeTO time.Duration) *http.Client {
 	return &http.Client{
-		Transport: transport,
+		Transport: rt,
 		Timeout:   readTO + writeTO + 2*time.Second, // 상한선
 	}
 }
 
 type ReverseProxy struct {
-	Client *http.Client
+	Client  *http.Client
+	Logger  *slog.Logger              // nil 이면 slog.Default
+	Signers map[string]signing.Signer // 라우트 이름 → 아웃바운드 서명 (routes[].backend.signing)
+	Bodies  map[string]BodyMap        // 라우트 이름 → 업스트림 본문 매핑 (routes[].backend.body)
 }
 
 // patch rewrite + proxy
@@ -49,7 +45,9 @@ func (p *ReverseProxy) Proxy(ctx context.Context, w http.ResponseWriter, r *http
 	//	path := BuildUpstreamPath(rt, req.URL.Path, params)
 	// scheme/host 조합해서 최종 target URL 구성
 
-	outReq := r.Clone(ctx)
+	lg := logx.Or(p.Logger)
+	logx.SetUpstream(ctx, host)
+	outReq := r.Clone(WithTLSProfile(ctx, RouteProfile(routeName))) // routes[].backend.tls
 	outReq.URL = target.ResolveReference(&url.URL{Path: pathRewrite})
 	outReq.RequestURI = "" // net/http requirement
 	outReq.Method = method
@@ -61,50 +59,15 @@ func (p *ReverseProxy) Proxy(ctx context.Context, w http.ResponseWriter, r *http
 	}
 	defer r.Body.Close()
 
-	/**
-	// key가 있으면 수정
-	if len(bodyBytes) > 0 {
-		var bodyMap map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &bodyMap); err == nil {
-			if keyVal, ok := bodyMap["key"].(string); ok {
-				// 요청 헤더에서 X-Fw-Session-Id 읽기
-				sessionID := r.Header.Get("X-Fw-Session-Id")
-				if sessionID != "" {
-					// key 값에 prefix 붙임
-					bodyMap["key"] = sessionID + keyVal
-				}
-			}
-			// 수정된 JSON 다시 직렬화
-			bodyBytes, _ = json.Marshal(bodyMap)
-		}
-	}
-	*/
-	var sessionID string
-	if routeName == "save-user" && len(bodyBytes) > 0 {
-		var bodyMap map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &bodyMap); err == nil {
-			if v, ok := bodyMap["key"].(string); ok && v != "" {
-				// 요청 헤더에서 X-Fw-Session-Id 읽기
-				sessionID = v
-			}
-			log.Printf("session ID : %s", sessionID)
-			// 수정된 JSON 다시 직렬화
-			//bodyBytes, _ = json.Marshal(bodyMap)
-		}
-	} else if routeName == "find-user-info" {
-		if r.Header.Get("X-Fw-Session-Id") == "" {
-			log.Printf("X - Session - id is null")
-			http.Error(w, "X - Session - id is null ", http.StatusBadGateway)
+	// 본문 매핑: 클라이언트 JSON 본문에 경로 변수 / 헤더 값을 덮어씀
+	if bm := p.Bodies[routeName]; bm != nil {
+		b, err := bm.build(r.Header, params, bodyBytes)
+		if err != nil {
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeBadRequest, err))
 			return
 		}
-
-		// 생성
-		bodyMap := make(map[string]interface{})
-		bodyMap["key"] = r.Header.Get("X-Fw-Session-Id")
-		bodyMap["field"] = params["field"]
-
-		bodyBytes, _ = json.Marshal(bodyMap)
-
+		bodyBytes = b
+		outReq.Header.Set("Content-Type", "application/json")
 	}
 
 	// 새로운 body 세팅
@@ -126,22 +89,36 @@ func (p *ReverseProxy) Proxy(ctx context.Context, w http.ResponseWriter, r *http
 	outReq.Header.Del("Transfer-Encoding")
 	outReq.Header.Del("Upgrade")
 
-	// DEBUG: 실제 전송 직전 덤프
-	if dump, err := httputil.DumpRequestOut(outReq, true); err == nil {
-		log.Printf("[OUTBOUND]\n%s", dump)
+	// 서명: 바디/헤더 변환이 모두 끝난 뒤 (이후 outReq 변경 금지)
+	if sg := p.Signers[routeName]; sg != nil {
+		if err := sg.Sign(outReq, bodyBytes); err != nil {
+			httpx.WriteError(w, r, httpx.Err(model.ErrCodeInternal, err))
+			return
+		}
+	}
+
+	// DEBUG: 실제 전송 직전 덤프 (debug 레벨에서만 생성)
+	if lg.Enabled(ctx, slog.LevelDebug) {
+		if dump, err := httputil.DumpRequestOut(outReq, true); err == nil {
+			lg.DebugContext(ctx, "outbound request", "dump", string(masking.Default().Dump(dump)))
+		}
 	}
 
 	resp, err := p.Client.Do(outReq)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("upstream error : %v", err), http.StatusBadGateway)
+		code := model.ErrCodeUpstreamFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			code = model.ErrCodeUpstreamTimeout
+		}
+		httpx.WriteError(w, r, httpx.Err(code, err))
 		return
 	}
 	defer resp.Body.Close()
 
 	// 클라가 보낸 원본
 
-	for k, v := range resp.Header {
-		log.Printf("resp. Header: %s=%s", k, v)
+	if lg.Enabled(ctx, slog.LevelDebug) {
+		lg.DebugContext(ctx, "upstream response", "status", resp.StatusCode, "header", masking.Default().Header(resp.Header))
 	}
 
 	fwHeader := resp.Header.Get("X-Fw-Header")
@@ -150,22 +127,9 @@ func (p *ReverseProxy) Proxy(ctx context.Context, w http.ResponseWriter, r *http
 	// 업스트림 응답 헤더를 먼저 복사
 	copyHeader(w.Header(), resp.Header)
 
-	// 세션 ID 발급 및 응답 헤더/쿠키에 추가
-	// sessionID := getOrCreateSessionID(r)
-	w.Header().Set("X-Fw-Session-Id", sessionID)
+	// 세션 ID 는 세션 미들웨어가 응답 헤더에 설정 (require_session / generate_if_missing)
 	w.Header().Set("X-Fw-Header", bumped)
 
-	/**
-	http.SetCookie(w, &http.Cookie{
-		Name:     "JSESSIONID",
-		Value:    sessionID,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   false,
-		SameSite: http.SameSiteLaxMode,
-	})
-	*/
-
 	w.WriteHeader(resp.StatusCode)
 	_, _ = io.Copy(w, resp.Body)
 }
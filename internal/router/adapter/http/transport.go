@@ -0,0 +1,95 @@
+package httpadapter
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/*
+공유 아웃바운드 Transport
+
+WHY:
+- ReverseProxy(YAML/코드 라우트)와 DynamicGateway(/gateway) 가 각자 Transport 를 만들어
+  커넥션 풀이 분리되고, 업스트림별 TLS(사설 CA, 클라이언트 인증서)를 지정할 곳이 없었음.
+
+동작:
+- 기본 풀 1개 + TLS 프로파일(라우트 / API 그룹)별 풀 (TLS 설정이 다르면 커넥션 재사용 불가 → 풀 분리)
+- 호출 측이 WithTLSProfile(ctx, RouteProfile(name) | GroupProfile(cd)) 로 지정, 등록 안 된 프로파일은 기본 풀
+*/
+
+type Transport struct {
+	base *http.Transport
+
+	mu       sync.RWMutex
+	profiles map[string]*http.Transport
+}
+
+// NewTransport: 커넥션 풀/타임아웃 기본값 (idleTO 0 이면 90s)
+func NewTransport(idleTO time.Duration) *Transport {
+	if idleTO <= 0 {
+		idleTO = 90 * time.Second
+	}
+	return &Transport{
+		base: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   3 * time.Second,
+				KeepAlive: 60 * time.Second,
+			}).DialContext,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   32,
+			IdleConnTimeout:       idleTO,
+			TLSHandshakeTimeout:   3 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+		},
+		profiles: map[string]*http.Transport{},
+	}
+}
+
+// SetTLS: 프로파일 TLS 등록 (기본 풀 설정 복제 + TLSClientConfig)
+func (t *Transport) SetTLS(profile string, cfg *tls.Config) {
+	tr := t.base.Clone()
+	tr.TLSClientConfig = cfg
+	t.mu.Lock()
+	t.profiles[profile] = tr
+	t.mu.Unlock()
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if p, ok := req.Context().Value(profileKey{}).(string); ok {
+		t.mu.RLock()
+		tr, ok := t.profiles[p]
+		t.mu.RUnlock()
+		if ok {
+			return tr.RoundTrip(req)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// CloseIdleConnections: 종료 시 유휴 커넥션 정리 (http.Client.CloseIdleConnections 에서 호출)
+func (t *Transport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, tr := range t.profiles {
+		tr.CloseIdleConnections()
+	}
+}
+
+type profileKey struct{}
+
+// WithTLSProfile: 이 요청의 업스트림 TLS 프로파일
+func WithTLSProfile(ctx context.Context, profile string) context.Context {
+	return context.WithValue(ctx, profileKey{}, profile)
+}
+
+// RouteProfile: routes[].backend.tls 프로파일 이름
+func RouteProfile(routeName string) string { return "route:" + routeName }
+
+// GroupProfile: API 그룹(API_GRP_CD) 프로파일 이름
+func GroupProfile(groupCd string) string { return "group:" + groupCd }
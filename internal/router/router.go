@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 	"regexp"
+	"service-gateway/internal/ipfilter"
 	"strings"
 )
 
@@ -25,6 +26,9 @@ type Backend struct {
 type RouteOptions struct {
 	RequireSession    bool
 	GenerateIfMissing bool
+	Scopes            []string          // JWT scope/scp 에 모두 포함되어야 함
+	Claims            map[string]string // JWT 클레임 값 일치 (배열 클레임은 포함 여부)
+	IPFilter          *ipfilter.List    // 클라이언트 IP 허용/차단 (nil 이면 전체 허용)
 }
 
 type Route struct {
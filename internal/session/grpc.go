@@ -0,0 +1,167 @@
+package session
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	pb "service-gateway/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+/*
+gRPC 세션 저장소 (proto SessionService)
+
+WHY:
+- session-service 는 SessionService(gRPC)를 제공하는데 게이트웨이는 임시 HTTP 경로로 호출했음
+  → 생성된 SessionServiceClient 로 직접 호출.
+
+- 조회 : GetSession{key}                    → success=false / NotFound 는 없는 세션
+- 생성 : CreateSession{key, value:"", ttl:초} → success=false 는 에러
+- 커넥션 풀 : pool_size 개 ClientConn 라운드로빈 (HTTP/2 스트림 상한 분산), keepalive
+- deadline : 시도 1건마다 timeout, 전체는 호출자 ctx
+- 재시도 : Unavailable / DeadlineExceeded / ResourceExhausted 만, backoff 지수 증가
+*/
+
+type GRPCConfig struct {
+	Target   string
+	PoolSize int           // 기본 1
+	Timeout  time.Duration // 시도 1건 deadline (기본 2s)
+	Retries  int           // 재시도 횟수 (기본 2, 음수면 재시도 안 함)
+	Backoff  time.Duration // 첫 재시도 대기 (기본 100ms, 이후 2배)
+	TLS      *tls.Config   // nil 이면 평문
+}
+
+type GRPCStore struct {
+	cfg     GRPCConfig
+	conns   []*grpc.ClientConn
+	clients []pb.SessionServiceClient
+	next    atomic.Uint32
+}
+
+// NewGRPCStore: 연결은 첫 호출 시 수립 (grpc.NewClient), opts 는 테스트용 dialer 등 추가 옵션
+func NewGRPCStore(cfg GRPCConfig, opts ...grpc.DialOption) (*GRPCStore, error) {
+	if cfg.Target == "" {
+		return nil, errors.New("session.grpc: target required")
+	}
+	cfg = cfg.withDefaults()
+	creds := insecure.NewCredentials()
+	if cfg.TLS != nil {
+		creds = credentials.NewTLS(cfg.TLS)
+	}
+	opts = append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 30 * time.Second, Timeout: 10 * time.Second}),
+	}, opts...)
+
+	s := &GRPCStore{cfg: cfg}
+	for range cfg.PoolSize {
+		cc, err := grpc.NewClient(cfg.Target, opts...)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("session.grpc: %w", err)
+		}
+		s.conns = append(s.conns, cc)
+		s.clients = append(s.clients, pb.NewSessionServiceClient(cc))
+	}
+	return s, nil
+}
+
+// NewGRPCStoreClient: 이미 만든 클라이언트로 구성 (풀 / Close 는 호출자 책임)
+func NewGRPCStoreClient(client pb.SessionServiceClient, cfg GRPCConfig) *GRPCStore {
+	return &GRPCStore{cfg: cfg.withDefaults(), clients: []pb.SessionServiceClient{client}}
+}
+
+func (c GRPCConfig) withDefaults() GRPCConfig {
+	if c.PoolSize <= 0 {
+		c.PoolSize = 1
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 2 * time.Second
+	}
+	if c.Retries == 0 {
+		c.Retries = 2
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = 100 * time.Millisecond
+	}
+	return c
+}
+
+func (s *GRPCStore) Exists(ctx context.Context, id string) (bool, error) {
+	var resp *pb.GenericResponse
+	err := s.do(ctx, func(ctx context.Context, c pb.SessionServiceClient) (err error) {
+		resp, err = c.GetSession(ctx, &pb.GetSessionRequest{Key: id})
+		return err
+	})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("session.grpc get: %w", err)
+	}
+	return resp.GetSuccess(), nil
+}
+
+func (s *GRPCStore) Create(ctx context.Context, id string, ttl time.Duration) error {
+	var resp *pb.GenericResponse
+	err := s.do(ctx, func(ctx context.Context, c pb.SessionServiceClient) (err error) {
+		resp, err = c.CreateSession(ctx, &pb.CreateSessionRequest{Key: id, Ttl: int64(ttl / time.Second)})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("session.grpc create: %w", err)
+	}
+	if !resp.GetSuccess() {
+		return fmt.Errorf("session.grpc create: rejected: %s", resp.GetMessage())
+	}
+	return nil
+}
+
+// Close: NewGRPCStore 로 만든 커넥션 정리
+func (s *GRPCStore) Close() error {
+	var errs []error
+	for _, cc := range s.conns {
+		errs = append(errs, cc.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// do: 시도마다 다음 커넥션 + 개별 deadline, 재시도 가능한 코드만 backoff 후 재시도
+func (s *GRPCStore) do(ctx context.Context, call func(context.Context, pb.SessionServiceClient) error) error {
+	wait := s.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		c := s.clients[int(s.next.Add(1)-1)%len(s.clients)]
+		actx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+		err := call(actx, c)
+		cancel()
+		if err == nil || attempt >= s.cfg.Retries || !retryable(err) {
+			return err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+		wait *= 2
+	}
+}
+
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
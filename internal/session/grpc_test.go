@@ -0,0 +1,215 @@
+package session_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"service-gateway/internal/session"
+	"service-gateway/internal/session/sessiontest"
+	pb "service-gateway/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func start(t *testing.T, cfg session.GRPCConfig) (*sessiontest.FakeServer, *session.GRPCStore) {
+	t.Helper()
+	fake, store, stop, err := sessiontest.Start(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(stop)
+	return fake, store
+}
+
+func TestGRPCStoreExists(t *testing.T) {
+	fake, store := start(t, session.GRPCConfig{Backoff: time.Millisecond})
+	fake.Put("sess-1", "", 0)
+	ctx := context.Background()
+
+	if ok, err := store.Exists(ctx, "sess-1"); !ok || err != nil {
+		t.Errorf("found: %v %v", ok, err)
+	}
+	if ok, err := store.Exists(ctx, "missing"); ok || err != nil {
+		t.Errorf("missing (success=false): %v %v", ok, err)
+	}
+	fake.FailNext(1, codes.NotFound)
+	if ok, err := store.Exists(ctx, "sess-1"); ok || err != nil {
+		t.Errorf("NotFound: %v %v", ok, err)
+	}
+}
+
+func TestGRPCStoreCreate(t *testing.T) {
+	fake, store := start(t, session.GRPCConfig{Backoff: time.Millisecond})
+	ctx := context.Background()
+
+	if err := store.Create(ctx, "sess-2", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := store.Exists(ctx, "sess-2"); !ok {
+		t.Error("created session not found")
+	}
+	// 빈 key 는 FakeServer 가 success=false 로 응답
+	err := store.Create(ctx, "", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "rejected: key required") {
+		t.Errorf("rejected create: %v", err)
+	}
+	if n := fake.Calls(); n != 3 {
+		t.Errorf("calls = %d, want 3 (success=false is not retried)", n)
+	}
+}
+
+func TestGRPCStoreRetry(t *testing.T) {
+	cases := []struct {
+		name      string
+		retries   int
+		fail      int
+		code      codes.Code
+		wantErr   codes.Code // OK 면 성공
+		wantCalls int
+	}{
+		{"unavailable within retries", 2, 2, codes.Unavailable, codes.OK, 3},
+		{"resource exhausted within retries", 1, 1, codes.ResourceExhausted, codes.OK, 2},
+		{"unavailable beyond retries", 2, 3, codes.Unavailable, codes.Unavailable, 3},
+		{"no retry when disabled", -1, 1, codes.Unavailable, codes.Unavailable, 1},
+		{"no retry on invalid argument", 2, 1, codes.InvalidArgument, codes.InvalidArgument, 1},
+		{"no retry on permission denied", 2, 1, codes.PermissionDenied, codes.PermissionDenied, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			fake, store := start(t, session.GRPCConfig{Retries: c.retries, Backoff: time.Millisecond})
+			fake.FailNext(c.fail, c.code)
+			err := store.Create(context.Background(), "sess", time.Minute)
+			if got := status.Code(err); got != c.wantErr {
+				t.Errorf("err = %v, want %s", err, c.wantErr)
+			}
+			if n := fake.Calls(); n != c.wantCalls {
+				t.Errorf("calls = %d, want %d", n, c.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGRPCStoreRetryStopsOnCallerCancel(t *testing.T) {
+	fake, store := start(t, session.GRPCConfig{Retries: 5, Backoff: time.Hour})
+	fake.FailNext(10, codes.Unavailable)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	_, err := store.Exists(ctx, "sess")
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("err = %v", err)
+	}
+	if time.Since(begin) > 5*time.Second || fake.Calls() != 1 {
+		t.Errorf("backoff not interrupted by caller ctx: %s, %d calls", time.Since(begin), fake.Calls())
+	}
+}
+
+// dialStore: FakeServer + 클라이언트 인터셉터를 끼운 GRPCStore (풀 / deadline 관찰용)
+func dialStore(t *testing.T, cfg session.GRPCConfig, icpt grpc.UnaryClientInterceptor) (*sessiontest.FakeServer, *session.GRPCStore) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	fake := sessiontest.NewFakeServer()
+	pb.RegisterSessionServiceServer(srv, fake)
+	go func() { _ = srv.Serve(lis) }()
+
+	cfg.Target = "passthrough:///bufconn"
+	store, err := session.NewGRPCStore(cfg,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithUnaryInterceptor(icpt),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+		srv.Stop()
+	})
+	return fake, store
+}
+
+func TestGRPCStoreRoundRobin(t *testing.T) {
+	var mu sync.Mutex
+	var seq []*grpc.ClientConn
+	_, store := dialStore(t, session.GRPCConfig{PoolSize: 3}, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		mu.Lock()
+		seq = append(seq, cc)
+		mu.Unlock()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	})
+
+	for range 6 {
+		if _, err := store.Exists(context.Background(), "sess"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	distinct := map[*grpc.ClientConn]bool{}
+	for i, cc := range seq {
+		distinct[cc] = true
+		if i >= 3 && cc != seq[i-3] {
+			t.Errorf("call %d used a different conn than call %d", i, i-3)
+		}
+	}
+	if len(distinct) != 3 {
+		t.Errorf("distinct conns = %d, want 3", len(distinct))
+	}
+}
+
+func TestGRPCStorePerAttemptDeadline(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	var mu sync.Mutex
+	var starts, deadlines []time.Time
+	// 서버 무응답 흉내: deadline 까지 대기 후 DeadlineExceeded
+	fake, store := dialStore(t, session.GRPCConfig{Timeout: timeout, Retries: 1, Backoff: time.Millisecond},
+		func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ grpc.UnaryInvoker, _ ...grpc.CallOption) error {
+			dl, _ := ctx.Deadline()
+			mu.Lock()
+			starts, deadlines = append(starts, time.Now()), append(deadlines, dl)
+			mu.Unlock()
+			<-ctx.Done()
+			return status.FromContextError(ctx.Err()).Err()
+		})
+
+	_, err := store.Exists(context.Background(), "sess")
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("err = %v", err)
+	}
+	if len(deadlines) != 2 {
+		t.Fatalf("attempts = %d, want 2", len(deadlines))
+	}
+	for i := range deadlines {
+		if d := deadlines[i].Sub(starts[i]); d <= 0 || d > timeout {
+			t.Errorf("attempt %d deadline %s after start, want <= %s", i, d, timeout)
+		}
+	}
+	if !deadlines[1].After(deadlines[0]) {
+		t.Error("retry reused the first attempt's deadline")
+	}
+	if fake.Calls() != 0 {
+		t.Errorf("server calls = %d", fake.Calls())
+	}
+
+	// 호출자 deadline 이 더 짧으면 그쪽이 적용
+	deadlines = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	_, _ = store.Exists(ctx, "sess")
+	if len(deadlines) != 1 || !deadlines[0].Equal(want) {
+		t.Errorf("caller deadline not applied: %v, want [%v]", deadlines, want)
+	}
+}
+
+func TestNewGRPCStoreRequiresTarget(t *testing.T) {
+	if _, err := session.NewGRPCStore(session.GRPCConfig{}); err == nil {
+		t.Error("empty target accepted")
+	}
+}
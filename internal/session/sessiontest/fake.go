@@ -0,0 +1,157 @@
+package sessiontest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"service-gateway/internal/session"
+	pb "service-gateway/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+/*
+테스트용 인프로세스 SessionService (net/http/httptest 처럼 운영 바이너리와 분리)
+
+- 메모리 map + TTL (ttl 0 이면 만료 없음), WithTarget 계열은 host 무시
+- FailNext(n, code): 다음 n 건을 지정 코드로 실패 (재시도 / 장애 경로 확인)
+- Start: bufconn 위에 gRPC 서버를 띄우고 연결된 GRPCStore 반환 (네트워크 불필요)
+*/
+
+type FakeServer struct {
+	pb.UnimplementedSessionServiceServer
+
+	mu      sync.Mutex
+	data    map[string]fakeEntry
+	failN   int
+	failErr error
+	calls   int
+	now     func() time.Time
+}
+
+type fakeEntry struct {
+	value   string
+	expires time.Time
+}
+
+func NewFakeServer() *FakeServer {
+	return &FakeServer{data: map[string]fakeEntry{}, now: time.Now}
+}
+
+// FailNext: 다음 n 건 호출을 code 로 실패
+func (f *FakeServer) FailNext(n int, code codes.Code) {
+	f.mu.Lock()
+	f.failN, f.failErr = n, status.Error(code, "fake: injected failure")
+	f.mu.Unlock()
+}
+
+// Calls: 받은 호출 수 (실패 주입 포함)
+func (f *FakeServer) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+// Put: 세션 직접 적재
+func (f *FakeServer) Put(key, value string, ttl time.Duration) {
+	f.mu.Lock()
+	f.put(key, value, ttl)
+	f.mu.Unlock()
+}
+
+func (f *FakeServer) SetSession(_ context.Context, r *pb.SetSessionRequest) (*pb.GenericResponse, error) {
+	return f.set(r.GetKey(), r.GetValue(), r.GetTtl())
+}
+
+func (f *FakeServer) CreateSession(_ context.Context, r *pb.CreateSessionRequest) (*pb.GenericResponse, error) {
+	return f.set(r.GetKey(), r.GetValue(), r.GetTtl())
+}
+
+func (f *FakeServer) SetSessionWithTarget(_ context.Context, r *pb.SetSessionWithTargetRequest) (*pb.GenericResponse, error) {
+	return f.set(r.GetKey(), r.GetValue(), r.GetTtl())
+}
+
+func (f *FakeServer) GetSession(_ context.Context, r *pb.GetSessionRequest) (*pb.GenericResponse, error) {
+	return f.get(r.GetKey())
+}
+
+func (f *FakeServer) GetSessionWithTarget(_ context.Context, r *pb.GetSessionWithTargetRequest) (*pb.GenericResponse, error) {
+	return f.get(r.GetKey())
+}
+
+func (f *FakeServer) set(key, value string, ttlSec int64) (*pb.GenericResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return &pb.GenericResponse{Success: false, Message: "key required"}, nil
+	}
+	f.put(key, value, time.Duration(ttlSec)*time.Second)
+	return &pb.GenericResponse{Success: true}, nil
+}
+
+func (f *FakeServer) get(key string) (*pb.GenericResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	e, ok := f.data[key]
+	if !ok || (!e.expires.IsZero() && f.now().After(e.expires)) {
+		delete(f.data, key)
+		return &pb.GenericResponse{Success: false}, nil
+	}
+	return &pb.GenericResponse{Success: true, Message: e.value}, nil
+}
+
+func (f *FakeServer) put(key, value string, ttl time.Duration) {
+	e := fakeEntry{value: value}
+	if ttl > 0 {
+		e.expires = f.now().Add(ttl)
+	}
+	f.data[key] = e
+}
+
+// fail: 호출 수 집계 + 주입된 실패 소비 (mu 보유 상태에서 호출)
+func (f *FakeServer) fail() error {
+	f.calls++
+	if f.failN > 0 {
+		f.failN--
+		return f.failErr
+	}
+	return nil
+}
+
+// Start: bufconn 위 FakeServer + 연결된 GRPCStore, stop 으로 서버/연결 정리
+func Start(cfg session.GRPCConfig) (fake *FakeServer, store *session.GRPCStore, stop func(), err error) {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	fake = NewFakeServer()
+	pb.RegisterSessionServiceServer(srv, fake)
+	go func() { _ = srv.Serve(lis) }()
+
+	if cfg.Target == "" {
+		cfg.Target = "passthrough:///bufconn"
+	}
+	store, err = session.NewGRPCStore(cfg,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		srv.Stop()
+		return nil, nil, nil, err
+	}
+	stop = func() {
+		_ = store.Close()
+		srv.Stop()
+	}
+	return fake, store, stop, nil
+}
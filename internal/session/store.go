@@ -0,0 +1,104 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/*
+세션 저장소 (session-service)
+
+WHY:
+- 세션 처리가 ReverseProxy.Proxy 안에 라우트 이름(save-user, find-user-info)으로 하드코딩되어
+  routes[].options.require_session / generate_if_missing 가 파싱만 되고 쓰이지 않았음.
+- 게이트웨이는 세션 ID 의 존재 확인 / 신규 적재만 필요 → 최소 인터페이스로 분리.
+
+HTTPStore:
+- 조회 : POST {base_url}{get_path}    {"key": id}                       → 200 {"success": true|false}
+- 생성 : POST {base_url}{create_path} {"key": id, "value": "", "ttl": 초} → 2xx {"success": true}
+- 404 / success=false 는 없는 세션, 그 외 비 2xx / 호출 실패는 에러
+*/
+
+// Store: 세션 존재 확인 / 생성
+type Store interface {
+	Exists(ctx context.Context, id string) (bool, error)
+	Create(ctx context.Context, id string, ttl time.Duration) error
+}
+
+type HTTPStore struct {
+	client     *http.Client
+	getURL     string
+	createURL  string
+	reqTimeout time.Duration
+}
+
+// NewHTTPStore: timeout 은 호출 1건 상한 (0 이면 client 설정만 사용)
+func NewHTTPStore(client *http.Client, baseURL, getPath, createPath string, timeout time.Duration) (*HTTPStore, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("session.http: base_url required")
+	}
+	if getPath == "" || createPath == "" {
+		return nil, fmt.Errorf("session.http: get_path and create_path required")
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base := strings.TrimSuffix(baseURL, "/")
+	return &HTTPStore{client: client, getURL: base + getPath, createURL: base + createPath, reqTimeout: timeout}, nil
+}
+
+func (s *HTTPStore) Exists(ctx context.Context, id string) (bool, error) {
+	return s.call(ctx, s.getURL, map[string]any{"key": id})
+}
+
+func (s *HTTPStore) Create(ctx context.Context, id string, ttl time.Duration) error {
+	ok, err := s.call(ctx, s.createURL, map[string]any{"key": id, "value": "", "ttl": int64(ttl / time.Second)})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("session: create rejected")
+	}
+	return nil
+}
+
+// call: success 필드가 없는 2xx 응답은 성공으로 간주
+func (s *HTTPStore) call(ctx context.Context, url string, body map[string]any) (bool, error) {
+	if s.reqTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.reqTimeout)
+		defer cancel()
+	}
+	b, _ := json.Marshal(body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("session: %w", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode/100 != 2:
+		return false, fmt.Errorf("session: %s returned %d", url, resp.StatusCode)
+	}
+	var r struct {
+		Success *bool `json:"success"`
+	}
+	if json.Unmarshal(raw, &r) != nil || r.Success == nil {
+		return true, nil
+	}
+	return *r.Success, nil
+}
@@ -0,0 +1,432 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type adminRepository struct {
+	db *sql.DB
+}
+
+// NewAdmin: 관리 API 전용 리포지토리 (게이트웨이 요청 경로와 커넥션 풀 분리)
+func NewAdmin(cfg Config) (store.AdminRepository, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(5)
+	return &adminRepository{db: db}, nil
+}
+
+func (r *adminRepository) Close() error {
+	return r.db.Close()
+}
+
+// ==== API GROUP ====
+
+const groupCols = `API_GROUP_CD, IFNULL(API_GROUP_NM, ''), USG_YN, API_GROUP_CLOT_CTL_CD, IFNULL(API_GROUP_CLOT_UABL_STA_TIM, ''), IFNULL(API_GROUP_CLOT_UABL_END_TIM, '')`
+
+func scanGroup(row interface{ Scan(...any) error }) (model.ApiGroup, error) {
+	var g model.ApiGroup
+	err := row.Scan(&g.ApiGroupCode, &g.ApiGroupName, &g.UseYn, &g.ClotCtlCode, &g.ClotUablStaTim, &g.ClotUablEndTim)
+	return g, err
+}
+
+func (r *adminRepository) ListGroups(ctx context.Context) ([]model.ApiGroup, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+groupCols+` FROM SID_API_GRP_MNG ORDER BY API_GROUP_CD`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.ApiGroup{}
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+func (r *adminRepository) GetGroup(ctx context.Context, groupCd string) (model.ApiGroup, error) {
+	return getGroup(ctx, r.db, groupCd, false)
+}
+
+func getGroup(ctx context.Context, q queryer, groupCd string, forUpdate bool) (model.ApiGroup, error) {
+	sqlStr := `SELECT ` + groupCols + ` FROM SID_API_GRP_MNG WHERE API_GROUP_CD = ?`
+	if forUpdate {
+		sqlStr += ` FOR UPDATE`
+	}
+	g, err := scanGroup(q.QueryRowContext(ctx, sqlStr, groupCd))
+	if err == sql.ErrNoRows {
+		return g, store.ErrNotFound
+	}
+	return g, err
+}
+
+func (r *adminRepository) CreateGroup(ctx context.Context, g model.ApiGroup, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "CREATE", "API_GROUP", g.ApiGroupCode, func(tx *sql.Tx) (any, any, error) {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_API_GRP_MNG (API_GROUP_CD, API_GROUP_NM, USG_YN, API_GROUP_CLOT_CTL_CD, API_GROUP_CLOT_UABL_STA_TIM, API_GROUP_CLOT_UABL_END_TIM)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			g.ApiGroupCode, g.ApiGroupName, g.UseYn, g.ClotCtlCode, nullIfEmpty(g.ClotUablStaTim), nullIfEmpty(g.ClotUablEndTim))
+		return nil, g, err
+	})
+}
+
+func (r *adminRepository) UpdateGroup(ctx context.Context, g model.ApiGroup, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "UPDATE", "API_GROUP", g.ApiGroupCode, func(tx *sql.Tx) (any, any, error) {
+		before, err := getGroup(ctx, tx, g.ApiGroupCode, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE SID_API_GRP_MNG SET API_GROUP_NM = ?, USG_YN = ?, API_GROUP_CLOT_CTL_CD = ?, API_GROUP_CLOT_UABL_STA_TIM = ?, API_GROUP_CLOT_UABL_END_TIM = ?
+			 WHERE API_GROUP_CD = ?`,
+			g.ApiGroupName, g.UseYn, g.ClotCtlCode, nullIfEmpty(g.ClotUablStaTim), nullIfEmpty(g.ClotUablEndTim), g.ApiGroupCode)
+		return before, g, err
+	})
+}
+
+// DeleteGroup: 물리 삭제 대신 USG_YN = 'N' (이력/관계 데이터 보존)
+func (r *adminRepository) DeleteGroup(ctx context.Context, groupCd string, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "DELETE", "API_GROUP", groupCd, func(tx *sql.Tx) (any, any, error) {
+		before, err := getGroup(ctx, tx, groupCd, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE SID_API_GRP_MNG SET USG_YN = 'N' WHERE API_GROUP_CD = ?`, groupCd)
+		after := before
+		after.UseYn = "N"
+		return before, after, err
+	})
+}
+
+// ==== API DETAIL ====
+
+const apiCols = `API_GROUP_CD, API_CD, IFNULL(API_NM, ''), API_PATH, API_TYP_CD, IFNULL(TARGET_URI, ''), USG_YN, API_CLOT_CTL_CD, IFNULL(API_CLOT_UABL_STA_TIM, ''), IFNULL(API_CLOT_UABL_END_TIM, '')`
+
+func scanApi(row interface{ Scan(...any) error }) (model.ApiDetail, error) {
+	var a model.ApiDetail
+	err := row.Scan(&a.ApiGroupCode, &a.ApiCode, &a.ApiName, &a.ApiPath, &a.ApiTypeCode, &a.TargetURI,
+		&a.UseYn, &a.ClotCtlCode, &a.ClotUablStaTim, &a.ClotUablEndTim)
+	return a, err
+}
+
+func (r *adminRepository) ListApis(ctx context.Context, groupCd string) ([]model.ApiDetail, error) {
+	q := `SELECT ` + apiCols + ` FROM SID_API_DTL_MNG`
+	var args []any
+	if groupCd != "" {
+		q += ` WHERE API_GROUP_CD = ?`
+		args = append(args, groupCd)
+	}
+	q += ` ORDER BY API_GROUP_CD, API_CD`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.ApiDetail{}
+	for rows.Next() {
+		a, err := scanApi(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *adminRepository) GetApi(ctx context.Context, groupCd, apiCd string) (model.ApiDetail, error) {
+	return getApi(ctx, r.db, groupCd, apiCd, false)
+}
+
+func getApi(ctx context.Context, q queryer, groupCd, apiCd string, forUpdate bool) (model.ApiDetail, error) {
+	sqlStr := `SELECT ` + apiCols + ` FROM SID_API_DTL_MNG WHERE API_GROUP_CD = ? AND API_CD = ?`
+	if forUpdate {
+		sqlStr += ` FOR UPDATE`
+	}
+	a, err := scanApi(q.QueryRowContext(ctx, sqlStr, groupCd, apiCd))
+	if err == sql.ErrNoRows {
+		return a, store.ErrNotFound
+	}
+	return a, err
+}
+
+func (r *adminRepository) CreateApi(ctx context.Context, a model.ApiDetail, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "CREATE", "API", a.ApiGroupCode+"/"+a.ApiCode, func(tx *sql.Tx) (any, any, error) {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_API_DTL_MNG (API_GROUP_CD, API_CD, API_NM, API_PATH, API_TYP_CD, TARGET_URI, USG_YN, API_CLOT_CTL_CD, API_CLOT_UABL_STA_TIM, API_CLOT_UABL_END_TIM)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			a.ApiGroupCode, a.ApiCode, a.ApiName, a.ApiPath, a.ApiTypeCode, nullIfEmpty(a.TargetURI), a.UseYn,
+			a.ClotCtlCode, nullIfEmpty(a.ClotUablStaTim), nullIfEmpty(a.ClotUablEndTim))
+		return nil, a, err
+	})
+}
+
+func (r *adminRepository) UpdateApi(ctx context.Context, a model.ApiDetail, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "UPDATE", "API", a.ApiGroupCode+"/"+a.ApiCode, func(tx *sql.Tx) (any, any, error) {
+		before, err := getApi(ctx, tx, a.ApiGroupCode, a.ApiCode, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE SID_API_DTL_MNG SET API_NM = ?, API_PATH = ?, API_TYP_CD = ?, TARGET_URI = ?, USG_YN = ?, API_CLOT_CTL_CD = ?, API_CLOT_UABL_STA_TIM = ?, API_CLOT_UABL_END_TIM = ?
+			 WHERE API_GROUP_CD = ? AND API_CD = ?`,
+			a.ApiName, a.ApiPath, a.ApiTypeCode, nullIfEmpty(a.TargetURI), a.UseYn, a.ClotCtlCode,
+			nullIfEmpty(a.ClotUablStaTim), nullIfEmpty(a.ClotUablEndTim), a.ApiGroupCode, a.ApiCode)
+		return before, a, err
+	})
+}
+
+// DeleteApi: USG_YN = 'N' 처리
+func (r *adminRepository) DeleteApi(ctx context.Context, groupCd, apiCd string, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "DELETE", "API", groupCd+"/"+apiCd, func(tx *sql.Tx) (any, any, error) {
+		before, err := getApi(ctx, tx, groupCd, apiCd, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE SID_API_DTL_MNG SET USG_YN = 'N' WHERE API_GROUP_CD = ? AND API_CD = ?`, groupCd, apiCd)
+		after := before
+		after.UseYn = "N"
+		return before, after, err
+	})
+}
+
+// ==== BIZ SERVICE PERMISSION ====
+
+func (r *adminRepository) ListPermissions(ctx context.Context, bizCd string) ([]model.BizSrvcPermission, error) {
+	q := `SELECT BIZ_SRVC_CD, API_GROUP_CD, API_CD, USG_YN FROM SID_BIZ_SRVC_API_RLP`
+	var args []any
+	if bizCd != "" {
+		q += ` WHERE BIZ_SRVC_CD = ?`
+		args = append(args, bizCd)
+	}
+	q += ` ORDER BY BIZ_SRVC_CD, API_GROUP_CD, API_CD`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.BizSrvcPermission{}
+	for rows.Next() {
+		var p model.BizSrvcPermission
+		if err := rows.Scan(&p.BizServiceCode, &p.ApiGroupCode, &p.ApiCode, &p.UseYn); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func getPermission(ctx context.Context, q queryer, bizCd, groupCd, apiCd string) (model.BizSrvcPermission, error) {
+	var p model.BizSrvcPermission
+	err := q.QueryRowContext(ctx,
+		`SELECT BIZ_SRVC_CD, API_GROUP_CD, API_CD, USG_YN FROM SID_BIZ_SRVC_API_RLP
+		 WHERE BIZ_SRVC_CD = ? AND API_GROUP_CD = ? AND API_CD = ? FOR UPDATE`,
+		bizCd, groupCd, apiCd).Scan(&p.BizServiceCode, &p.ApiGroupCode, &p.ApiCode, &p.UseYn)
+	if err == sql.ErrNoRows {
+		return p, store.ErrNotFound
+	}
+	return p, err
+}
+
+// GrantPermission: 없으면 생성, 회수(N) 상태면 Y 로 복구
+func (r *adminRepository) GrantPermission(ctx context.Context, p model.BizSrvcPermission, actor store.Actor) error {
+	key := p.BizServiceCode + "/" + p.ApiGroupCode + "/" + p.ApiCode
+	return r.withAudit(ctx, actor, "CREATE", "PERMISSION", key, func(tx *sql.Tx) (any, any, error) {
+		var before any
+		if b, err := getPermission(ctx, tx, p.BizServiceCode, p.ApiGroupCode, p.ApiCode); err == nil {
+			before = b
+		} else if !errors.Is(err, store.ErrNotFound) {
+			return nil, nil, err
+		}
+		if _, err := getApi(ctx, tx, p.ApiGroupCode, p.ApiCode, false); err != nil {
+			return nil, nil, err
+		}
+		p.UseYn = "Y"
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_BIZ_SRVC_API_RLP (BIZ_SRVC_CD, API_GROUP_CD, API_CD, USG_YN) VALUES (?, ?, ?, 'Y')
+			 ON DUPLICATE KEY UPDATE USG_YN = 'Y'`,
+			p.BizServiceCode, p.ApiGroupCode, p.ApiCode)
+		return before, p, err
+	})
+}
+
+func (r *adminRepository) RevokePermission(ctx context.Context, bizCd, groupCd, apiCd string, actor store.Actor) error {
+	key := bizCd + "/" + groupCd + "/" + apiCd
+	return r.withAudit(ctx, actor, "DELETE", "PERMISSION", key, func(tx *sql.Tx) (any, any, error) {
+		before, err := getPermission(ctx, tx, bizCd, groupCd, apiCd)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx,
+			`UPDATE SID_BIZ_SRVC_API_RLP SET USG_YN = 'N' WHERE BIZ_SRVC_CD = ? AND API_GROUP_CD = ? AND API_CD = ?`,
+			bizCd, groupCd, apiCd)
+		after := before
+		after.UseYn = "N"
+		return before, after, err
+	})
+}
+
+// ==== SETTINGS (SID_API_EST_MNG) ====
+
+func (r *adminRepository) ListSettings(ctx context.Context, groupCd string) ([]model.ApiSetting, error) {
+	q := `SELECT API_GROUP_CD, API_EST_KEY, VALUE, USG_YN FROM SID_API_EST_MNG`
+	var args []any
+	if groupCd != "" {
+		q += ` WHERE API_GROUP_CD = ?`
+		args = append(args, groupCd)
+	}
+	q += ` ORDER BY API_GROUP_CD, API_EST_KEY, VALUE`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.ApiSetting{}
+	for rows.Next() {
+		var s model.ApiSetting
+		if err := rows.Scan(&s.ApiGroupCode, &s.Key, &s.Value, &s.UseYn); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func getSetting(ctx context.Context, q queryer, groupCd, key, value string) (model.ApiSetting, error) {
+	var s model.ApiSetting
+	err := q.QueryRowContext(ctx,
+		`SELECT API_GROUP_CD, API_EST_KEY, VALUE, USG_YN FROM SID_API_EST_MNG
+		 WHERE API_GROUP_CD = ? AND API_EST_KEY = ? AND VALUE = ? FOR UPDATE`,
+		groupCd, key, value).Scan(&s.ApiGroupCode, &s.Key, &s.Value, &s.UseYn)
+	if err == sql.ErrNoRows {
+		return s, store.ErrNotFound
+	}
+	return s, err
+}
+
+// PutSetting: (그룹, 키, 값) upsert
+func (r *adminRepository) PutSetting(ctx context.Context, s model.ApiSetting, actor store.Actor) error {
+	key := s.ApiGroupCode + "/" + s.Key + "/" + s.Value
+	return r.withAudit(ctx, actor, "UPDATE", "SETTING", key, func(tx *sql.Tx) (any, any, error) {
+		var before any
+		if b, err := getSetting(ctx, tx, s.ApiGroupCode, s.Key, s.Value); err == nil {
+			before = b
+		} else if !errors.Is(err, store.ErrNotFound) {
+			return nil, nil, err
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_API_EST_MNG (API_GROUP_CD, API_EST_KEY, VALUE, USG_YN) VALUES (?, ?, ?, ?)
+			 ON DUPLICATE KEY UPDATE USG_YN = VALUES(USG_YN)`,
+			s.ApiGroupCode, s.Key, s.Value, s.UseYn)
+		return before, s, err
+	})
+}
+
+func (r *adminRepository) DeleteSetting(ctx context.Context, groupCd, key, value string, actor store.Actor) error {
+	target := groupCd + "/" + key + "/" + value
+	return r.withAudit(ctx, actor, "DELETE", "SETTING", target, func(tx *sql.Tx) (any, any, error) {
+		before, err := getSetting(ctx, tx, groupCd, key, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM SID_API_EST_MNG WHERE API_GROUP_CD = ? AND API_EST_KEY = ? AND VALUE = ?`,
+			groupCd, key, value)
+		return before, nil, err
+	})
+}
+
+// ==== AUDIT ====
+
+func (r *adminRepository) ListAudit(ctx context.Context, limit int) ([]model.AuditRecord, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 100
+	}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT AUDIT_SEQ, ACTOR, ACTION, TARGET_TYPE, TARGET_KEY, IFNULL(BEFORE_VAL, ''), IFNULL(AFTER_VAL, ''), IFNULL(CLIENT_IP, ''), IFNULL(TCID, ''), REG_DTM
+		 FROM SID_ADM_AUDIT_HIS ORDER BY AUDIT_SEQ DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.AuditRecord{}
+	for rows.Next() {
+		var a model.AuditRecord
+		if err := rows.Scan(&a.Seq, &a.Actor, &a.Action, &a.TargetType, &a.TargetKey, &a.Before, &a.After, &a.ClientIP, &a.Tcid, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// queryer: *sql.DB / *sql.Tx 공용 조회
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// withAudit: 변경(fn)과 감사 이력 INSERT 를 하나의 트랜잭션으로 묶음
+// fn 은 (변경 전, 변경 후, 에러) 를 반환
+func (r *adminRepository) withAudit(ctx context.Context, actor store.Actor, action, targetType, targetKey string, fn func(tx *sql.Tx) (any, any, error)) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, after, err := fn(tx)
+	if err != nil {
+		return mapMySQLError(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO SID_ADM_AUDIT_HIS (ACTOR, ACTION, TARGET_TYPE, TARGET_KEY, BEFORE_VAL, AFTER_VAL, CLIENT_IP, TCID)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		actor.Name, action, targetType, targetKey, toJSON(before), toJSON(after), nullIfEmpty(actor.ClientIP), nullIfEmpty(actor.Tcid)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func toJSON(v any) sql.NullString {
+	if v == nil {
+		return sql.NullString{}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(b), Valid: true}
+}
+
+func nullIfEmpty(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// 1062 Duplicate entry → store.ErrConflict
+func mapMySQLError(err error) error {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == 1062 {
+		return store.ErrConflict
+	}
+	return err
+}
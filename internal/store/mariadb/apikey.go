@@ -0,0 +1,95 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+)
+
+const apiKeyCols = `KEY_ID, BIZ_SRVC_CD, KEY_HASH, IFNULL(KEY_NM, ''), EXP_DTM, USG_YN, REG_DTM`
+
+func scanApiKey(row interface{ Scan(...any) error }) (model.ApiKey, error) {
+	var k model.ApiKey
+	var exp sql.NullTime
+	err := row.Scan(&k.KeyID, &k.BizServiceCode, &k.Hash, &k.Name, &exp, &k.UseYn, &k.CreatedAt)
+	if exp.Valid {
+		k.ExpiresAt = &exp.Time
+	}
+	return k, err
+}
+
+// 게이트웨이: 폐기(N) 포함 조회 → 폐기/만료 판단은 apikey.Resolver
+func (r *repository) FindApiKey(ctx context.Context, keyID string) (model.ApiKey, error) {
+	k, err := scanApiKey(r.db.QueryRowContext(ctx,
+		`SELECT `+apiKeyCols+` FROM SID_BIZ_SRVC_API_KEY WHERE KEY_ID = ?`, keyID))
+	if err == sql.ErrNoRows {
+		return k, store.ErrNotFound
+	}
+	return k, err
+}
+
+func (m *mockRepository) FindApiKey(ctx context.Context, keyID string) (model.ApiKey, error) {
+	return model.ApiKey{}, store.ErrNotFound
+}
+
+// ==== 관리 API ====
+
+func (r *adminRepository) ListApiKeys(ctx context.Context, bizCd string) ([]model.ApiKey, error) {
+	q := `SELECT ` + apiKeyCols + ` FROM SID_BIZ_SRVC_API_KEY`
+	var args []any
+	if bizCd != "" {
+		q += ` WHERE BIZ_SRVC_CD = ?`
+		args = append(args, bizCd)
+	}
+	q += ` ORDER BY BIZ_SRVC_CD, REG_DTM`
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.ApiKey{}
+	for rows.Next() {
+		k, err := scanApiKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+// CreateApiKey: 해시만 저장 (이력에도 해시는 남지 않음, ApiKey.Hash 는 json "-")
+func (r *adminRepository) CreateApiKey(ctx context.Context, k model.ApiKey, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "CREATE", "API_KEY", k.KeyID, func(tx *sql.Tx) (any, any, error) {
+		var exp sql.NullTime
+		if k.ExpiresAt != nil {
+			exp = sql.NullTime{Time: *k.ExpiresAt, Valid: true}
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_BIZ_SRVC_API_KEY (KEY_ID, BIZ_SRVC_CD, KEY_HASH, KEY_NM, EXP_DTM, USG_YN) VALUES (?, ?, ?, ?, ?, 'Y')`,
+			k.KeyID, k.BizServiceCode, k.Hash, nullIfEmpty(k.Name), exp)
+		k.UseYn = "Y"
+		return nil, k, err
+	})
+}
+
+// RevokeApiKey: USG_YN = 'N' 처리 (재사용 불가)
+func (r *adminRepository) RevokeApiKey(ctx context.Context, keyID string, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "DELETE", "API_KEY", keyID, func(tx *sql.Tx) (any, any, error) {
+		before, err := scanApiKey(tx.QueryRowContext(ctx,
+			`SELECT `+apiKeyCols+` FROM SID_BIZ_SRVC_API_KEY WHERE KEY_ID = ? FOR UPDATE`, keyID))
+		if err == sql.ErrNoRows {
+			return nil, nil, store.ErrNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE SID_BIZ_SRVC_API_KEY SET USG_YN = 'N' WHERE KEY_ID = ?`, keyID)
+		after := before
+		after.UseYn = "N"
+		return before, after, err
+	})
+}
@@ -0,0 +1,92 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+	"strconv"
+)
+
+const ipAclCols = `ACL_ID, BIZ_SRVC_CD, CIDR, ACL_TYP, IFNULL(RMK, ''), USG_YN`
+
+func scanIPAcl(row interface{ Scan(...any) error }) (model.IPAcl, error) {
+	var a model.IPAcl
+	err := row.Scan(&a.ID, &a.BizServiceCode, &a.CIDR, &a.Type, &a.Remark, &a.UseYn)
+	return a, err
+}
+
+func listIPAcls(ctx context.Context, db *sql.DB, activeOnly bool) ([]model.IPAcl, error) {
+	q := `SELECT ` + ipAclCols + ` FROM SID_BIZ_SRVC_IP_ACL`
+	if activeOnly {
+		q += ` WHERE USG_YN = 'Y'`
+	}
+	q += ` ORDER BY BIZ_SRVC_CD, ACL_ID`
+
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.IPAcl{}
+	for rows.Next() {
+		a, err := scanIPAcl(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// 게이트웨이: 사용 중(Y) 만 (ipfilter.Registry 가 캐시)
+func (r *repository) ListIPAcls(ctx context.Context) ([]model.IPAcl, error) {
+	return listIPAcls(ctx, r.db, true)
+}
+
+func (m *mockRepository) ListIPAcls(ctx context.Context) ([]model.IPAcl, error) {
+	return nil, nil
+}
+
+// ==== 관리 API ====
+
+func (r *adminRepository) ListIPAcls(ctx context.Context) ([]model.IPAcl, error) {
+	return listIPAcls(ctx, r.db, false)
+}
+
+func (r *adminRepository) CreateIPAcl(ctx context.Context, a model.IPAcl, actor store.Actor) (int64, error) {
+	var id int64
+	err := r.withAudit(ctx, actor, "CREATE", "IP_ACL", a.BizServiceCode+"/"+a.CIDR, func(tx *sql.Tx) (any, any, error) {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_BIZ_SRVC_IP_ACL (BIZ_SRVC_CD, CIDR, ACL_TYP, RMK, USG_YN) VALUES (?, ?, ?, ?, ?)`,
+			a.BizServiceCode, a.CIDR, a.Type, nullIfEmpty(a.Remark), a.UseYn)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return nil, nil, err
+		}
+		a.ID = id
+		return nil, a, nil
+	})
+	return id, err
+}
+
+// DeleteIPAcl: USG_YN = 'N' 처리
+func (r *adminRepository) DeleteIPAcl(ctx context.Context, id int64, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "DELETE", "IP_ACL", strconv.FormatInt(id, 10), func(tx *sql.Tx) (any, any, error) {
+		before, err := scanIPAcl(tx.QueryRowContext(ctx,
+			`SELECT `+ipAclCols+` FROM SID_BIZ_SRVC_IP_ACL WHERE ACL_ID = ? FOR UPDATE`, id))
+		if err == sql.ErrNoRows {
+			return nil, nil, store.ErrNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE SID_BIZ_SRVC_IP_ACL SET USG_YN = 'N' WHERE ACL_ID = ?`, id)
+		after := before
+		after.UseYn = "N"
+		return before, after, err
+	})
+}
@@ -0,0 +1,86 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"service-gateway/internal/model"
+	"service-gateway/internal/store"
+	"strconv"
+)
+
+const mntWinCols = `WIN_ID, API_GROUP_CD, API_CD, CLOT_CTL_CD, IFNULL(STA_TIM, ''), IFNULL(END_TIM, ''), IFNULL(CRON_EXPR, ''), DUR_MIN, TZ_NM, IFNULL(MSG, ''), RETRY_AFTER_SEC, USG_YN`
+
+func scanMaintenanceWindow(row interface{ Scan(...any) error }) (model.MaintenanceWindow, error) {
+	var w model.MaintenanceWindow
+	err := row.Scan(&w.ID, &w.ApiGroupCode, &w.ApiCode, &w.ControlCode, &w.StartTim, &w.EndTim,
+		&w.Cron, &w.DurationMin, &w.TimeZone, &w.Message, &w.RetryAfterSec, &w.UseYn)
+	return w, err
+}
+
+func listMaintenanceWindows(ctx context.Context, db *sql.DB, activeOnly bool) ([]model.MaintenanceWindow, error) {
+	q := `SELECT ` + mntWinCols + ` FROM SID_API_MNT_WIN`
+	if activeOnly {
+		q += ` WHERE USG_YN = 'Y'`
+	}
+	q += ` ORDER BY WIN_ID`
+
+	rows, err := db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.MaintenanceWindow{}
+	for rows.Next() {
+		w, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+// 관리 API: 사용 종료(N) 포함 전체
+func (r *adminRepository) ListMaintenanceWindows(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	return listMaintenanceWindows(ctx, r.db, false)
+}
+
+func (r *adminRepository) CreateMaintenanceWindow(ctx context.Context, mw model.MaintenanceWindow, actor store.Actor) (int64, error) {
+	var id int64
+	target := mw.ApiGroupCode + "/" + mw.ApiCode
+	err := r.withAudit(ctx, actor, "CREATE", "MAINTENANCE", target, func(tx *sql.Tx) (any, any, error) {
+		res, err := tx.ExecContext(ctx,
+			`INSERT INTO SID_API_MNT_WIN (API_GROUP_CD, API_CD, CLOT_CTL_CD, STA_TIM, END_TIM, CRON_EXPR, DUR_MIN, TZ_NM, MSG, RETRY_AFTER_SEC, USG_YN)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			mw.ApiGroupCode, mw.ApiCode, mw.ControlCode, nullIfEmpty(mw.StartTim), nullIfEmpty(mw.EndTim), nullIfEmpty(mw.Cron),
+			mw.DurationMin, mw.TimeZone, nullIfEmpty(mw.Message), mw.RetryAfterSec, mw.UseYn)
+		if err != nil {
+			return nil, nil, err
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return nil, nil, err
+		}
+		mw.ID = id
+		return nil, mw, nil
+	})
+	return id, err
+}
+
+// DeleteMaintenanceWindow: USG_YN = 'N' 처리
+func (r *adminRepository) DeleteMaintenanceWindow(ctx context.Context, id int64, actor store.Actor) error {
+	return r.withAudit(ctx, actor, "DELETE", "MAINTENANCE", strconv.FormatInt(id, 10), func(tx *sql.Tx) (any, any, error) {
+		before, err := scanMaintenanceWindow(tx.QueryRowContext(ctx,
+			`SELECT `+mntWinCols+` FROM SID_API_MNT_WIN WHERE WIN_ID = ? FOR UPDATE`, id))
+		if err == sql.ErrNoRows {
+			return nil, nil, store.ErrNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE SID_API_MNT_WIN SET USG_YN = 'N' WHERE WIN_ID = ?`, id)
+		after := before
+		after.UseYn = "N"
+		return before, after, err
+	})
+}
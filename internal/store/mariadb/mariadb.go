@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
 	config "service-gateway/internal/configs"
+	"service-gateway/internal/logx"
 	"service-gateway/internal/model"
 	"service-gateway/internal/store"
+	"strconv"
 	"time"
 
 	// ★ 이 줄이 반드시 있어야 함
@@ -21,10 +23,12 @@ type Config struct {
 	Host     string
 	Port     int
 	DBName   string
+	Logger   *slog.Logger // nil 이면 slog.Default
 }
 
 type repository struct {
-	db *sql.DB
+	db  *sql.DB
+	log *slog.Logger
 }
 
 type mockRepository struct{}
@@ -34,6 +38,15 @@ func New(cfg Config) (store.Repository, error) {
 	if !cfg.Enabled {
 		return &mockRepository{}, nil
 	}
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &repository{db: db, log: logx.Or(cfg.Logger).With("component", "store")}, nil
+}
+
+// Open: 커넥션 풀 설정 + Ping 까지 완료된 *sql.DB 반환 (마이그레이션/시드 명령에서도 재사용)
+func Open(cfg Config) (*sql.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&charset=utf8mb4,utf8",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName)
 
@@ -45,9 +58,10 @@ func New(cfg Config) (store.Repository, error) {
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(30 * time.Minute)
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
-	return &repository{db: db}, nil
+	return db, nil
 }
 
 // main repo
@@ -60,11 +74,10 @@ func (r *repository) FindRequestData(ctx context.Context, inputData model.Reques
 		&inputData.ApiGroupCode,
 		&inputData.RequestHost,
 	)
-	/**
+	// 미등록 경로는 ErrNotFound → 핸들러에서 404(코드 14) 응답
 	if err == sql.ErrNoRows {
-		return inputData, nil // 없을 경우 빈 문자열
+		return inputData, store.ErrNotFound
 	}
-	*/
 	if err != nil {
 		return inputData, err
 	}
@@ -112,7 +125,7 @@ func (r *repository) ExistAPI(ctx context.Context, inputData model.RequestData)
 	}
 
 	// 제어 코드 체크
-	controlErr := r.checkControlCodes(&ctlCd, &staTim, &endTim)
+	controlErr := r.checkControlCodes(ctx, &ctlCd, &staTim, &endTim)
 
 	if controlErr != nil {
 		return false, controlErr
@@ -153,7 +166,7 @@ func (r *repository) ExistAPIGroup(ctx context.Context, inputData model.RequestD
 	}
 
 	// 제어 코드 체크
-	controlErr := r.checkControlCodes(&ctlCd, &staTim, &endTim)
+	controlErr := r.checkControlCodes(ctx, &ctlCd, &staTim, &endTim)
 	if controlErr != nil {
 		return false, controlErr
 	}
@@ -180,6 +193,33 @@ func (r *repository) ExistConfig(ctx context.Context, configKey string) (bool, e
 	return exists == 1, nil
 }
 
+// 사용 중(USG_YN = 'Y')인 점검 시간대 전체 (maintenance.Checker 가 캐시)
+func (r *repository) ListMaintenanceWindows(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	return listMaintenanceWindows(ctx, r.db, true)
+}
+
+// 마스킹 규칙 (API_EST_KEY = 'MASK' 그룹 전체 / 'MASK.<API_CD>' API 별, masking.Registry 가 캐시)
+func (r *repository) ListMaskSettings(ctx context.Context) ([]model.ApiSetting, error) {
+	const q = `SELECT API_GROUP_CD, API_EST_KEY, VALUE, USG_YN FROM SID_API_EST_MNG
+		WHERE (API_EST_KEY = 'MASK' OR API_EST_KEY LIKE 'MASK.%') AND USG_YN = 'Y'
+		ORDER BY API_GROUP_CD, API_EST_KEY, VALUE`
+	rows, err := r.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []model.ApiSetting{}
+	for rows.Next() {
+		var s model.ApiSetting
+		if err := rows.Scan(&s.ApiGroupCode, &s.Key, &s.Value, &s.UseYn); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
 func (r *repository) Close() error {
 	return r.db.Close()
 }
@@ -206,31 +246,60 @@ func (m *mockRepository) ExistUseAPIList(ctx context.Context, inputData model.Re
 	return true, nil
 }
 
+func (m *mockRepository) ListMaintenanceWindows(ctx context.Context) ([]model.MaintenanceWindow, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) ListMaskSettings(ctx context.Context) ([]model.ApiSetting, error) {
+	return nil, nil
+}
+
 func (m *mockRepository) Close() error {
 	return nil
 }
 
 // 제어코드 비즈니스 로직
-func (r *repository) checkControlCodes(ClotCtlCd, ClotUablStaTim, ClotUablEndTim *string) error {
-	if *ClotCtlCd != "00" {
-		// 08: 지정기간거래불가
-		if *ClotCtlCd == "08" {
-			now := time.Now().Format("20060102150405000") // HHMMSS
-			if *ClotUablStaTim != "" && *ClotUablEndTim != "" {
-				if now >= *ClotUablStaTim && now <= *ClotUablEndTim {
-					return fmt.Errorf("%s", model.ErrorCodeMap[*ClotCtlCd])
-				}
-			}
-		} else {
-			// 00, 08이 아닌 경우 에러 반환
-			msg := model.ErrorCodeMap[*ClotCtlCd]
-			if msg == "" {
-				msg = "알 수 없는 에러"
-			}
-			log.Printf("Control Code Error: [%s] %s ", *ClotCtlCd, msg)
-			return fmt.Errorf("%s", msg)
+// 00 이외 코드는 *model.ControlError 로 반환 → 핸들러에서 503 + 코드로 응답
+func (r *repository) checkControlCodes(ctx context.Context, ClotCtlCd, ClotUablStaTim, ClotUablEndTim *string) error {
+	if *ClotCtlCd == "" || *ClotCtlCd == "00" {
+		return nil
+	}
+
+	// 08: 지정기간거래불가 (yyyyMMddHHmmss[SSS], 서버 로컬 타임존)
+	if *ClotCtlCd == "08" {
+		sta, okSta := parseClotTim(*ClotUablStaTim)
+		end, okEnd := parseClotTim(*ClotUablEndTim)
+		if !okSta || !okEnd {
+			return nil
 		}
+		now := time.Now()
+		if !now.Before(sta) && !now.After(end) {
+			return model.NewControlError(*ClotCtlCd, "", end.Sub(now))
+		}
+		return nil
 	}
 
-	return nil
+	// 00, 08이 아닌 경우 에러 반환
+	ce := model.NewControlError(*ClotCtlCd, "", 0)
+	r.log.InfoContext(ctx, "control code blocked", "code", ce.Code, "message", ce.Message)
+	return ce
+}
+
+// parseClotTim: 문자열 사전순 비교 대신 시각으로 변환 (14자리 또는 밀리초 포함 17자리)
+func parseClotTim(v string) (time.Time, bool) {
+	if len(v) != 14 && len(v) != 17 {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation("20060102150405", v[:14], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if len(v) == 17 {
+		ms, err := strconv.Atoi(v[14:])
+		if err != nil {
+			return time.Time{}, false
+		}
+		t = t.Add(time.Duration(ms) * time.Millisecond)
+	}
+	return t, true
 }
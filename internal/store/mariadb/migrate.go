@@ -0,0 +1,243 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 바이너리에 포함되는 버전별 DDL (NNNN_name.up.sql / NNNN_name.down.sql)
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// 적용 이력 테이블: 어떤 버전까지 반영되었는지 기록
+const migrationTable = "SID_SCHEMA_MIG_HIS"
+
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationStatus: status 명령 출력용 (AppliedAt 이 nil 이면 미적용)
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	ms, err := loadMigrations(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// loadMigrations: 파일명에서 버전/이름/방향을 파싱해 버전 오름차순으로 정렬
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		fname := e.Name()
+		var direction string
+		switch {
+		case strings.HasSuffix(fname, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fname, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+		base := strings.TrimSuffix(fname, "."+direction+".sql")
+		verStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name: %s", fname)
+		}
+		ver, err := strconv.Atoi(verStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", fname)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, fname))
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[ver]
+		if !ok {
+			m = &Migration{Version: ver, Name: name}
+			byVersion[ver] = m
+		}
+		if direction == "up" {
+			m.up = string(body)
+		} else {
+			m.down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up script", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrationTable+` (
+    MIG_VER  INT          NOT NULL,
+    MIG_NM   VARCHAR(200) NOT NULL,
+    APLY_DTM DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (MIG_VER)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`)
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT MIG_VER, APLY_DTM FROM `+migrationTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[int]time.Time)
+	for rows.Next() {
+		var ver int
+		var at time.Time
+		if err := rows.Scan(&ver, &at); err != nil {
+			return nil, err
+		}
+		out[ver] = at
+	}
+	return out, rows.Err()
+}
+
+// Up: 미적용 버전을 순서대로 반영. 반영된 버전 목록 반환
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, mig := range m.migrations {
+		if _, ok := done[mig.Version]; ok {
+			continue
+		}
+		if err := m.exec(ctx, mig.up); err != nil {
+			return ran, fmt.Errorf("migration %04d_%s up: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := m.db.ExecContext(ctx,
+			`INSERT INTO `+migrationTable+` (MIG_VER, MIG_NM) VALUES (?, ?)`, mig.Version, mig.Name); err != nil {
+			return ran, err
+		}
+		ran = append(ran, mig)
+	}
+	return ran, nil
+}
+
+// Down: 최근 적용 버전부터 steps 개 되돌림
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(ran) < steps; i-- {
+		mig := m.migrations[i]
+		if _, ok := done[mig.Version]; !ok {
+			continue
+		}
+		if mig.down == "" {
+			return ran, fmt.Errorf("migration %04d_%s has no down script", mig.Version, mig.Name)
+		}
+		if err := m.exec(ctx, mig.down); err != nil {
+			return ran, fmt.Errorf("migration %04d_%s down: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := m.db.ExecContext(ctx,
+			`DELETE FROM `+migrationTable+` WHERE MIG_VER = ?`, mig.Version); err != nil {
+			return ran, err
+		}
+		ran = append(ran, mig)
+	}
+	return ran, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	done, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if at, ok := done[mig.Version]; ok {
+			st.AppliedAt = &at
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// exec: DDL 은 MariaDB 에서 암묵적 커밋되므로 트랜잭션 없이 문장 단위로 실행
+// (DSN 에 multiStatements 를 켜지 않기 위해 ';' 줄끝 기준으로 분리)
+func (m *Migrator) exec(ctx context.Context, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if _, err := m.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var out []string
+	var cur strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmt := strings.TrimSuffix(strings.TrimSpace(cur.String()), ";")
+			out = append(out, stmt)
+			cur.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(cur.String()); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
@@ -0,0 +1,12 @@
+-- API 그룹 관리 : 그룹 단위 사용여부 및 거래통제(CLOT) 코드
+CREATE TABLE IF NOT EXISTS SID_API_GRP_MNG (
+    API_GROUP_CD                VARCHAR(3)   NOT NULL,
+    API_GROUP_NM                VARCHAR(100) NULL,
+    USG_YN                      CHAR(1)      NOT NULL DEFAULT 'Y',
+    API_GROUP_CLOT_CTL_CD       VARCHAR(2)   NOT NULL DEFAULT '00',
+    API_GROUP_CLOT_UABL_STA_TIM VARCHAR(17)  NULL,
+    API_GROUP_CLOT_UABL_END_TIM VARCHAR(17)  NULL,
+    REG_DTM                     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM                     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (API_GROUP_CD)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
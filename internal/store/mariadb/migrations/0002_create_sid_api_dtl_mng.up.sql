@@ -0,0 +1,17 @@
+-- API 상세 관리 : FW URL(API_PATH) → 그룹/API 코드 및 업스트림(TARGET_URI) 매핑
+CREATE TABLE IF NOT EXISTS SID_API_DTL_MNG (
+    API_GROUP_CD          VARCHAR(3)   NOT NULL,
+    API_CD                VARCHAR(5)   NOT NULL,
+    API_NM                VARCHAR(100) NULL,
+    API_PATH              VARCHAR(200) NOT NULL,
+    API_TYP_CD            VARCHAR(2)   NOT NULL DEFAULT '00',
+    TARGET_URI            VARCHAR(200) NULL,
+    USG_YN                CHAR(1)      NOT NULL DEFAULT 'Y',
+    API_CLOT_CTL_CD       VARCHAR(2)   NOT NULL DEFAULT '00',
+    API_CLOT_UABL_STA_TIM VARCHAR(17)  NULL,
+    API_CLOT_UABL_END_TIM VARCHAR(17)  NULL,
+    REG_DTM               DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM               DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (API_GROUP_CD, API_CD),
+    UNIQUE KEY UK_SID_API_DTL_MNG_PATH (API_PATH, API_TYP_CD)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
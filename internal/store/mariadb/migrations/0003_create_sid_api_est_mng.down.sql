@@ -0,0 +1 @@
+DROP TABLE IF EXISTS SID_API_EST_MNG;
@@ -0,0 +1,10 @@
+-- API 설정 관리 : 그룹별 설정값 (로그 적재 대상 등). ExistConfig 는 VALUE 컬럼으로 조회
+CREATE TABLE IF NOT EXISTS SID_API_EST_MNG (
+    API_GROUP_CD VARCHAR(3)   NOT NULL,
+    API_EST_KEY  VARCHAR(50)  NOT NULL,
+    VALUE        VARCHAR(200) NOT NULL,
+    USG_YN       CHAR(1)      NOT NULL DEFAULT 'Y',
+    REG_DTM      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM      DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (API_GROUP_CD, API_EST_KEY, VALUE)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
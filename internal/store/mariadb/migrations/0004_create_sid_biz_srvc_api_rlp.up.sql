@@ -0,0 +1,11 @@
+-- 업무서비스-API 관계 : BIZ_SRVC_CD 별 호출 허용 API
+CREATE TABLE IF NOT EXISTS SID_BIZ_SRVC_API_RLP (
+    BIZ_SRVC_CD  VARCHAR(20) NOT NULL,
+    API_GROUP_CD VARCHAR(3)  NOT NULL,
+    API_CD       VARCHAR(5)  NOT NULL,
+    USG_YN       CHAR(1)     NOT NULL DEFAULT 'Y',
+    REG_DTM      DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM      DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (BIZ_SRVC_CD, API_GROUP_CD, API_CD),
+    KEY IX_SID_BIZ_SRVC_API_RLP_API (API_GROUP_CD, API_CD)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
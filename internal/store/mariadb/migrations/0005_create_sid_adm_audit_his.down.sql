@@ -0,0 +1 @@
+DROP TABLE IF EXISTS SID_ADM_AUDIT_HIS;
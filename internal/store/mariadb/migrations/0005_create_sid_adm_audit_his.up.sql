@@ -0,0 +1,15 @@
+-- 관리 API(/admin/v1) 변경 이력 : 누가/언제/무엇을 (변경 전후 JSON)
+CREATE TABLE IF NOT EXISTS SID_ADM_AUDIT_HIS (
+    AUDIT_SEQ   BIGINT       NOT NULL AUTO_INCREMENT,
+    ACTOR       VARCHAR(100) NOT NULL,
+    ACTION      VARCHAR(10)  NOT NULL,
+    TARGET_TYPE VARCHAR(30)  NOT NULL,
+    TARGET_KEY  VARCHAR(300) NOT NULL,
+    BEFORE_VAL  TEXT         NULL,
+    AFTER_VAL   TEXT         NULL,
+    CLIENT_IP   VARCHAR(64)  NULL,
+    TCID        VARCHAR(40)  NULL,
+    REG_DTM     DATETIME(3)  NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (AUDIT_SEQ),
+    KEY IX_SID_ADM_AUDIT_HIS_TARGET (TARGET_TYPE, TARGET_KEY)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
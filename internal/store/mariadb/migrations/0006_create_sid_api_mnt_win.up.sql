@@ -0,0 +1,19 @@
+-- 점검(거래불가) 시간대 : API 또는 그룹 단위, 1회성(STA~END) 또는 반복(CRON_EXPR + DUR_MIN)
+CREATE TABLE IF NOT EXISTS SID_API_MNT_WIN (
+    WIN_ID          BIGINT       NOT NULL AUTO_INCREMENT,
+    API_GROUP_CD    VARCHAR(3)   NOT NULL,
+    API_CD          VARCHAR(5)   NOT NULL DEFAULT '',
+    CLOT_CTL_CD     VARCHAR(2)   NOT NULL DEFAULT '08',
+    STA_TIM         VARCHAR(14)  NULL,
+    END_TIM         VARCHAR(14)  NULL,
+    CRON_EXPR       VARCHAR(100) NULL,
+    DUR_MIN         INT          NOT NULL DEFAULT 0,
+    TZ_NM           VARCHAR(50)  NOT NULL DEFAULT 'Asia/Seoul',
+    MSG             VARCHAR(500) NULL,
+    RETRY_AFTER_SEC INT          NOT NULL DEFAULT 0,
+    USG_YN          CHAR(1)      NOT NULL DEFAULT 'Y',
+    REG_DTM         DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM         DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (WIN_ID),
+    KEY IX_SID_API_MNT_WIN_API (API_GROUP_CD, API_CD)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
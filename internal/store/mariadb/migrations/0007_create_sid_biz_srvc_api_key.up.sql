@@ -0,0 +1,14 @@
+-- 업무서비스 API 키 : 키 → BIZ_SRVC_CD (게이트웨이가 헤더/바디의 BizSrvcCd 대신 키로 업무서비스 판별)
+-- KEY_HASH 는 SHA-256(전체 키) hex, 원문은 발급 응답에서 1회만 노출
+CREATE TABLE IF NOT EXISTS SID_BIZ_SRVC_API_KEY (
+    KEY_ID      VARCHAR(16)  NOT NULL,
+    BIZ_SRVC_CD VARCHAR(20)  NOT NULL,
+    KEY_HASH    CHAR(64)     NOT NULL,
+    KEY_NM      VARCHAR(100) NULL,
+    EXP_DTM     DATETIME     NULL,
+    USG_YN      CHAR(1)      NOT NULL DEFAULT 'Y',
+    REG_DTM     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (KEY_ID),
+    KEY IX_SID_BIZ_SRVC_API_KEY_BIZ (BIZ_SRVC_CD)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
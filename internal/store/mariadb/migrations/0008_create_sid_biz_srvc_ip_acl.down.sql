@@ -0,0 +1 @@
+DROP TABLE IF EXISTS SID_BIZ_SRVC_IP_ACL;
@@ -0,0 +1,14 @@
+-- 업무서비스별 IP 허용/차단 : ACL_TYP A(허용) / D(차단), CIDR 는 단일 IP 또는 CIDR 표기
+-- 허용(A) 행이 하나라도 있으면 그 업무서비스는 허용 목록에 있는 IP 만 통과
+CREATE TABLE IF NOT EXISTS SID_BIZ_SRVC_IP_ACL (
+    ACL_ID      BIGINT       NOT NULL AUTO_INCREMENT,
+    BIZ_SRVC_CD VARCHAR(20)  NOT NULL,
+    CIDR        VARCHAR(50)  NOT NULL,
+    ACL_TYP     CHAR(1)      NOT NULL DEFAULT 'A',
+    RMK         VARCHAR(200) NULL,
+    USG_YN      CHAR(1)      NOT NULL DEFAULT 'Y',
+    REG_DTM     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHG_DTM     DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (ACL_ID),
+    KEY IX_SID_BIZ_SRVC_IP_ACL_BIZ (BIZ_SRVC_CD)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,129 @@
+package mariadb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// SID_API_EST_MNG 에서 로그 적재 설정을 묶는 키
+const LogEstKey = "LOG"
+
+type SeedRoute struct {
+	Name      string // API_NM
+	Path      string // API_PATH (FW URL)
+	TargetURI string // scheme://host
+}
+
+// Seed: gateway.yaml 에서 카탈로그(SID_*)로 옮길 내용
+type Seed struct {
+	GroupCode   string
+	GroupName   string
+	Routes      []SeedRoute
+	LogSettings []string // SID_API_EST_MNG.VALUE (log.source.in 등)
+	BizSrvcCds  []string // 비어있지 않으면 해당 업무서비스 코드에 모든 라우트 허용
+}
+
+type SeedResult struct {
+	Inserted int
+	Updated  int
+}
+
+// ApplySeed: 재실행해도 안전하도록 API_PATH 기준 upsert. 하나의 트랜잭션으로 반영
+func ApplySeed(ctx context.Context, db *sql.DB, s Seed) (SeedResult, error) {
+	var res SeedResult
+	if s.GroupCode == "" {
+		return res, fmt.Errorf("seed: group code empty")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO SID_API_GRP_MNG (API_GROUP_CD, API_GROUP_NM, USG_YN) VALUES (?, ?, 'Y')
+		 ON DUPLICATE KEY UPDATE API_GROUP_NM = VALUES(API_GROUP_NM)`,
+		s.GroupCode, s.GroupName); err != nil {
+		return res, err
+	}
+
+	for _, v := range s.LogSettings {
+		if v == "" {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT IGNORE INTO SID_API_EST_MNG (API_GROUP_CD, API_EST_KEY, VALUE, USG_YN) VALUES (?, ?, ?, 'Y')`,
+			s.GroupCode, LogEstKey, v); err != nil {
+			return res, err
+		}
+	}
+
+	next, err := nextApiCode(ctx, tx, s.GroupCode)
+	if err != nil {
+		return res, err
+	}
+
+	for _, rt := range s.Routes {
+		if rt.Path == "" {
+			continue
+		}
+		var apiCd, groupCd string
+		err := tx.QueryRowContext(ctx,
+			`SELECT API_CD, API_GROUP_CD FROM SID_API_DTL_MNG WHERE API_PATH = ? AND API_TYP_CD = '00'`,
+			rt.Path).Scan(&apiCd, &groupCd)
+		switch {
+		case err == sql.ErrNoRows:
+			apiCd, groupCd = fmt.Sprintf("%05d", next), s.GroupCode
+			next++
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO SID_API_DTL_MNG (API_GROUP_CD, API_CD, API_NM, API_PATH, API_TYP_CD, TARGET_URI, USG_YN)
+				 VALUES (?, ?, ?, ?, '00', ?, 'Y')`,
+				groupCd, apiCd, rt.Name, rt.Path, rt.TargetURI); err != nil {
+				return res, err
+			}
+			res.Inserted++
+		case err != nil:
+			return res, err
+		default:
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE SID_API_DTL_MNG SET API_NM = ?, TARGET_URI = ? WHERE API_GROUP_CD = ? AND API_CD = ?`,
+				rt.Name, rt.TargetURI, groupCd, apiCd); err != nil {
+				return res, err
+			}
+			res.Updated++
+		}
+
+		for _, biz := range s.BizSrvcCds {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT IGNORE INTO SID_BIZ_SRVC_API_RLP (BIZ_SRVC_CD, API_GROUP_CD, API_CD, USG_YN) VALUES (?, ?, ?, 'Y')`,
+				biz, groupCd, apiCd); err != nil {
+				return res, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// nextApiCode: 그룹 내 최대 API_CD + 1 (5자리 숫자 코드 체계)
+func nextApiCode(ctx context.Context, tx *sql.Tx, groupCode string) (int, error) {
+	var max sql.NullString
+	if err := tx.QueryRowContext(ctx,
+		`SELECT MAX(API_CD) FROM SID_API_DTL_MNG WHERE API_GROUP_CD = ?`, groupCode).Scan(&max); err != nil {
+		return 0, err
+	}
+	if !max.Valid || max.String == "" {
+		return 1, nil
+	}
+	n, err := strconv.Atoi(max.String)
+	if err != nil {
+		return 0, fmt.Errorf("seed: non numeric API_CD %q in group %s", max.String, groupCode)
+	}
+	return n + 1, nil
+}
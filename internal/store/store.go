@@ -2,9 +2,15 @@ package store
 
 import (
 	"context"
+	"errors"
 	"service-gateway/internal/model"
 )
 
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("already exists")
+)
+
 /**
 // 트랜잭션 묶음
 type TxRepository interface {
@@ -20,5 +26,54 @@ type Repository interface {
 	ExistAPIGroup(ctx context.Context, inputData model.RequestData) (bool, error)
 	ExistAPI(ctx context.Context, inputData model.RequestData) (bool, error)
 	ExistConfig(ctx context.Context, config string) (bool, error)
+	ListMaintenanceWindows(ctx context.Context) ([]model.MaintenanceWindow, error)
+	ListMaskSettings(ctx context.Context) ([]model.ApiSetting, error)
+	FindApiKey(ctx context.Context, keyID string) (model.ApiKey, error) // 없으면 ErrNotFound
+	ListIPAcls(ctx context.Context) ([]model.IPAcl, error)
+	Close() error
+}
+
+// Actor: 관리 API 변경 주체 (감사 이력에 함께 기록)
+type Actor struct {
+	Name     string
+	ClientIP string
+	Tcid     string
+}
+
+// AdminRepository: /admin/v1 에서 사용하는 카탈로그 CRUD. 모든 변경은 감사 이력과 같은 트랜잭션으로 기록
+type AdminRepository interface {
+	ListGroups(ctx context.Context) ([]model.ApiGroup, error)
+	GetGroup(ctx context.Context, groupCd string) (model.ApiGroup, error)
+	CreateGroup(ctx context.Context, g model.ApiGroup, actor Actor) error
+	UpdateGroup(ctx context.Context, g model.ApiGroup, actor Actor) error
+	DeleteGroup(ctx context.Context, groupCd string, actor Actor) error
+
+	ListApis(ctx context.Context, groupCd string) ([]model.ApiDetail, error)
+	GetApi(ctx context.Context, groupCd, apiCd string) (model.ApiDetail, error)
+	CreateApi(ctx context.Context, a model.ApiDetail, actor Actor) error
+	UpdateApi(ctx context.Context, a model.ApiDetail, actor Actor) error
+	DeleteApi(ctx context.Context, groupCd, apiCd string, actor Actor) error
+
+	ListPermissions(ctx context.Context, bizCd string) ([]model.BizSrvcPermission, error)
+	GrantPermission(ctx context.Context, p model.BizSrvcPermission, actor Actor) error
+	RevokePermission(ctx context.Context, bizCd, groupCd, apiCd string, actor Actor) error
+
+	ListSettings(ctx context.Context, groupCd string) ([]model.ApiSetting, error)
+	PutSetting(ctx context.Context, s model.ApiSetting, actor Actor) error
+	DeleteSetting(ctx context.Context, groupCd, key, value string, actor Actor) error
+
+	ListMaintenanceWindows(ctx context.Context) ([]model.MaintenanceWindow, error)
+	CreateMaintenanceWindow(ctx context.Context, mw model.MaintenanceWindow, actor Actor) (int64, error)
+	DeleteMaintenanceWindow(ctx context.Context, id int64, actor Actor) error
+
+	ListApiKeys(ctx context.Context, bizCd string) ([]model.ApiKey, error)
+	CreateApiKey(ctx context.Context, k model.ApiKey, actor Actor) error
+	RevokeApiKey(ctx context.Context, keyID string, actor Actor) error
+
+	ListIPAcls(ctx context.Context) ([]model.IPAcl, error)
+	CreateIPAcl(ctx context.Context, a model.IPAcl, actor Actor) (int64, error)
+	DeleteIPAcl(ctx context.Context, id int64, actor Actor) error
+
+	ListAudit(ctx context.Context, limit int) ([]model.AuditRecord, error)
 	Close() error
 }
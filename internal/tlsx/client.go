@@ -0,0 +1,55 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+)
+
+/*
+업스트림 호출용 TLS (사설 CA / 클라이언트 인증서)
+
+- ca_file: 비우면 시스템 루트 CA
+- cert_file + key_file: 업스트림이 mTLS 를 요구할 때 제시할 인증서 (둘 다 지정)
+- server_name: 인증서 검증/SNI 이름 (IP 로 호출하거나 LB 이름과 인증서 이름이 다를 때)
+- insecure_skip_verify: 개발 환경 전용 (서버 인증서 미검증)
+*/
+
+// ClientConfig: routes[].backend.tls / upstream_tls.groups 블록
+type ClientConfig struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+	MinVersion         string // 비우면 1.2
+}
+
+// Build: 파일 적재 후 tls.Config (설정 오류는 기동 시 에러)
+func (c ClientConfig) Build() (*tls.Config, error) {
+	minVer, err := ParseVersion(c.MinVersion)
+	if err != nil {
+		return nil, fmt.Errorf("min_version: %w", err)
+	}
+	cfg := &tls.Config{
+		MinVersion:         minVer,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, // 개발 환경 전용
+	}
+	if c.CAFile != "" {
+		if cfg.RootCAs, err = LoadCertPool(c.CAFile); err != nil {
+			return nil, fmt.Errorf("ca_file: %w", err)
+		}
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("cert_file and key_file must be set together")
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cert_file: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,55 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"strings"
+)
+
+/*
+mTLS 클라이언트 인증서 → 업무서비스(BIZ_SRVC_CD)
+
+- 검증된(VerifiedChains 있는) 인증서만 사용, 검증 안 된 인증서는 없는 것으로 취급
+- 매칭 순서: SAN(DNS → URI → email) → Subject CN, 처음 매칭되는 이름의 업무서비스
+- 게이트웨이는 이 값으로 SID_BIZ_SRVC_API_RLP 권한 체크 (헤더/바디 BizSrvcCd 와 다르면 401)
+*/
+
+// PeerNames: 검증된 클라이언트 인증서의 SAN / CN (인증서 없으면 nil)
+func PeerNames(cs *tls.ConnectionState) []string {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+	leaf := cs.PeerCertificates[0]
+	names := append([]string{}, leaf.DNSNames...)
+	for _, u := range leaf.URIs {
+		names = append(names, u.String())
+	}
+	names = append(names, leaf.EmailAddresses...)
+	if leaf.Subject.CommonName != "" {
+		names = append(names, leaf.Subject.CommonName)
+	}
+	return names
+}
+
+// IdentityMap: 인증서 이름(CN/SAN) → BIZ_SRVC_CD
+type IdentityMap struct {
+	m map[string]string
+}
+
+// NewIdentityMap: 이름은 대소문자 무시
+func NewIdentityMap(m map[string]string) *IdentityMap {
+	im := &IdentityMap{m: make(map[string]string, len(m))}
+	for name, biz := range m {
+		im.m[strings.ToLower(name)] = biz
+	}
+	return im
+}
+
+// Lookup: 처음 매칭되는 이름의 업무서비스
+func (im *IdentityMap) Lookup(names []string) (string, bool) {
+	for _, n := range names {
+		if biz, ok := im.m[strings.ToLower(n)]; ok {
+			return biz, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+// 실제 mTLS 핸드셰이크: 검증된 클라이언트 인증서만 PeerNames 로 노출
+func TestPeerNamesHandshake(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil, true, nil)
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.der}))
+	srvCert := newCert(t, "srv", []string{"api.example.com"}, false, ca)
+
+	s, err := NewServer(ServerConfig{
+		Certs:        []CertFiles{srvCert.write(t, dir, "srv")},
+		ClientAuth:   "request",
+		ClientCAFile: caFile,
+		Reload:       -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newCert(t, "partner-cn", []string{"partner.example.com"}, false, ca)
+
+	handshake := func(certs []tls.Certificate) tls.ConnectionState {
+		t.Helper()
+		cConn, sConn := net.Pipe()
+		defer cConn.Close()
+		defer sConn.Close()
+		srv := tls.Server(sConn, s.TLSConfig())
+		done := make(chan error, 1)
+		go func() { done <- srv.Handshake() }()
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		cli := tls.Client(cConn, &tls.Config{ServerName: "api.example.com", RootCAs: roots, Certificates: certs})
+		if err := cli.Handshake(); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		return srv.ConnectionState()
+	}
+
+	cs := handshake([]tls.Certificate{{Certificate: [][]byte{client.der}, PrivateKey: client.key}})
+	if got := PeerNames(&cs); len(got) != 2 || got[0] != "partner.example.com" || got[1] != "partner-cn" {
+		t.Errorf("PeerNames = %q", got)
+	}
+	if got := PeerNames(&tls.ConnectionState{}); got != nil {
+		t.Errorf("no certificate: %q", got)
+	}
+	if got := PeerNames(nil); got != nil {
+		t.Errorf("nil state: %q", got)
+	}
+	// 인증서는 있으나 검증 체인 없음 (검증 안 된 인증서) → 없는 것으로 취급
+	if got := PeerNames(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{client.cert}}); got != nil {
+		t.Errorf("unverified certificate: %q", got)
+	}
+
+	// 검증된 인증서의 SAN(DNS → URI → email) → CN 순서
+	u, _ := url.Parse("spiffe://example.com/partner")
+	client.cert.URIs = []*url.URL{u}
+	client.cert.EmailAddresses = []string{"ops@example.com"}
+	verified := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{client.cert}, VerifiedChains: [][]*x509.Certificate{{client.cert, ca.cert}}}
+	want := []string{"partner.example.com", "spiffe://example.com/partner", "ops@example.com", "partner-cn"}
+	got := PeerNames(verified)
+	if len(got) != len(want) {
+		t.Fatalf("PeerNames = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIdentityMap(t *testing.T) {
+	im := NewIdentityMap(map[string]string{"Partner.Example.com": "SMP", "partner-cn": "ABC"})
+	if biz, ok := im.Lookup([]string{"unknown", "partner.example.COM", "partner-cn"}); !ok || biz != "SMP" {
+		t.Errorf("first match: %q %v", biz, ok)
+	}
+	if biz, ok := im.Lookup([]string{"partner-cn"}); !ok || biz != "ABC" {
+		t.Errorf("cn: %q %v", biz, ok)
+	}
+	if _, ok := im.Lookup(nil); ok {
+		t.Error("nil names matched")
+	}
+}
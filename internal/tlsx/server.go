@@ -0,0 +1,286 @@
+package tlsx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+리스너 TLS 종료 / mTLS 클라이언트 인증
+
+WHY:
+- srv.ListenAndServe 가 평문 HTTP 만 제공 → 앞단 LB 없이 외부에 노출하거나 구간 암호화가 필요한 환경에서 사용 불가.
+- 인증서 교체 때마다 게이트웨이를 재기동하지 않도록 파일 변경 시 자동 재적재.
+
+동작:
+- certs 여러 개 → SNI(ServerName) 로 선택: server_names(미지정 시 인증서 SAN/CN) 정확 일치 → 와일드카드(*.example.com) → 첫 인증서
+- reload 주기로 파일 수정 시각 확인, 바뀌면 재적재 (실패 시 이전 인증서 유지)
+- min_version / cipher_suites (cipher_suites 는 TLS 1.2 이하에만 적용, 1.3 은 Go 고정)
+- client_auth: none | request(제시 시 검증) | require(필수), client_ca_file 로 검증 (CA 번들도 재적재)
+*/
+
+// CertFiles: 인증서/키 파일 쌍
+type CertFiles struct {
+	CertFile    string
+	KeyFile     string
+	ServerNames []string // SNI 매칭 이름 (비우면 인증서 SAN, SAN 없으면 CN)
+}
+
+// ServerConfig: gateway.yaml server.tls 블록
+type ServerConfig struct {
+	Certs        []CertFiles
+	MinVersion   string   // "1.2"(기본) | "1.3"
+	CipherSuites []string // tls.CipherSuites() 이름 (비우면 Go 기본)
+	ClientAuth   string   // none(기본) | request | require
+	ClientCAFile string
+	Reload       time.Duration // 파일 변경 확인 주기 (0 이면 10s, 음수면 재적재 안 함)
+	Logger       *slog.Logger
+}
+
+type snapshot struct {
+	certs     []*tls.Certificate
+	byName    map[string]*tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+// Server: 재적재 가능한 서버 TLS 설정
+type Server struct {
+	cfg  ServerConfig
+	log  *slog.Logger
+	base *tls.Config
+
+	mu  sync.RWMutex
+	cur *snapshot
+
+	stop chan struct{}
+}
+
+// NewServer: 최초 적재 실패는 에러 (기동 시점 설정 오류 조기 발견)
+func NewServer(cfg ServerConfig) (*Server, error) {
+	if len(cfg.Certs) == 0 {
+		return nil, errors.New("server.tls.certs: at least one cert required")
+	}
+	minVer, err := ParseVersion(cfg.MinVersion)
+	if err != nil {
+		return nil, fmt.Errorf("server.tls.min_version: %w", err)
+	}
+	suites, err := ParseCipherSuites(cfg.CipherSuites)
+	if err != nil {
+		return nil, fmt.Errorf("server.tls.cipher_suites: %w", err)
+	}
+	var auth tls.ClientAuthType
+	switch strings.ToLower(cfg.ClientAuth) {
+	case "", "none":
+		auth = tls.NoClientCert
+	case "request":
+		auth = tls.VerifyClientCertIfGiven
+	case "require":
+		auth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("server.tls.client_auth: unknown %q (none|request|require)", cfg.ClientAuth)
+	}
+	if auth != tls.NoClientCert && cfg.ClientCAFile == "" {
+		return nil, errors.New("server.tls.client_ca_file: required when client_auth is set")
+	}
+	if cfg.Reload == 0 {
+		cfg.Reload = 10 * time.Second
+	}
+	lg := cfg.Logger
+	if lg == nil {
+		lg = slog.Default()
+	}
+
+	s := &Server{cfg: cfg, log: lg.With("component", "tls"), stop: make(chan struct{})}
+	if s.cur, err = s.load(); err != nil {
+		return nil, err
+	}
+	s.base = &tls.Config{
+		MinVersion:     minVer,
+		CipherSuites:   suites,
+		ClientAuth:     auth,
+		NextProtos:     []string{"h2", "http/1.1"},
+		GetCertificate: s.getCertificate,
+	}
+	if auth != tls.NoClientCert {
+		// 핸드셰이크마다 현재 CA 번들 적용 (재적재 반영)
+		s.base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := s.base.Clone()
+			c.GetConfigForClient = nil
+			c.ClientCAs = s.snapshot().clientCAs
+			return c, nil
+		}
+	}
+	if cfg.Reload > 0 {
+		go s.loop()
+	}
+	return s, nil
+}
+
+// TLSConfig: http.Server.TLSConfig 용 (ListenAndServeTLS("", ""))
+func (s *Server) TLSConfig() *tls.Config { return s.base }
+
+// Close: 재적재 중지
+func (s *Server) Close() {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+}
+
+func (s *Server) snapshot() *snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cur
+}
+
+func (s *Server) loop() {
+	t := time.NewTicker(s.cfg.Reload)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			if !s.changed() {
+				continue
+			}
+			next, err := s.load()
+			if err != nil {
+				s.log.Warn("reload failed, keep previous certificates", "err", err)
+				continue
+			}
+			s.mu.Lock()
+			s.cur = next
+			s.mu.Unlock()
+			s.log.Info("certificates reloaded", "certs", len(next.certs))
+		}
+	}
+}
+
+// changed: 적재 이후 파일 수정 시각이 바뀐 파일이 있는지
+func (s *Server) changed() bool {
+	for f, mt := range s.snapshot().modTimes {
+		if fi, err := os.Stat(f); err == nil && !fi.ModTime().Equal(mt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) load() (*snapshot, error) {
+	snap := &snapshot{byName: map[string]*tls.Certificate{}, modTimes: map[string]time.Time{}}
+	stamp := func(f string) {
+		if fi, err := os.Stat(f); err == nil {
+			snap.modTimes[f] = fi.ModTime()
+		}
+	}
+	for _, cf := range s.cfg.Certs {
+		stamp(cf.CertFile)
+		stamp(cf.KeyFile)
+		cert, err := tls.LoadX509KeyPair(cf.CertFile, cf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("server.tls.certs[%s]: %w", cf.CertFile, err)
+		}
+		if cert.Leaf == nil { // GODEBUG x509keypairleaf=0
+			if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+				return nil, fmt.Errorf("server.tls.certs[%s]: %w", cf.CertFile, err)
+			}
+		}
+		names := cf.ServerNames
+		if len(names) == 0 {
+			names = cert.Leaf.DNSNames
+			if len(names) == 0 && cert.Leaf.Subject.CommonName != "" {
+				names = []string{cert.Leaf.Subject.CommonName}
+			}
+		}
+		c := &cert
+		snap.certs = append(snap.certs, c)
+		for _, n := range names {
+			n = strings.ToLower(n)
+			if _, dup := snap.byName[n]; !dup { // 같은 이름은 먼저 나온 인증서 우선
+				snap.byName[n] = c
+			}
+		}
+	}
+	if s.cfg.ClientCAFile != "" {
+		stamp(s.cfg.ClientCAFile)
+		pool, err := LoadCertPool(s.cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("server.tls.client_ca_file: %w", err)
+		}
+		snap.clientCAs = pool
+	}
+	return snap, nil
+}
+
+// getCertificate: SNI 정확 일치 → 와일드카드 → 첫 인증서
+func (s *Server) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	snap := s.snapshot()
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if c, ok := snap.byName[name]; ok {
+		return c, nil
+	}
+	if _, rest, ok := strings.Cut(name, "."); ok {
+		if c, ok := snap.byName["*."+rest]; ok {
+			return c, nil
+		}
+	}
+	return snap.certs[0], nil
+}
+
+// ParseVersion: "1.0" | "1.1" | "1.2" | "1.3" (빈 값은 1.2)
+func ParseVersion(v string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(v), "tls") {
+	case "", "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version %q", v)
+}
+
+// ParseCipherSuites: 이름 → ID (취약 스위트는 거부)
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	known := map[string]uint16{}
+	for _, cs := range tls.CipherSuites() {
+		known[cs.Name] = cs.ID
+	}
+	out := make([]uint16, 0, len(names))
+	for _, n := range names {
+		id, ok := known[strings.ToUpper(n)]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", n)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// LoadCertPool: PEM CA 번들
+func LoadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%s: no PEM certificates", file)
+	}
+	return pool, nil
+}
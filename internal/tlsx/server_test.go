@@ -0,0 +1,237 @@
+package tlsx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert: PEM 로 쓸 수 있는 인증서 + 키
+type testCert struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	der  []byte
+}
+
+var serial, writes int64
+
+// newCert: parent 가 nil 이면 자체 서명 (isCA 면 CA 인증서)
+func newCert(t *testing.T, cn string, dns []string, isCA bool, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial++
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: cn},
+		DNSNames:              dns,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+	signerCert, signerKey := tmpl, crypto.Signer(key)
+	if parent != nil {
+		signerCert, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signerCert, key.Public(), signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: c, key: key, der: der}
+}
+
+// write: dir 에 <name>.crt / <name>.key 로 기록
+func (c *testCert) write(t *testing.T, dir, name string) CertFiles {
+	t.Helper()
+	keyDER, err := x509.MarshalPKCS8PrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf := CertFiles{CertFile: filepath.Join(dir, name+".crt"), KeyFile: filepath.Join(dir, name+".key")}
+	writeFile(t, cf.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}))
+	writeFile(t, cf.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	return cf
+}
+
+// writeFile: 기록 후 수정 시각을 앞당겨 재적재 감지가 파일시스템 시각 해상도에 좌우되지 않도록
+func writeFile(t *testing.T, path string, b []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writes++
+	mt := time.Now().Add(time.Duration(writes) * time.Second)
+	if err := os.Chtimes(path, mt, mt); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func served(t *testing.T, s *Server, sni string) *x509.Certificate {
+	t.Helper()
+	c, err := s.getCertificate(&tls.ClientHelloInfo{ServerName: sni})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.Leaf
+}
+
+func TestSNISelection(t *testing.T) {
+	dir := t.TempDir()
+	api := newCert(t, "api", []string{"api.example.com"}, false, nil)
+	wild := newCert(t, "wild", []string{"*.example.com"}, false, nil)
+	cnOnly := newCert(t, "legacy.example.net", nil, false, nil)
+	named := newCert(t, "named", []string{"ignored.example.org"}, false, nil)
+
+	namedFiles := named.write(t, dir, "named")
+	namedFiles.ServerNames = []string{"Other.Test"}
+	s, err := NewServer(ServerConfig{
+		Certs:  []CertFiles{api.write(t, dir, "api"), wild.write(t, dir, "wild"), cnOnly.write(t, dir, "cn"), namedFiles},
+		Reload: -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		sni  string
+		want *testCert
+	}{
+		{"api.example.com", api},
+		{"API.Example.Com.", api},        // 대소문자 / 끝 점 무시
+		{"www.example.com", wild},        // 와일드카드
+		{"a.b.example.com", api},         // 와일드카드는 한 단계만 → 첫 인증서
+		{"legacy.example.net", cnOnly},   // SAN 없으면 CN
+		{"other.test", named},            // server_names 지정
+		{"ignored.example.org", api},     // server_names 지정 시 SAN 미사용
+		{"", api},                        // SNI 없음 → 첫 인증서
+		{"unknown.example.invalid", api}, // 미매칭 → 첫 인증서
+	}
+	for _, c := range cases {
+		if got := served(t, s, c.sni); got.SerialNumber.Cmp(c.want.cert.SerialNumber) != 0 {
+			t.Errorf("SNI %q: got %s, want %s", c.sni, got.Subject.CommonName, c.want.cert.Subject.CommonName)
+		}
+	}
+}
+
+func TestNewServerInvalid(t *testing.T) {
+	dir := t.TempDir()
+	cf := newCert(t, "api", []string{"api.example.com"}, false, nil).write(t, dir, "api")
+	for _, c := range []struct {
+		name string
+		cfg  ServerConfig
+	}{
+		{"no certs", ServerConfig{}},
+		{"missing file", ServerConfig{Certs: []CertFiles{{CertFile: filepath.Join(dir, "none.crt"), KeyFile: cf.KeyFile}}}},
+		{"bad version", ServerConfig{Certs: []CertFiles{cf}, MinVersion: "1.4"}},
+		{"bad cipher", ServerConfig{Certs: []CertFiles{cf}, CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}},
+		{"bad client_auth", ServerConfig{Certs: []CertFiles{cf}, ClientAuth: "maybe"}},
+		{"client_auth without CA", ServerConfig{Certs: []CertFiles{cf}, ClientAuth: "require"}},
+	} {
+		c.cfg.Reload = -1
+		if _, err := NewServer(c.cfg); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+// waitFor: cond 가 참이 될 때까지 (재적재 주기 여러 번)
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+	first := newCert(t, "first", []string{"api.example.com"}, false, nil)
+	cf := first.write(t, dir, "api")
+	s, err := NewServer(ServerConfig{Certs: []CertFiles{cf}, Reload: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// 파일 교체 → 새 인증서
+	second := newCert(t, "second", []string{"api.example.com"}, false, nil)
+	second.write(t, dir, "api")
+	waitFor(t, "reload", func() bool {
+		return served(t, s, "api.example.com").SerialNumber.Cmp(second.cert.SerialNumber) == 0
+	})
+
+	// 깨진 파일 → 이전 인증서 유지
+	writeFile(t, cf.CertFile, []byte("not a certificate"))
+	time.Sleep(100 * time.Millisecond)
+	if got := served(t, s, "api.example.com"); got.SerialNumber.Cmp(second.cert.SerialNumber) != 0 {
+		t.Errorf("bad file replaced certificate: serving %s", got.Subject.CommonName)
+	}
+
+	// 복구되면 다시 적재
+	third := newCert(t, "third", []string{"api.example.com"}, false, nil)
+	third.write(t, dir, "api")
+	waitFor(t, "recovery", func() bool {
+		return served(t, s, "api.example.com").SerialNumber.Cmp(third.cert.SerialNumber) == 0
+	})
+}
+
+// GetConfigForClient 는 핸드셰이크마다 현재 CA 번들을 적용 (재적재 반영)
+func TestClientCAReload(t *testing.T) {
+	dir := t.TempDir()
+	ca1 := newCert(t, "ca1", nil, true, nil)
+	ca2 := newCert(t, "ca2", nil, true, nil)
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca1.der}))
+
+	s, err := NewServer(ServerConfig{
+		Certs:        []CertFiles{newCert(t, "srv", []string{"api.example.com"}, false, nil).write(t, dir, "srv")},
+		ClientAuth:   "require",
+		ClientCAFile: caFile,
+		Reload:       10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	pool := func(ca *testCert) *x509.CertPool {
+		p := x509.NewCertPool()
+		p.AddCert(ca.cert)
+		return p
+	}
+	current := func() *tls.Config {
+		c, err := s.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	if c := current(); c.ClientAuth != tls.RequireAndVerifyClientCert || !c.ClientCAs.Equal(pool(ca1)) || c.GetConfigForClient != nil {
+		t.Errorf("initial config: auth %v, CA ca1 %v", c.ClientAuth, c.ClientCAs.Equal(pool(ca1)))
+	}
+
+	writeFile(t, caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca2.der}))
+	waitFor(t, "CA reload", func() bool { return current().ClientCAs.Equal(pool(ca2)) })
+}